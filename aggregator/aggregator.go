@@ -12,6 +12,7 @@ import (
 	"github.com/vlifesystems/rhkit/goal"
 	"github.com/vlifesystems/rhkit/internal"
 	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+	"github.com/vlifesystems/rhkit/rule"
 )
 
 var (
@@ -23,9 +24,12 @@ type Aggregator interface {
 	MakeSpec(string, string) (Spec, error)
 }
 
+// Desc describes an aggregator.  Arg is only used if Args is empty, so
+// that descriptions from before Args was added can still be decoded.
 type Desc struct {
 	Name string
 	Kind string
+	Args []string
 	Arg  string
 }
 
@@ -33,7 +37,7 @@ type Spec interface {
 	New() Instance
 	Name() string
 	Kind() string
-	Arg() string
+	Args() []string
 }
 
 type Instance interface {
@@ -42,6 +46,50 @@ type Instance interface {
 	NextRecord(map[string]*dlit.Literal, bool) error
 }
 
+// RuleSetter is implemented by Instances that need to know which rule
+// they are aggregating for
+type RuleSetter interface {
+	SetRule(rule.Rule)
+}
+
+// Calculator is implemented by Instances whose result is calculated from
+// the results of other aggregators, so that InstancesToMap can pass them
+// the results that it has already found rather than each Instance
+// finding them again
+type Calculator interface {
+	// CalcResult returns the result of the Instance using the results of
+	// the aggregators that come before it
+	CalcResult(
+		results map[string]*dlit.Literal,
+		goals []*goal.Goal,
+		numRecords int64,
+	) *dlit.Literal
+}
+
+// MultiResultSpec is implemented by Specs whose Instances give further
+// named results as well as the result named after the Spec
+type MultiResultSpec interface {
+	// ResultNames returns the names of the further results
+	ResultNames() []string
+}
+
+// MultiResulter is implemented by Instances that give further named
+// results as well as the result named after the Instance
+type MultiResulter interface {
+	// Results returns the further results keyed by name
+	Results([]Instance, []*goal.Goal, int64) map[string]*dlit.Literal
+}
+
+// Bounder is implemented by Instances whose result can only move in one
+// direction as each record is processed, so that the range of results
+// that they could have at the end of a pass can be found part way
+// through it
+type Bounder interface {
+	// Bounds returns the minimum and maximum results that the Instance
+	// could have if numRemaining more records were to be processed
+	Bounds(numRemaining int64) (min float64, max float64)
+}
+
 // Register makes an Aggregator available by the provided kind.
 // If Register is called twice with the same kind or if
 // aggregator is nil, it panics.
@@ -59,7 +107,9 @@ func Register(kind string, aggregator Aggregator) {
 
 // Create a new Aggregator where 'name' is what the aggregator will be
 // known as, 'kind' is the name of the Aggregator as Registered,
-// 'args' are any arguments to pass to the Aggregator.
+// 'args' are any arguments to pass to the Aggregator.  If the Aggregator
+// is an ArgsAggregator then the arguments are validated against its
+// ArgDescs.
 func New(name string, kind string, args ...string) (Spec, error) {
 	var spec Spec
 	var err error
@@ -74,11 +124,8 @@ func New(name string, kind string, args ...string) (Spec, error) {
 		return nil, DescError{Name: name, Kind: kind, Err: ErrInvalidName}
 	}
 
-	if kind == "goalsscore" {
-		if len(args) != 0 {
-			return nil, DescError{Name: name, Kind: kind, Err: ErrInvalidNumArgs}
-		}
-		spec, err = aggregator.MakeSpec(name, "")
+	if a, ok := aggregator.(ArgsAggregator); ok {
+		spec, err = makeArgsSpec(a, name, args)
 	} else {
 		if len(args) != 1 {
 			return nil, DescError{Name: name, Kind: kind, Err: ErrInvalidNumArgs}
@@ -100,8 +147,10 @@ func MustNew(name string, kind string, args ...string) Spec {
 	return a
 }
 
-// InstancesToMap gets the results of each Instance and
-// returns the results as a map with the aggregatorSpec name as the key
+// InstancesToMap gets the results of each Instance, in order, and
+// returns the results as a map with the aggregatorSpec name as the key.
+// Each result is found once and if stopNames are given then it stops
+// at the first Instance with one of those names.
 func InstancesToMap(
 	Instances []Instance,
 	goals []*goal.Goal,
@@ -117,15 +166,44 @@ func InstancesToMap(
 				return r, nil
 			}
 		}
-		l := ai.Result(Instances, goals, numRecords)
+		var l *dlit.Literal
+		if c, ok := ai.(Calculator); ok {
+			l = c.CalcResult(r, goals, numRecords)
+		} else {
+			l = ai.Result(Instances, goals, numRecords)
+		}
 		if err := l.Err(); err != nil {
 			return r, err
 		}
 		r[ai.Name()] = l
+		if mr, ok := ai.(MultiResulter); ok {
+			for name, l := range mr.Results(Instances, goals, numRecords) {
+				if err := l.Err(); err != nil {
+					return r, err
+				}
+				r[name] = l
+			}
+		}
 	}
 	return r, nil
 }
 
+// ResultNames returns the names of the results given by the specs,
+// including any further results given by a MultiResultSpec
+func ResultNames(specs []Spec) []string {
+	r := make([]string, 0, len(specs))
+	for _, s := range specs {
+		r = append(r, s.Name())
+		if ms, ok := s.(MultiResultSpec); ok {
+			r = append(r, ms.ResultNames()...)
+		}
+	}
+	return r
+}
+
+// MakeSpecs creates the Specs from the descriptions, adds the default
+// aggregators and orders them so that each comes after the aggregators
+// that it depends on
 func MakeSpecs(
 	fields []string,
 	descs []*Desc,
@@ -136,12 +214,12 @@ func MakeSpecs(
 		if err = checkDescValid(fields, desc); err != nil {
 			return []Spec{}, err
 		}
-		r[i], err = New(desc.Name, desc.Kind, desc.Arg)
+		r[i], err = New(desc.Name, desc.Kind, desc.args()...)
 		if err != nil {
 			return []Spec{}, err
 		}
 	}
-	return addDefaultAggregators(r), nil
+	return Order(addDefaultAggregators(r))
 }
 
 func addDefaultAggregators(specs []Spec) []Spec {
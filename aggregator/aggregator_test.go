@@ -77,6 +77,15 @@ func TestNew_error(t *testing.T) {
 				Err:  ErrInvalidNumArgs,
 			},
 		},
+		{name: "a",
+			kind: "percentile",
+			args: []string{"balance"},
+			wantErr: DescError{
+				Name: "a",
+				Kind: "percentile",
+				Err:  ErrInvalidNumArgs,
+			},
+		},
 		{name: "a",
 			kind: "invalid",
 			args: []string{"3+4"},
@@ -202,11 +211,15 @@ func TestMakeSpecs(t *testing.T) {
 		"balance", "housing", "loan", "contact", "day", "month", "duration",
 		"campaign", "pdays", "previous", "y"}
 	desc := []*Desc{
-		{"num_married", "count", "marital == \"married\""},
-		{"numSignedUp", "count", "y == \"yes\""},
-		{"cost", "calc", "numMatches * 4.5"},
-		{"income", "calc", "numSignedUp * 24"},
-		{"profit", "calc", "income - cost"},
+		NewDesc("num_married", "count", "marital == \"married\""),
+		NewDesc("numSignedUp", "count", "y == \"yes\""),
+		NewDesc("cost", "calc", "numMatches * 4.5"),
+		NewDesc("income", "calc", "numSignedUp * 24"),
+		NewDesc("profit", "calc", "income - cost"),
+		NewDesc("complexity", "complexity"),
+		NewDesc("p90Balance", "percentile", "balance", "90"),
+		NewDesc("medianAge", "median", "age"),
+		NewDesc("scaledScore", "calc", "goalsScore * 10"),
 	}
 	want := []Spec{
 		MustNew("numMatches", "count", "true()"),
@@ -218,7 +231,11 @@ func TestMakeSpecs(t *testing.T) {
 		MustNew("cost", "calc", "numMatches * 4.5"),
 		MustNew("income", "calc", "numSignedUp * 24"),
 		MustNew("profit", "calc", "income - cost"),
+		MustNew("complexity", "complexity"),
+		MustNew("p90Balance", "percentile", "balance", "90"),
+		MustNew("medianAge", "median", "age"),
 		MustNew("goalsScore", "goalsscore"),
+		MustNew("scaledScore", "calc", "goalsScore * 10"),
 	}
 	got, err := MakeSpecs(fields, desc)
 	if err != nil {
@@ -239,12 +256,12 @@ func TestMakeSpecs_errors(t *testing.T) {
 		wantErr error
 	}{
 		{desc: []*Desc{
-			{"pdays", "count", "day > 2"},
+			NewDesc("pdays", "count", "day > 2"),
 		},
 			wantErr: DescError{Name: "pdays", Kind: "count", Err: ErrNameClash},
 		},
 		{desc: []*Desc{
-			{"numMatches", "count", "y == \"yes\""},
+			NewDesc("numMatches", "count", "y == \"yes\""),
 		},
 			wantErr: DescError{
 				Name: "numMatches",
@@ -253,7 +270,7 @@ func TestMakeSpecs_errors(t *testing.T) {
 			},
 		},
 		{desc: []*Desc{
-			{"percentMatches", "percent", "y == \"yes\""},
+			NewDesc("percentMatches", "percent", "y == \"yes\""),
 		},
 			wantErr: DescError{
 				Name: "percentMatches",
@@ -262,7 +279,7 @@ func TestMakeSpecs_errors(t *testing.T) {
 			},
 		},
 		{desc: []*Desc{
-			{"goalsScore", "count", "y == \"yes\""},
+			NewDesc("goalsScore", "count", "y == \"yes\""),
 		},
 			wantErr: DescError{
 				Name: "goalsScore",
@@ -271,7 +288,7 @@ func TestMakeSpecs_errors(t *testing.T) {
 			},
 		},
 		{desc: []*Desc{
-			{"3numSignedUp", "count", "y == \"yes\""},
+			NewDesc("3numSignedUp", "count", "y == \"yes\""),
 		},
 			wantErr: DescError{
 				Name: "3numSignedUp",
@@ -280,7 +297,7 @@ func TestMakeSpecs_errors(t *testing.T) {
 			},
 		},
 		{desc: []*Desc{
-			{"num-signed-up", "count", "y == \"yes\""},
+			NewDesc("num-signed-up", "count", "y == \"yes\""),
 		},
 			wantErr: DescError{
 				Name: "num-signed-up",
@@ -289,7 +306,7 @@ func TestMakeSpecs_errors(t *testing.T) {
 			},
 		},
 		{desc: []*Desc{
-			{"something", "nothing", "y == \"yes\""},
+			NewDesc("something", "nothing", "y == \"yes\""),
 		},
 			wantErr: DescError{
 				Name: "something",
@@ -297,6 +314,26 @@ func TestMakeSpecs_errors(t *testing.T) {
 				Err:  ErrUnregisteredKind,
 			},
 		},
+		{desc: []*Desc{
+			NewDesc("cost", "calc", "numMatches * price"),
+		},
+			wantErr: DescError{
+				Name: "cost",
+				Kind: "calc",
+				Err:  UndeclaredError("price"),
+			},
+		},
+		{desc: []*Desc{
+			NewDesc("cost", "calc", "income - profit"),
+			NewDesc("income", "calc", "numMatches * 24"),
+			NewDesc("profit", "calc", "income - cost"),
+		},
+			wantErr: DescError{
+				Name: "cost",
+				Kind: "calc",
+				Err:  CycleError{"cost", "profit", "cost"},
+			},
+		},
 	}
 	for i, c := range cases {
 		_, err := MakeSpecs(fields, c.desc)
@@ -358,7 +395,7 @@ func areAggregatorsEqual(a1 []Spec, a2 []Spec) bool {
 	for i, a := range a1 {
 		if reflect.TypeOf(a) != reflect.TypeOf(a2[i]) ||
 			a.Name() != a2[i].Name() ||
-			a.Arg() != a2[i].Arg() {
+			!reflect.DeepEqual(a.Args(), a2[i].Args()) {
 			return false
 		}
 	}
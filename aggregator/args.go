@@ -0,0 +1,175 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/vlifesystems/rhkit/internal"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// ArgsAggregator is implemented by Aggregators that take a list of
+// typed arguments.  The arguments are validated against ArgDescs before
+// being passed to MakeSpecArgs.
+type ArgsAggregator interface {
+	Aggregator
+	// ArgDescs describes the arguments that the Aggregator takes
+	ArgDescs() []ArgDesc
+	// MakeSpecArgs creates a Spec from the name and validated arguments
+	MakeSpecArgs(name string, args []Arg) (Spec, error)
+}
+
+// ArgKind is the kind of value that an argument takes
+type ArgKind int
+
+const (
+	// ExprArg is an expression
+	ExprArg ArgKind = iota
+	// NumberArg is a number
+	NumberArg
+	// ChoiceArg is one of a set of words
+	ChoiceArg
+)
+
+// ArgDesc describes an argument taken by an ArgsAggregator
+type ArgDesc struct {
+	Name string
+	Kind ArgKind
+	// Optional arguments must come after any that aren't optional and if
+	// they aren't supplied then Default is used
+	Optional bool
+	Default  string
+	// Choices are the words allowed for a ChoiceArg
+	Choices []string
+	// Valid, if set, reports whether the number passed to a NumberArg
+	// is in range
+	Valid func(float64) bool
+}
+
+// Arg is a validated argument
+type Arg struct {
+	str    string
+	expr   *dexpr.Expr
+	number float64
+}
+
+// ArgError indicates that an argument passed to an aggregator is invalid
+type ArgError struct {
+	Arg   string
+	Value string
+	Err   error
+}
+
+func (e ArgError) Error() string {
+	return fmt.Sprintf("invalid argument - %s: %s (%s)", e.Arg, e.Value, e.Err)
+}
+
+// String returns the argument as it was passed
+func (a Arg) String() string {
+	return a.str
+}
+
+// Expr returns the expression of an ExprArg
+func (a Arg) Expr() *dexpr.Expr {
+	return a.expr
+}
+
+// Number returns the number of a NumberArg
+func (a Arg) Number() float64 {
+	return a.number
+}
+
+// NewDesc returns a Desc with the arguments given
+func NewDesc(name string, kind string, args ...string) *Desc {
+	return &Desc{Name: name, Kind: kind, Args: args}
+}
+
+// args returns the arguments of the Desc.  If Args is empty then Arg is
+// used so that descriptions with a single argument can still be decoded.
+func (d *Desc) args() []string {
+	if len(d.Args) > 0 {
+		return d.Args
+	}
+	return singleArg(d.Arg)
+}
+
+// singleArg returns arg as a list of arguments for aggregators created
+// with MakeSpec, or an empty list if arg is empty
+func singleArg(arg string) []string {
+	if arg == "" {
+		return []string{}
+	}
+	return []string{arg}
+}
+
+// makeArgsSpec validates the arguments and passes them to the
+// ArgsAggregator to make a Spec
+func makeArgsSpec(
+	a ArgsAggregator,
+	name string,
+	args []string,
+) (Spec, error) {
+	validArgs, err := parseArgs(a.ArgDescs(), args)
+	if err != nil {
+		return nil, err
+	}
+	return a.MakeSpecArgs(name, validArgs)
+}
+
+// parseArgs checks that the arguments match the ArgDescs and returns
+// them as Args, with defaults for optional arguments not supplied
+func parseArgs(descs []ArgDesc, args []string) ([]Arg, error) {
+	numRequired := 0
+	for _, d := range descs {
+		if !d.Optional {
+			numRequired++
+		}
+	}
+	if len(args) < numRequired || len(args) > len(descs) {
+		return []Arg{}, ErrInvalidNumArgs
+	}
+	r := make([]Arg, len(descs))
+	for i, d := range descs {
+		s := d.Default
+		if i < len(args) {
+			s = strings.TrimSpace(args[i])
+		}
+		arg, err := parseArg(d, s)
+		if err != nil {
+			return []Arg{}, err
+		}
+		r[i] = arg
+	}
+	return r, nil
+}
+
+func parseArg(d ArgDesc, s string) (Arg, error) {
+	arg := Arg{str: s}
+	switch d.Kind {
+	case ExprArg:
+		expr, err := dexpr.New(s, dexprfuncs.CallFuncs)
+		if err != nil {
+			return Arg{}, err
+		}
+		arg.expr = expr
+	case NumberArg:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Arg{}, ArgError{Arg: d.Name, Value: s, Err: ErrNotNumber}
+		}
+		if d.Valid != nil && !d.Valid(n) {
+			return Arg{}, ArgError{Arg: d.Name, Value: s, Err: ErrOutOfRange}
+		}
+		arg.number = n
+	case ChoiceArg:
+		if !internal.IsStringInSlice(s, d.Choices) {
+			return Arg{}, ArgError{Arg: d.Name, Value: s, Err: ErrInvalidChoice}
+		}
+	}
+	return arg, nil
+}
@@ -0,0 +1,188 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"github.com/lawrencewoodman/dexpr"
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	descs := []ArgDesc{
+		{Name: "expr", Kind: ExprArg},
+		{Name: "weight",
+			Kind:  NumberArg,
+			Valid: func(n float64) bool { return n > 0 },
+		},
+		{Name: "mode",
+			Kind:     ChoiceArg,
+			Optional: true,
+			Default:  "fast",
+			Choices:  []string{"fast", "slow"},
+		},
+	}
+	cases := []struct {
+		args       []string
+		wantStrs   []string
+		wantNumber float64
+	}{
+		{args: []string{"a + b", "2.5"},
+			wantStrs:   []string{"a + b", "2.5", "fast"},
+			wantNumber: 2.5,
+		},
+		{args: []string{" a + b", " 3 ", " slow "},
+			wantStrs:   []string{"a + b", "3", "slow"},
+			wantNumber: 3,
+		},
+	}
+	for i, c := range cases {
+		got, err := parseArgs(descs, c.args)
+		if err != nil {
+			t.Errorf("(%d) parseArgs: %s", i, err)
+			continue
+		}
+		gotStrs := make([]string, len(got))
+		for j, a := range got {
+			gotStrs[j] = a.String()
+		}
+		if !reflect.DeepEqual(gotStrs, c.wantStrs) {
+			t.Errorf("(%d) parseArgs got: %v, want: %v", i, gotStrs, c.wantStrs)
+		}
+		if got[0].Expr() == nil || got[0].Expr().String() != "a + b" {
+			t.Errorf("(%d) parseArgs got Expr: %v, want: a + b", i, got[0].Expr())
+		}
+		if got[1].Number() != c.wantNumber {
+			t.Errorf("(%d) parseArgs got Number: %f, want: %f",
+				i, got[1].Number(), c.wantNumber)
+		}
+	}
+}
+
+func TestParseArgs_errors(t *testing.T) {
+	descs := []ArgDesc{
+		{Name: "expr", Kind: ExprArg},
+		{Name: "weight",
+			Kind:  NumberArg,
+			Valid: func(n float64) bool { return n > 0 },
+		},
+		{Name: "mode",
+			Kind:     ChoiceArg,
+			Optional: true,
+			Default:  "fast",
+			Choices:  []string{"fast", "slow"},
+		},
+	}
+	cases := []struct {
+		args    []string
+		wantErr error
+	}{
+		{args: []string{"a + b"}, wantErr: ErrInvalidNumArgs},
+		{args: []string{"a + b", "2", "fast", "4"}, wantErr: ErrInvalidNumArgs},
+		{args: []string{"a + {", "2"},
+			wantErr: dexpr.InvalidExprError{Expr: "a + {", Err: dexpr.ErrSyntax},
+		},
+		{args: []string{"a + b", "heavy"},
+			wantErr: ArgError{Arg: "weight", Value: "heavy", Err: ErrNotNumber},
+		},
+		{args: []string{"a + b", "0"},
+			wantErr: ArgError{Arg: "weight", Value: "0", Err: ErrOutOfRange},
+		},
+		{args: []string{"a + b", "2", "medium"},
+			wantErr: ArgError{Arg: "mode", Value: "medium", Err: ErrInvalidChoice},
+		},
+	}
+	for i, c := range cases {
+		_, err := parseArgs(descs, c.args)
+		if err == nil || err.Error() != c.wantErr.Error() {
+			t.Errorf("(%d) parseArgs err: %v, wantErr: %v", i, err, c.wantErr)
+		}
+	}
+}
+
+func TestArgDescs(t *testing.T) {
+	exprArgDescs := []ArgDesc{{Name: "expr", Kind: ExprArg}}
+	cases := []struct {
+		kind string
+		want []ArgDesc
+	}{
+		{kind: "calc", want: exprArgDescs},
+		{kind: "count", want: exprArgDescs},
+		{kind: "f1", want: exprArgDescs},
+		{kind: "lift", want: exprArgDescs},
+		{kind: "complexity", want: []ArgDesc{}},
+		{kind: "goalsdistance", want: []ArgDesc{}},
+		{kind: "goalsscore", want: []ArgDesc{}},
+	}
+	for _, c := range cases {
+		a, ok := aggregators[c.kind].(ArgsAggregator)
+		if !ok {
+			t.Errorf("%s isn't an ArgsAggregator", c.kind)
+			continue
+		}
+		if got := a.ArgDescs(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s ArgDescs() got: %v, want: %v", c.kind, got, c.want)
+		}
+	}
+}
+
+func TestDescArgs(t *testing.T) {
+	cases := []struct {
+		desc *Desc
+		want []string
+	}{
+		{desc: &Desc{Name: "a", Kind: "calc", Arg: "in(x, 1, 2)"},
+			want: []string{"in(x, 1, 2)"},
+		},
+		{desc: &Desc{Name: "a", Kind: "calc", Arg: ""}, want: []string{}},
+		{desc: &Desc{Name: "a", Kind: "goalsscore"}, want: []string{}},
+		{desc: &Desc{Name: "a", Kind: "percentile", Args: []string{"x", "90"}},
+			want: []string{"x", "90"},
+		},
+		{desc: &Desc{
+			Name: "a",
+			Kind: "percentile",
+			Args: []string{"balance", "90"},
+			Arg:  "cost",
+		},
+			want: []string{"balance", "90"},
+		},
+		{desc: NewDesc("a", "percentile", "in(x, 1, 2)", "90", "approx"),
+			want: []string{"in(x, 1, 2)", "90", "approx"},
+		},
+		{desc: NewDesc("a", "count", "y == \"yes\""),
+			want: []string{"y == \"yes\""},
+		},
+	}
+	for _, c := range cases {
+		got := c.desc.args()
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("args() - desc: %v, got: %q, want: %q", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestDescArgs_json(t *testing.T) {
+	cases := []struct {
+		json string
+		want []string
+	}{
+		{json: `{"name": "a", "kind": "calc", "arg": "3 + 4"}`,
+			want: []string{"3 + 4"},
+		},
+		{json: `{"name": "a", "kind": "percentile", "args": ["balance", "90"]}`,
+			want: []string{"balance", "90"},
+		},
+		{json: `{"name": "a", "kind": "goalsscore"}`, want: []string{}},
+	}
+	for _, c := range cases {
+		var desc Desc
+		if err := json.Unmarshal([]byte(c.json), &desc); err != nil {
+			t.Errorf("Unmarshal(%s) err: %s", c.json, err)
+			continue
+		}
+		got := desc.args()
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("args() - json: %s, got: %q, want: %q", c.json, got, c.want)
+		}
+	}
+}
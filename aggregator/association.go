@@ -0,0 +1,97 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// associationSpec is used by the association rule aggregators: support,
+// confidence, lift, leverage and conviction.  These treat the rule as
+// the antecedent and the expression as the consequent and measure the
+// rule relative to the base rate of the consequent over all the records.
+// see: https://en.wikipedia.org/wiki/Association_rule_learning
+type associationSpec struct {
+	name    string
+	kind    string
+	expr    *dexpr.Expr
+	measure func(confusionMatrix) *dlit.Literal
+}
+
+type associationInstance struct {
+	spec *associationSpec
+	cm   confusionMatrix
+}
+
+// These are calculated using dexprs, in terms of the counts of the
+// confusion matrix, because this easily handles errors and
+// overflow/underflow errors.  The base rate is: (tp+fn)/n and the
+// coverage is: (tp+fp)/n, where n is: tp+fp+tn+fn.
+var (
+	supportExpr = dexpr.MustNew("tp/(tp+fp+tn+fn)", dexprfuncs.CallFuncs)
+	liftExpr    = dexpr.MustNew(
+		"(tp*(tp+fp+tn+fn))/((tp+fp)*(tp+fn))",
+		dexprfuncs.CallFuncs,
+	)
+	leverageExpr = dexpr.MustNew(
+		"tp/(tp+fp+tn+fn)-((tp+fp)*(tp+fn))/((tp+fp+tn+fn)*(tp+fp+tn+fn))",
+		dexprfuncs.CallFuncs,
+	)
+	convictionExpr = dexpr.MustNew(
+		"((fp+tn)*(tp+fp))/(fp*(tp+fp+tn+fn))",
+		dexprfuncs.CallFuncs,
+	)
+)
+
+func newAssociationSpec(
+	name string,
+	kind string,
+	expr *dexpr.Expr,
+	measure func(confusionMatrix) *dlit.Literal,
+) Spec {
+	return &associationSpec{
+		name:    name,
+		kind:    kind,
+		expr:    expr,
+		measure: measure,
+	}
+}
+
+func (ad *associationSpec) New() Instance {
+	return &associationInstance{spec: ad}
+}
+
+func (ad *associationSpec) Name() string {
+	return ad.name
+}
+
+func (ad *associationSpec) Kind() string {
+	return ad.kind
+}
+
+func (ad *associationSpec) Args() []string {
+	return []string{ad.expr.String()}
+}
+
+func (ai *associationInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *associationInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return ai.cm.next(ai.spec.expr, record, isRuleTrue)
+}
+
+func (ai *associationInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	return ai.spec.measure(ai.cm)
+}
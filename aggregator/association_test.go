@@ -0,0 +1,209 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+var associationKinds = []string{
+	"support",
+	"confidence",
+	"lift",
+	"leverage",
+	"conviction",
+}
+
+func TestAssociationResult(t *testing.T) {
+	records := makeConfusionRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		rule func(int) bool
+		want map[string]float64
+	}{
+		{rule: func(i int) bool { return i <= 2 || i == 4 || i == 5 },
+			want: map[string]float64{
+				"support":    0.3,
+				"confidence": 0.6,
+				"lift":       1.5,
+				"leverage":   0.1,
+				"conviction": 1.5,
+			},
+		},
+		{rule: func(i int) bool { return i < 4 },
+			want: map[string]float64{
+				"support":    0.4,
+				"confidence": 1,
+				"lift":       2.5,
+				"leverage":   0.24,
+				"conviction": 1000000,
+			},
+		},
+		{rule: func(i int) bool { return i >= 4 },
+			want: map[string]float64{
+				"support":    0,
+				"confidence": 0,
+				"lift":       0,
+				"leverage":   -0.24,
+				"conviction": 0.6,
+			},
+		},
+		{rule: func(i int) bool { return true },
+			want: map[string]float64{
+				"support":    0.4,
+				"confidence": 0.4,
+				"lift":       1,
+				"leverage":   0,
+				"conviction": 1,
+			},
+		},
+		{rule: func(i int) bool { return false },
+			want: map[string]float64{
+				"support":    0,
+				"confidence": 0,
+				"lift":       0,
+				"leverage":   0,
+				"conviction": 0,
+			},
+		},
+	}
+	for ci, c := range cases {
+		for _, kind := range associationKinds {
+			ai := MustNew(kind, kind, "y == \"yes\"").New()
+			instances := []Instance{ai}
+
+			for i, record := range records {
+				if err := ai.NextRecord(record, c.rule(i)); err != nil {
+					t.Fatalf("(%d) %s NextRecord: %s", ci, kind, err)
+				}
+			}
+			numRecords := int64(len(records))
+			got := ai.Result(instances, goals, numRecords)
+			gotFloat, gotIsFloat := got.Float()
+			if !gotIsFloat || gotFloat != c.want[kind] {
+				t.Errorf("(%d) %s Result, got: %v, want: %f",
+					ci, kind, got, c.want[kind])
+			}
+		}
+	}
+}
+
+func TestAssociationResult_noRecords(t *testing.T) {
+	for _, kind := range associationKinds {
+		ai := MustNew("a", kind, "y == \"yes\"").New()
+		got := ai.Result([]Instance{ai}, []*goal.Goal{}, 0)
+		if got.String() != "0" {
+			t.Errorf("%s Result, got: %v, want: 0", kind, got)
+		}
+	}
+}
+
+func TestAssociationResult_goals(t *testing.T) {
+	records := makeConfusionRecords()
+	instances := []Instance{
+		MustNew("lift", "lift", "y == \"yes\"").New(),
+		MustNew("leverage", "leverage", "y == \"yes\"").New(),
+		MustNew("goalsScore", "goalsscore").New(),
+	}
+	goals := []*goal.Goal{
+		goal.MustNew("lift > 1.2"),
+		goal.MustNew("leverage >= 0.2"),
+	}
+	for i, record := range records {
+		isRuleTrue := i <= 2 || i == 4 || i == 5
+		for _, ai := range instances {
+			if err := ai.NextRecord(record, isRuleTrue); err != nil {
+				t.Fatalf("NextRecord: %s", err)
+			}
+		}
+	}
+	want := map[string]*dlit.Literal{
+		"numRecords": dlit.MustNew(10),
+		"lift":       dlit.MustNew(1.5),
+		"leverage":   dlit.MustNew(0.1),
+		"goalsScore": dlit.MustNew(1),
+	}
+	got, err := InstancesToMap(instances, goals, int64(len(records)))
+	if err != nil {
+		t.Fatalf("InstancesToMap: %s", err)
+	}
+	if !doAggregatorMapsMatch(got, want) {
+		t.Errorf("InstancesToMap got: %s, want: %s", got, want)
+	}
+}
+
+func TestConvictionResult_calc(t *testing.T) {
+	records := makeConfusionRecords()
+	instances := []Instance{
+		MustNew("conviction", "conviction", "y == \"yes\"").New(),
+		MustNew("doubleConviction", "calc", "conviction * 2").New(),
+	}
+	for i, record := range records {
+		for _, ai := range instances {
+			if err := ai.NextRecord(record, i < 4); err != nil {
+				t.Fatalf("NextRecord: %s", err)
+			}
+		}
+	}
+	want := map[string]*dlit.Literal{
+		"numRecords":       dlit.MustNew(10),
+		"conviction":       dlit.MustNew(1000000),
+		"doubleConviction": dlit.MustNew(2000000),
+	}
+	got, err := InstancesToMap(instances, []*goal.Goal{}, int64(len(records)))
+	if err != nil {
+		t.Fatalf("InstancesToMap: %s", err)
+	}
+	if !doAggregatorMapsMatch(got, want) {
+		t.Errorf("InstancesToMap got: %s, want: %s", got, want)
+	}
+}
+
+func TestNewAssociation_error(t *testing.T) {
+	for _, kind := range associationKinds {
+		_, err := New("a", kind, "3>4{")
+		wantErr := DescError{
+			Name: "a",
+			Kind: kind,
+			Err:  dexpr.InvalidExprError{Expr: "3>4{", Err: dexpr.ErrSyntax},
+		}
+		if err == nil || err.Error() != wantErr.Error() {
+			t.Errorf("New: gotErr: %s, wantErr: %s", err, wantErr)
+		}
+	}
+}
+
+func TestAssociationNextRecord_error(t *testing.T) {
+	for _, kind := range associationKinds {
+		ai := MustNew("a", kind, "cost > 2").New()
+		record := map[string]*dlit.Literal{}
+		got := ai.NextRecord(record, true)
+		want := dexpr.InvalidExprError{
+			Expr: "cost > 2",
+			Err:  dexpr.VarNotExistError("cost"),
+		}
+		if got == nil || got.Error() != want.Error() {
+			t.Errorf("%s NextRecord: got: %s, want: %s", kind, got, want)
+		}
+	}
+}
+
+func TestAssociationSpec(t *testing.T) {
+	for _, kind := range associationKinds {
+		as := MustNew("abc", kind, "cost > 2")
+		if got := as.Name(); got != "abc" {
+			t.Errorf("Name - got: %s, want: abc", got)
+		}
+		if got := as.Kind(); got != kind {
+			t.Errorf("Kind - got: %s, want: %s", got, kind)
+		}
+		if got := as.Args(); !reflect.DeepEqual(got, []string{"cost > 2"}) {
+			t.Errorf("Args - got: %q, want: [\"cost > 2\"]", got)
+		}
+		if got := as.New().Name(); got != "abc" {
+			t.Errorf("Instance Name - got: %s, want: abc", got)
+		}
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
-	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
 )
 
 type calcAggregator struct{}
@@ -27,15 +26,22 @@ func init() {
 
 func (a *calcAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *calcAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *calcAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
-	dexpr, err := dexpr.New(expr, dexprfuncs.CallFuncs)
-	if err != nil {
-		return nil, err
-	}
 	d := &calcSpec{
 		name: name,
-		expr: dexpr,
+		expr: args[0].Expr(),
 	}
 	return d, nil
 }
@@ -52,8 +58,14 @@ func (ad *calcSpec) Kind() string {
 	return "calc"
 }
 
-func (ad *calcSpec) Arg() string {
-	return ad.expr.String()
+func (ad *calcSpec) Args() []string {
+	return []string{ad.expr.String()}
+}
+
+// Dependencies returns the names of the aggregators used in the
+// expression
+func (ad *calcSpec) Dependencies() []string {
+	return exprVars(ad.expr.String())
 }
 
 func (ai *calcInstance) Name() string {
@@ -77,5 +89,13 @@ func (ai *calcInstance) Result(
 	if err != nil {
 		return dlit.MustNew(err)
 	}
-	return ai.spec.expr.Eval(instancesMap)
+	return ai.CalcResult(instancesMap, goals, numRecords)
+}
+
+func (ai *calcInstance) CalcResult(
+	results map[string]*dlit.Literal,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	return ai.spec.expr.Eval(results)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
 	"testing"
 )
 
@@ -87,11 +88,11 @@ func TestCalcSpecKind(t *testing.T) {
 	}
 }
 
-func TestCalcSpecArg(t *testing.T) {
-	arg := "3+4"
-	as := MustNew("a", "calc", arg)
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+func TestCalcSpecArgs(t *testing.T) {
+	want := []string{"3+4"}
+	as := MustNew("a", "calc", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
@@ -0,0 +1,90 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+// complexityAggregator reports the complexity score of the rule being
+// assessed as given by rule.Complexity
+type complexityAggregator struct{}
+
+type complexitySpec struct {
+	name string
+}
+
+type complexityInstance struct {
+	spec       *complexitySpec
+	complexity int
+	isRuleSet  bool
+}
+
+func init() {
+	Register("complexity", &complexityAggregator{})
+}
+
+func (a *complexityAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *complexityAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{}
+}
+
+func (a *complexityAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	d := &complexitySpec{name: name}
+	return d, nil
+}
+
+func (ad *complexitySpec) New() Instance {
+	return &complexityInstance{spec: ad}
+}
+
+func (ad *complexitySpec) Name() string {
+	return ad.name
+}
+
+func (ad *complexitySpec) Kind() string {
+	return "complexity"
+}
+
+func (ad *complexitySpec) Args() []string {
+	return []string{}
+}
+
+func (ai *complexityInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *complexityInstance) SetRule(r rule.Rule) {
+	ai.complexity = rule.Complexity(r)
+	ai.isRuleSet = true
+}
+
+func (ai *complexityInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return nil
+}
+
+func (ai *complexityInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	if !ai.isRuleSet {
+		return dlit.MustNew(ErrRuleNotSet)
+	}
+	return dlit.MustNew(ai.complexity)
+}
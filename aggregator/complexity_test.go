@@ -0,0 +1,79 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/rule"
+	"reflect"
+	"testing"
+)
+
+func TestComplexitySpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "complexity")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestComplexitySpecKind(t *testing.T) {
+	kind := "complexity"
+	as := MustNew("a", kind)
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestComplexitySpecArgs(t *testing.T) {
+	want := []string{}
+	as := MustNew("a", "complexity")
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
+	}
+}
+
+func TestComplexityNextRecord(t *testing.T) {
+	as := MustNew("a", "complexity")
+	ai := as.New()
+	record := map[string]*dlit.Literal{}
+	got := ai.NextRecord(record, true)
+	if got != nil {
+		t.Errorf("NextRecord: got: %s, want: nil", got)
+	}
+}
+
+func TestComplexityResult(t *testing.T) {
+	cases := []struct {
+		rule rule.Rule
+		want *dlit.Literal
+	}{
+		{rule: rule.NewTrue(), want: dlit.MustNew(0)},
+		{rule: rule.NewEQFV("band", dlit.MustNew(4)), want: dlit.MustNew(1)},
+		{rule: rule.NewGEFF("band", "cost"), want: dlit.MustNew(2)},
+		{rule: rule.NewMulLEF("band", "cost", dlit.MustNew(4)),
+			want: dlit.MustNew(3)},
+	}
+	numRecords := int64(12)
+	for i, c := range cases {
+		as := MustNew("complexity", "complexity")
+		ai := as.New()
+		ai.(RuleSetter).SetRule(c.rule)
+		got := ai.Result([]Instance{ai}, []*goal.Goal{}, numRecords)
+		if got.String() != c.want.String() {
+			t.Errorf("(%d) Result: got: %s, want: %s", i, got, c.want)
+		}
+	}
+}
+
+func TestComplexityResult_rule_not_set(t *testing.T) {
+	as := MustNew("complexity", "complexity")
+	ai := as.New()
+	want := dlit.MustNew(ErrRuleNotSet)
+	got := ai.Result([]Instance{ai}, []*goal.Goal{}, 12)
+	if got.String() != want.String() {
+		t.Errorf("Result: got: %s, want: %s", got, want)
+	}
+}
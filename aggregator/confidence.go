@@ -0,0 +1,45 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+)
+
+// confidenceAggregator gives the proportion of the records matched by a
+// rule for which the consequent expression is true.
+type confidenceAggregator struct{}
+
+func init() {
+	Register("confidence", &confidenceAggregator{})
+}
+
+func (a *confidenceAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *confidenceAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *confidenceAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	return newAssociationSpec(
+		name,
+		"confidence",
+		args[0].Expr(),
+		confidence,
+	), nil
+}
+
+// confidence returns tp/(tp+fp), which is 0 if the rule matches no
+// records
+func confidence(cm confusionMatrix) *dlit.Literal {
+	return cm.precision()
+}
@@ -0,0 +1,261 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// confusionAggregator keeps a confusion matrix of a rule against a
+// target expression.  Its result is the number of true positives and it
+// also gives the results listed in confusionResults, named after the
+// aggregator with an underscore and the result, such as: cm_specificity
+// see: https://en.wikipedia.org/wiki/Confusion_matrix
+type confusionAggregator struct{}
+
+type confusionSpec struct {
+	name string
+	expr *dexpr.Expr
+}
+
+type confusionInstance struct {
+	spec *confusionSpec
+	cm   confusionMatrix
+}
+
+// confusionMatrix counts how often a rule and a target expression
+// agree and disagree
+type confusionMatrix struct {
+	tp int64
+	fp int64
+	tn int64
+	fn int64
+}
+
+// confusionResults are the further results given by the confusion
+// aggregator
+var confusionResults = []string{
+	"tp",
+	"fp",
+	"tn",
+	"fn",
+	"specificity",
+	"npv",
+	"accuracy",
+	"balancedAccuracy",
+	"fpr",
+	"informedness",
+	"markedness",
+}
+
+// These are calculated using dexprs because this easily handles errors
+// and overflow/underflow errors
+var (
+	precisionExpr   = dexpr.MustNew("tp/(tp+fp)", dexprfuncs.CallFuncs)
+	recallExpr      = dexpr.MustNew("tp/(tp+fn)", dexprfuncs.CallFuncs)
+	specificityExpr = dexpr.MustNew("tn/(tn+fp)", dexprfuncs.CallFuncs)
+	npvExpr         = dexpr.MustNew("tn/(tn+fn)", dexprfuncs.CallFuncs)
+	accuracyExpr    = dexpr.MustNew(
+		"(tp+tn)/(tp+fp+tn+fn)",
+		dexprfuncs.CallFuncs,
+	)
+	balancedAccuracyExpr = dexpr.MustNew(
+		"(tp/(tp+fn)+tn/(tn+fp))/2",
+		dexprfuncs.CallFuncs,
+	)
+	fprExpr          = dexpr.MustNew("fp/(fp+tn)", dexprfuncs.CallFuncs)
+	informednessExpr = dexpr.MustNew(
+		"tp/(tp+fn)+tn/(tn+fp)-1",
+		dexprfuncs.CallFuncs,
+	)
+	markednessExpr = dexpr.MustNew(
+		"tp/(tp+fp)+tn/(tn+fn)-1",
+		dexprfuncs.CallFuncs,
+	)
+)
+
+func init() {
+	Register("confusion", &confusionAggregator{})
+}
+
+func (a *confusionAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *confusionAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *confusionAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	d := &confusionSpec{
+		name: name,
+		expr: args[0].Expr(),
+	}
+	return d, nil
+}
+
+func (ad *confusionSpec) New() Instance {
+	return &confusionInstance{spec: ad}
+}
+
+func (ad *confusionSpec) Name() string {
+	return ad.name
+}
+
+func (ad *confusionSpec) Kind() string {
+	return "confusion"
+}
+
+func (ad *confusionSpec) Args() []string {
+	return []string{ad.expr.String()}
+}
+
+func (ad *confusionSpec) ResultNames() []string {
+	r := make([]string, len(confusionResults))
+	for i, result := range confusionResults {
+		r[i] = ad.name + "_" + result
+	}
+	return r
+}
+
+func (ai *confusionInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *confusionInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return ai.cm.next(ai.spec.expr, record, isRuleTrue)
+}
+
+func (ai *confusionInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	return dlit.MustNew(ai.cm.tp)
+}
+
+func (ai *confusionInstance) Results(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) map[string]*dlit.Literal {
+	cm := ai.cm
+	values := []*dlit.Literal{
+		dlit.MustNew(cm.tp),
+		dlit.MustNew(cm.fp),
+		dlit.MustNew(cm.tn),
+		dlit.MustNew(cm.fn),
+		cm.specificity(),
+		cm.npv(),
+		cm.accuracy(),
+		cm.balancedAccuracy(),
+		cm.fpr(),
+		cm.informedness(),
+		cm.markedness(),
+	}
+	names := ai.spec.ResultNames()
+	r := make(map[string]*dlit.Literal, len(names))
+	for i, name := range names {
+		r[name] = values[i]
+	}
+	return r
+}
+
+// next evaluates the target expression for the record and counts it
+// against whether the rule is true
+func (cm *confusionMatrix) next(
+	expr *dexpr.Expr,
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	matchExprIsTrue, err := expr.EvalBool(record)
+	if err != nil {
+		return err
+	}
+	if matchExprIsTrue {
+		if isRuleTrue {
+			cm.tp++
+		} else {
+			cm.fn++
+		}
+	} else {
+		if isRuleTrue {
+			cm.fp++
+		} else {
+			cm.tn++
+		}
+	}
+	return nil
+}
+
+func (cm confusionMatrix) precision() *dlit.Literal {
+	return cm.eval(precisionExpr, cm.tp+cm.fp)
+}
+
+func (cm confusionMatrix) recall() *dlit.Literal {
+	return cm.eval(recallExpr, cm.tp+cm.fn)
+}
+
+func (cm confusionMatrix) specificity() *dlit.Literal {
+	return cm.eval(specificityExpr, cm.tn+cm.fp)
+}
+
+func (cm confusionMatrix) npv() *dlit.Literal {
+	return cm.eval(npvExpr, cm.tn+cm.fn)
+}
+
+func (cm confusionMatrix) accuracy() *dlit.Literal {
+	return cm.eval(accuracyExpr, cm.tp+cm.fp+cm.tn+cm.fn)
+}
+
+// balancedAccuracy, informedness and markedness are 0 if either of the
+// rates that they combine can't be found because it would divide by 0
+func (cm confusionMatrix) balancedAccuracy() *dlit.Literal {
+	return cm.eval(balancedAccuracyExpr, cm.tp+cm.fn, cm.tn+cm.fp)
+}
+
+func (cm confusionMatrix) fpr() *dlit.Literal {
+	return cm.eval(fprExpr, cm.fp+cm.tn)
+}
+
+func (cm confusionMatrix) informedness() *dlit.Literal {
+	return cm.eval(informednessExpr, cm.tp+cm.fn, cm.tn+cm.fp)
+}
+
+func (cm confusionMatrix) markedness() *dlit.Literal {
+	return cm.eval(markednessExpr, cm.tp+cm.fp, cm.tn+cm.fn)
+}
+
+// eval returns the result of expr, using the counts of the matrix as
+// its variables, rounded to 4 decimal places.  It returns 0 if any of
+// the divisors are 0.
+func (cm confusionMatrix) eval(
+	expr *dexpr.Expr,
+	divisors ...int64,
+) *dlit.Literal {
+	for _, d := range divisors {
+		if d == 0 {
+			return dlit.MustNew(0)
+		}
+	}
+	vars := map[string]*dlit.Literal{
+		"tp": dlit.MustNew(cm.tp),
+		"fp": dlit.MustNew(cm.fp),
+		"tn": dlit.MustNew(cm.tn),
+		"fn": dlit.MustNew(cm.fn),
+	}
+	return roundTo(expr.Eval(vars), 4)
+}
@@ -0,0 +1,203 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestConfusionResults(t *testing.T) {
+	records := makeConfusionRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		rule func(int) bool
+		want map[string]*dlit.Literal
+	}{
+		{rule: func(i int) bool { return i <= 2 || i == 4 || i == 5 },
+			want: map[string]*dlit.Literal{
+				"cm":                  dlit.MustNew(3),
+				"cm_tp":               dlit.MustNew(3),
+				"cm_fp":               dlit.MustNew(2),
+				"cm_tn":               dlit.MustNew(4),
+				"cm_fn":               dlit.MustNew(1),
+				"cm_specificity":      dlit.MustNew(0.6667),
+				"cm_npv":              dlit.MustNew(0.8),
+				"cm_accuracy":         dlit.MustNew(0.7),
+				"cm_balancedAccuracy": dlit.MustNew(0.7083),
+				"cm_fpr":              dlit.MustNew(0.3333),
+				"cm_informedness":     dlit.MustNew(0.4167),
+				"cm_markedness":       dlit.MustNew(0.4),
+			},
+		},
+		{rule: func(i int) bool { return true },
+			want: map[string]*dlit.Literal{
+				"cm":                  dlit.MustNew(4),
+				"cm_tp":               dlit.MustNew(4),
+				"cm_fp":               dlit.MustNew(6),
+				"cm_tn":               dlit.MustNew(0),
+				"cm_fn":               dlit.MustNew(0),
+				"cm_specificity":      dlit.MustNew(0),
+				"cm_npv":              dlit.MustNew(0),
+				"cm_accuracy":         dlit.MustNew(0.4),
+				"cm_balancedAccuracy": dlit.MustNew(0.5),
+				"cm_fpr":              dlit.MustNew(1),
+				"cm_informedness":     dlit.MustNew(0),
+				"cm_markedness":       dlit.MustNew(0),
+			},
+		},
+		{rule: func(i int) bool { return false },
+			want: map[string]*dlit.Literal{
+				"cm":                  dlit.MustNew(0),
+				"cm_tp":               dlit.MustNew(0),
+				"cm_fp":               dlit.MustNew(0),
+				"cm_tn":               dlit.MustNew(6),
+				"cm_fn":               dlit.MustNew(4),
+				"cm_specificity":      dlit.MustNew(1),
+				"cm_npv":              dlit.MustNew(0.6),
+				"cm_accuracy":         dlit.MustNew(0.6),
+				"cm_balancedAccuracy": dlit.MustNew(0.5),
+				"cm_fpr":              dlit.MustNew(0),
+				"cm_informedness":     dlit.MustNew(0),
+				"cm_markedness":       dlit.MustNew(0),
+			},
+		},
+	}
+	for ci, c := range cases {
+		cm := MustNew("cm", "confusion", "y == \"yes\"").New()
+		instances := []Instance{cm}
+
+		for i, record := range records {
+			if err := cm.NextRecord(record, c.rule(i)); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", ci, err)
+			}
+		}
+		numRecords := int64(len(records))
+		got, err := InstancesToMap(instances, goals, numRecords)
+		if err != nil {
+			t.Fatalf("(%d) InstancesToMap: %s", ci, err)
+		}
+		c.want["numRecords"] = dlit.MustNew(numRecords)
+		if !doAggregatorMapsMatch(got, c.want) {
+			t.Errorf("(%d) InstancesToMap got: %v, want: %v", ci, got, c.want)
+		}
+	}
+}
+
+func TestConfusionResults_noRecords(t *testing.T) {
+	cm := MustNew("cm", "confusion", "y == \"yes\"").New()
+	got := cm.Result([]Instance{cm}, []*goal.Goal{}, 0)
+	if got.String() != "0" {
+		t.Errorf("Result, got: %v, want: 0", got)
+	}
+	results := cm.(MultiResulter).Results([]Instance{cm}, []*goal.Goal{}, 0)
+	for name, l := range results {
+		if l.String() != "0" {
+			t.Errorf("Results, got: %s: %v, want: 0", name, l)
+		}
+	}
+}
+
+func TestConfusionNextRecord_error(t *testing.T) {
+	as := MustNew("a", "confusion", "cost > 2")
+	ai := as.New()
+	record := map[string]*dlit.Literal{}
+	got := ai.NextRecord(record, true)
+	want := dexpr.InvalidExprError{
+		Expr: "cost > 2",
+		Err:  dexpr.VarNotExistError("cost"),
+	}
+	if got == nil || got.Error() != want.Error() {
+		t.Errorf("NextRecord: got: %s, want: %s", got, want)
+	}
+}
+
+func TestResultNames(t *testing.T) {
+	specs := []Spec{
+		MustNew("a", "count", "true()"),
+		MustNew("cm", "confusion", "y == \"yes\""),
+	}
+	want := []string{
+		"a",
+		"cm",
+		"cm_tp",
+		"cm_fp",
+		"cm_tn",
+		"cm_fn",
+		"cm_specificity",
+		"cm_npv",
+		"cm_accuracy",
+		"cm_balancedAccuracy",
+		"cm_fpr",
+		"cm_informedness",
+		"cm_markedness",
+	}
+	got := ResultNames(specs)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResultNames got: %v, want: %v", got, want)
+	}
+}
+
+func TestOrder_confusion(t *testing.T) {
+	specs := []Spec{
+		MustNew("share", "calc", "cm_tp / (cm_tp + cm_fn)"),
+		MustNew("cm", "confusion", "y == \"yes\""),
+	}
+	got, err := Order(specs)
+	if err != nil {
+		t.Fatalf("Order: %s", err)
+	}
+	if len(got) != 2 || got[0].Name() != "cm" || got[1].Name() != "share" {
+		t.Errorf("Order got: %v, want: cm before share", got)
+	}
+}
+
+func TestOrder_confusionDuplicateName(t *testing.T) {
+	specs := []Spec{
+		MustNew("cm", "confusion", "y == \"yes\""),
+		MustNew("cm_tp", "count", "true()"),
+	}
+	wantErr := DescError{Name: "cm_tp", Kind: "count", Err: ErrDuplicateName}
+	_, err := Order(specs)
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Errorf("Order err: %v, wantErr: %v", err, wantErr)
+	}
+}
+
+func TestConfusionSpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "confusion", "cost > 2")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestConfusionSpecKind(t *testing.T) {
+	kind := "confusion"
+	as := MustNew("a", kind, "cost > 2")
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestConfusionSpecArgs(t *testing.T) {
+	want := []string{"cost > 2"}
+	as := MustNew("a", "confusion", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
+	}
+}
+
+func TestConfusionInstanceName(t *testing.T) {
+	as := MustNew("abc", "confusion", "cost > 2")
+	ai := as.New()
+	got := ai.Name()
+	want := "abc"
+	if got != want {
+		t.Errorf("Name: got: %s, want: %s", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+)
+
+// convictionAggregator gives the ratio of how often a rule would be
+// expected to be true while the consequent expression is false, if they
+// were independent, to how often this is seen.
+type convictionAggregator struct{}
+
+// maxConviction is the largest conviction given.  It is given when the
+// confidence is 1, as the conviction would otherwise be infinite, and is
+// small enough to be used in further calculations, such as by calc.
+const maxConviction = 1000000
+
+func init() {
+	Register("conviction", &convictionAggregator{})
+}
+
+func (a *convictionAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *convictionAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *convictionAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	return newAssociationSpec(
+		name,
+		"conviction",
+		args[0].Expr(),
+		conviction,
+	), nil
+}
+
+// conviction returns (1-baseRate)/(1-confidence), which is 0 if the
+// rule matches no records.  The conviction is limited to maxConviction,
+// which is returned if the confidence is 1 as the conviction would
+// otherwise be infinite.
+func conviction(cm confusionMatrix) *dlit.Literal {
+	if cm.tp+cm.fp == 0 {
+		return dlit.MustNew(0)
+	}
+	if cm.fp == 0 {
+		return dlit.MustNew(maxConviction)
+	}
+	l := cm.eval(convictionExpr, cm.tp+cm.fp+cm.tn+cm.fn)
+	if f, ok := l.Float(); ok && f > maxConviction {
+		return dlit.MustNew(maxConviction)
+	}
+	return l
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
-	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
 )
 
 type countAggregator struct{}
@@ -28,15 +27,22 @@ func init() {
 
 func (a *countAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *countAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *countAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
-	dexpr, err := dexpr.New(expr, dexprfuncs.CallFuncs)
-	if err != nil {
-		return nil, err
-	}
 	d := &countSpec{
 		name: name,
-		expr: dexpr,
+		expr: args[0].Expr(),
 	}
 	return d, nil
 }
@@ -56,8 +62,8 @@ func (ad *countSpec) Kind() string {
 	return "count"
 }
 
-func (ad *countSpec) Arg() string {
-	return ad.expr.String()
+func (ad *countSpec) Args() []string {
+	return []string{ad.expr.String()}
 }
 
 func (ai *countInstance) Name() string {
@@ -78,6 +84,10 @@ func (ai *countInstance) NextRecord(
 	return nil
 }
 
+func (ai *countInstance) Bounds(numRemaining int64) (float64, float64) {
+	return float64(ai.numMatches), float64(ai.numMatches + numRemaining)
+}
+
 func (ai *countInstance) Result(
 	aggregatorInstances []Instance,
 	goals []*goal.Goal,
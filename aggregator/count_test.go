@@ -4,6 +4,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
 	"testing"
 )
 
@@ -48,6 +49,40 @@ func TestCountResult(t *testing.T) {
 	}
 }
 
+func TestCountBounds(t *testing.T) {
+	records := []map[string]*dlit.Literal{
+		{"band": dlit.MustNew(4)},
+		{"band": dlit.MustNew(7)},
+		{"band": dlit.MustNew(4)},
+		{"band": dlit.MustNew(6)},
+		{"band": dlit.MustNew(9)},
+	}
+	cases := []struct {
+		numProcessed int
+		wantMin      float64
+		wantMax      float64
+	}{
+		{numProcessed: 0, wantMin: 0, wantMax: 5},
+		{numProcessed: 2, wantMin: 1, wantMax: 4},
+		{numProcessed: 4, wantMin: 1, wantMax: 2},
+		{numProcessed: 5, wantMin: 2, wantMax: 2},
+	}
+	for i, c := range cases {
+		ai := MustNew("numBandGt4", "count", "band > 4").New()
+		for j, record := range records[:c.numProcessed] {
+			if err := ai.NextRecord(record, j != 3); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", i, err)
+			}
+		}
+		numRemaining := int64(len(records) - c.numProcessed)
+		gotMin, gotMax := ai.(Bounder).Bounds(numRemaining)
+		if gotMin != c.wantMin || gotMax != c.wantMax {
+			t.Errorf("(%d) Bounds - got: (%f, %f), want: (%f, %f)",
+				i, gotMin, gotMax, c.wantMin, c.wantMax)
+		}
+	}
+}
+
 func TestCountNextRecord_error(t *testing.T) {
 	as := MustNew("a", "count", "cost > 2")
 	ai := as.New()
@@ -80,12 +115,12 @@ func TestCountSpecKind(t *testing.T) {
 	}
 }
 
-func TestCountSpecArg(t *testing.T) {
-	arg := "band > 4"
-	as := MustNew("a", "count", arg)
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+func TestCountSpecArgs(t *testing.T) {
+	want := []string{"band > 4"}
+	as := MustNew("a", "count", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
 
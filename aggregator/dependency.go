@@ -0,0 +1,227 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"go/ast"
+	"go/parser"
+	"sort"
+	"strings"
+
+	"github.com/vlifesystems/rhkit/internal"
+)
+
+// Dependent is implemented by Specs whose results are calculated from
+// the results of other aggregators
+type Dependent interface {
+	// Dependencies returns the names of the aggregators that the
+	// Spec's result is calculated from
+	Dependencies() []string
+}
+
+// goalsKinds are the kinds of Aggregator whose results are calculated
+// by assessing the goals against the results of the other aggregators
+var goalsKinds = []string{"goalsdistance", "goalsscore"}
+
+// UndeclaredError indicates that an aggregator refers to an aggregator
+// that hasn't been declared
+type UndeclaredError string
+
+func (e UndeclaredError) Error() string {
+	return "undeclared aggregator: " + string(e)
+}
+
+// CycleError indicates that aggregators depend on each other in a cycle
+type CycleError []string
+
+func (e CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e, " -> ")
+}
+
+// Order returns the specs ordered so that each one comes after the
+// aggregators that it depends on.  Where there is a choice the
+// original order is kept.  Aggregators of goals kinds depend on every
+// aggregator that doesn't itself depend on one of goals kinds.
+func Order(specs []Spec) ([]Spec, error) {
+	dependencies, err := findDependencies(specs)
+	if err != nil {
+		return []Spec{}, err
+	}
+	numDependencies := make([]int, len(specs))
+	dependents := make([][]int, len(specs))
+	for i, deps := range dependencies {
+		numDependencies[i] = len(deps)
+		for _, j := range deps {
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	r := make([]Spec, 0, len(specs))
+	done := make([]bool, len(specs))
+	for len(r) < len(specs) {
+		next := -1
+		for i := range specs {
+			if !done[i] && numDependencies[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return []Spec{}, cycleError(specs, dependencies, done)
+		}
+		done[next] = true
+		r = append(r, specs[next])
+		for _, i := range dependents[next] {
+			numDependencies[i]--
+		}
+	}
+	return r, nil
+}
+
+// findDependencies returns the indices of the specs that each spec
+// depends on
+func findDependencies(specs []Spec) ([][]int, error) {
+	indices := make(map[string]int, len(specs))
+	for i, s := range specs {
+		names := []string{s.Name()}
+		if ms, ok := s.(MultiResultSpec); ok {
+			names = append(names, ms.ResultNames()...)
+		}
+		for _, name := range names {
+			if _, ok := indices[name]; ok {
+				return [][]int{}, DescError{
+					Name: s.Name(),
+					Kind: s.Kind(),
+					Err:  ErrDuplicateName,
+				}
+			}
+			indices[name] = i
+		}
+	}
+	r := make([][]int, len(specs))
+	for i, s := range specs {
+		d, ok := s.(Dependent)
+		if !ok {
+			continue
+		}
+		for _, name := range d.Dependencies() {
+			j, ok := indices[name]
+			if !ok {
+				return [][]int{}, DescError{
+					Name: s.Name(),
+					Kind: s.Kind(),
+					Err:  UndeclaredError(name),
+				}
+			}
+			r[i] = append(r[i], j)
+		}
+	}
+
+	usesGoals := make([]bool, len(specs))
+	for i, s := range specs {
+		if internal.IsStringInSlice(s.Kind(), goalsKinds) {
+			markUsesGoals(i, r, usesGoals)
+		}
+	}
+	for i, s := range specs {
+		if !internal.IsStringInSlice(s.Kind(), goalsKinds) {
+			continue
+		}
+		for j := range specs {
+			if !usesGoals[j] {
+				r[i] = append(r[i], j)
+			}
+		}
+	}
+	return r, nil
+}
+
+// markUsesGoals marks spec i and every spec that depends on it as using
+// the goals
+func markUsesGoals(i int, dependencies [][]int, usesGoals []bool) {
+	if usesGoals[i] {
+		return
+	}
+	usesGoals[i] = true
+	for j, deps := range dependencies {
+		for _, d := range deps {
+			if d == i {
+				markUsesGoals(j, dependencies, usesGoals)
+				break
+			}
+		}
+	}
+}
+
+// cycleError returns a DescError describing a dependency cycle between
+// the specs that haven't been ordered
+func cycleError(specs []Spec, dependencies [][]int, done []bool) error {
+	start := 0
+	for done[start] {
+		start++
+	}
+	// Follow unordered dependencies until one is visited twice, as
+	// every unordered spec has at least one unordered dependency
+	visited := make(map[int]int, len(specs))
+	path := []int{}
+	for i := start; ; {
+		if pos, ok := visited[i]; ok {
+			path = append(path[pos:], i)
+			break
+		}
+		visited[i] = len(path)
+		path = append(path, i)
+		for _, j := range dependencies[i] {
+			if !done[j] {
+				i = j
+				break
+			}
+		}
+	}
+	names := make([]string, len(path))
+	for k, i := range path {
+		names[k] = specs[i].Name()
+	}
+	s := specs[path[0]]
+	return DescError{Name: s.Name(), Kind: s.Kind(), Err: CycleError(names)}
+}
+
+// exprVars returns the sorted names of the variables in an expression
+// apart from numRecords, true and false
+func exprVars(expr string) []string {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return []string{}
+	}
+	vars := map[string]bool{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		return inspectVar(n, vars)
+	})
+	r := make([]string, 0, len(vars))
+	for name := range vars {
+		r = append(r, name)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// inspectVar records n in vars if it is a variable and returns whether
+// to inspect its children, skipping the names of called functions
+func inspectVar(n ast.Node, vars map[string]bool) bool {
+	switch x := n.(type) {
+	case *ast.CallExpr:
+		for _, arg := range x.Args {
+			ast.Inspect(arg, func(n ast.Node) bool {
+				return inspectVar(n, vars)
+			})
+		}
+		return false
+	case *ast.Ident:
+		switch x.Name {
+		case "numRecords", "true", "false":
+		default:
+			vars[x.Name] = true
+		}
+	}
+	return true
+}
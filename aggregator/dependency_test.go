@@ -0,0 +1,187 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestOrder(t *testing.T) {
+	cases := []struct {
+		specs []Spec
+		want  []string
+	}{
+		{specs: []Spec{
+			MustNew("a", "count", "true()"),
+			MustNew("b", "calc", "a * 2"),
+		},
+			want: []string{"a", "b"},
+		},
+		{specs: []Spec{
+			MustNew("profit", "calc", "income - cost"),
+			MustNew("cost", "calc", "numMatches * 4.5"),
+			MustNew("income", "calc", "numSignedUp * 24"),
+			MustNew("numSignedUp", "count", "y == \"yes\""),
+			MustNew("numMatches", "count", "true()"),
+		},
+			want: []string{"numSignedUp", "income", "numMatches", "cost", "profit"},
+		},
+		{specs: []Spec{
+			MustNew("goalsScore", "goalsscore"),
+			MustNew("scaled", "calc", "goalsScore * numMatches"),
+			MustNew("numMatches", "count", "true()"),
+			MustNew("goalsDistance", "goalsdistance"),
+			MustNew("percentMatches", "calc", "100.0 * numMatches / numRecords"),
+		},
+			want: []string{
+				"numMatches",
+				"percentMatches",
+				"goalsScore",
+				"scaled",
+				"goalsDistance",
+			},
+		},
+	}
+	for i, c := range cases {
+		got, err := Order(c.specs)
+		if err != nil {
+			t.Errorf("(%d) Order: %s", i, err)
+			continue
+		}
+		gotNames := make([]string, len(got))
+		for j, s := range got {
+			gotNames[j] = s.Name()
+		}
+		if !reflect.DeepEqual(gotNames, c.want) {
+			t.Errorf("(%d) Order got: %v, want: %v", i, gotNames, c.want)
+		}
+	}
+}
+
+func TestOrder_errors(t *testing.T) {
+	cases := []struct {
+		specs   []Spec
+		wantErr error
+	}{
+		{specs: []Spec{
+			MustNew("a", "count", "true()"),
+			MustNew("b", "calc", "a + c"),
+		},
+			wantErr: DescError{
+				Name: "b",
+				Kind: "calc",
+				Err:  UndeclaredError("c"),
+			},
+		},
+		{specs: []Spec{
+			MustNew("a", "calc", "a + 1"),
+		},
+			wantErr: DescError{
+				Name: "a",
+				Kind: "calc",
+				Err:  CycleError{"a", "a"},
+			},
+		},
+		{specs: []Spec{
+			MustNew("a", "count", "true()"),
+			MustNew("b", "calc", "a + d"),
+			MustNew("c", "calc", "b * 2"),
+			MustNew("d", "calc", "c - 1"),
+		},
+			wantErr: DescError{
+				Name: "b",
+				Kind: "calc",
+				Err:  CycleError{"b", "d", "c", "b"},
+			},
+		},
+	}
+	for i, c := range cases {
+		_, err := Order(c.specs)
+		if err == nil || err.Error() != c.wantErr.Error() {
+			t.Errorf("(%d) Order err: %v, wantErr: %v", i, err, c.wantErr)
+		}
+	}
+}
+
+func TestInstancesToMap_resultsFoundOnce(t *testing.T) {
+	a := &countingLitInstance{name: "a", result: "3"}
+	instances := []Instance{
+		a,
+		MustNew("b", "calc", "a * 2").New(),
+		MustNew("c", "calc", "b + a").New(),
+		MustNew("d", "calc", "c + b").New(),
+		MustNew("goalsScore", "goalsscore").New(),
+	}
+	goals := []*goal.Goal{goal.MustNew("d > 10"), goal.MustNew("c > 10")}
+	want := map[string]*dlit.Literal{
+		"numRecords": dlit.MustNew(12),
+		"a":          dlit.MustNew(3),
+		"b":          dlit.MustNew(6),
+		"c":          dlit.MustNew(9),
+		"d":          dlit.MustNew(15),
+		"goalsScore": dlit.MustNew(1),
+	}
+	got, err := InstancesToMap(instances, goals, 12)
+	if err != nil {
+		t.Fatalf("InstancesToMap: %s", err)
+	}
+	if !doAggregatorMapsMatch(got, want) {
+		t.Errorf("InstancesToMap got: %s, want: %s", got, want)
+	}
+	if a.numResults != 1 {
+		t.Errorf("InstancesToMap - Result called: %d times, want: 1",
+			a.numResults)
+	}
+}
+
+func TestExprVars(t *testing.T) {
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{expr: "3 + 4", want: []string{}},
+		{expr: "income - cost", want: []string{"cost", "income"}},
+		{expr: "numMatches / numRecords", want: []string{"numMatches"}},
+		{expr: "a + a * b", want: []string{"a", "b"}},
+		{expr: "iferr(roundto(100.0 * numMatches / numRecords, 2), 0)",
+			want: []string{"numMatches"},
+		},
+		{expr: "in(a, b, \"c\") && true", want: []string{"a", "b"}},
+		{expr: "3+4+{", want: []string{}},
+	}
+	for _, c := range cases {
+		got := exprVars(c.expr)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("exprVars(%s) got: %v, want: %v", c.expr, got, c.want)
+		}
+	}
+}
+
+// countingLitInstance is a LitInstance that counts how many times
+// Result is called
+type countingLitInstance struct {
+	name       string
+	result     string
+	numResults int
+}
+
+func (ci *countingLitInstance) Name() string {
+	return ci.name
+}
+
+func (ci *countingLitInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return nil
+}
+
+func (ci *countingLitInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	ci.numResults++
+	return dlit.MustNew(ci.result)
+}
@@ -22,6 +22,11 @@ var (
 	ErrInvalidName      = errors.New("invalid name")
 	ErrNameClash        = errors.New("name clashes with field name")
 	ErrNameReserved     = errors.New("name reserved")
+	ErrRuleNotSet       = errors.New("rule not set")
+	ErrNotNumber        = errors.New("value isn't a number")
+	ErrOutOfRange       = errors.New("out of range")
+	ErrInvalidChoice    = errors.New("invalid choice")
+	ErrDuplicateName    = errors.New("duplicate name")
 )
 
 func (e DescError) Error() string {
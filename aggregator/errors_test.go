@@ -10,3 +10,12 @@ func TestDescErrorError(t *testing.T) {
 		t.Errorf("Error() got: %s, want: %s", got, want)
 	}
 }
+
+func TestArgErrorError(t *testing.T) {
+	e := ArgError{Arg: "percentile", Value: "101", Err: ErrOutOfRange}
+	want := "invalid argument - percentile: 101 (out of range)"
+	got := e.Error()
+	if got != want {
+		t.Errorf("Error() got: %s, want: %s", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+// f1Aggregator gives the F1 score of a rule against a target expression,
+// which is the harmonic mean of precision and recall.  This is the
+// F-beta score with a beta of 1.
+type f1Aggregator struct{}
+
+func init() {
+	Register("f1", &f1Aggregator{})
+}
+
+func (a *f1Aggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *f1Aggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *f1Aggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	d := &fbetaSpec{
+		name: name,
+		kind: "f1",
+		expr: args[0].Expr(),
+		beta: 1,
+	}
+	return d, nil
+}
@@ -0,0 +1,73 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestNewF1_error(t *testing.T) {
+	_, err := New("a", "f1", "3>4{")
+	wantErr := DescError{
+		Name: "a",
+		Kind: "f1",
+		Err: dexpr.InvalidExprError{
+			Expr: "3>4{",
+			Err:  dexpr.ErrSyntax,
+		},
+	}.Error()
+	if err.Error() != wantErr {
+		t.Errorf("New: gotErr: %s, wantErr: %s", err, wantErr)
+	}
+}
+
+func TestF1Result(t *testing.T) {
+	records := makeConfusionRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		rule func(int) bool
+		want float64
+	}{
+		{func(i int) bool { return i <= 2 || i == 4 || i == 5 }, 0.6667},
+		{func(i int) bool { return i < 4 }, 1},
+		{func(i int) bool { return true }, 0.5714},
+		{func(i int) bool { return i >= 4 }, 0},
+		{func(i int) bool { return false }, 0},
+	}
+	for ci, c := range cases {
+		f1Desc := MustNew("f1", "f1", "y == \"yes\"")
+		f1 := f1Desc.New()
+		instances := []Instance{f1}
+
+		for i, record := range records {
+			if err := f1.NextRecord(record, c.rule(i)); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", ci, err)
+			}
+		}
+		numRecords := int64(len(records))
+		got := f1.Result(instances, goals, numRecords)
+		gotFloat, gotIsFloat := got.Float()
+		if !gotIsFloat || gotFloat != c.want {
+			t.Errorf("(%d) - Result, got: %v, want: %f", ci, got, c.want)
+		}
+	}
+}
+
+func TestF1SpecKind(t *testing.T) {
+	kind := "f1"
+	as := MustNew("a", kind, "cost > 2")
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestF1SpecArgs(t *testing.T) {
+	want := []string{"cost > 2"}
+	as := MustNew("a", "f1", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
+	}
+}
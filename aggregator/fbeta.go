@@ -0,0 +1,127 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"strconv"
+
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// fbetaAggregator gives the F-beta score of a rule against a target
+// expression, which weights recall beta times as much as precision.
+// It takes the target expression and beta, which must be above 0.
+// see: https://en.wikipedia.org/wiki/F1_score
+type fbetaAggregator struct{}
+
+type fbetaSpec struct {
+	name string
+	kind string
+	expr *dexpr.Expr
+	beta float64
+}
+
+type fbetaInstance struct {
+	spec *fbetaSpec
+	cm   confusionMatrix
+}
+
+// This is calculated using a dexpr because this easily handles errors and
+// overflow/underflow errors
+var fbetaExpr = dexpr.MustNew(
+	"((1+beta*beta)*tp)/((1+beta*beta)*tp+beta*beta*fn+fp)",
+	dexprfuncs.CallFuncs,
+)
+
+func init() {
+	Register("fbeta", &fbetaAggregator{})
+}
+
+func (a *fbetaAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *fbetaAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{
+		{Name: "expr", Kind: ExprArg},
+		{Name: "beta",
+			Kind:  NumberArg,
+			Valid: func(beta float64) bool { return beta > 0 },
+		},
+	}
+}
+
+func (a *fbetaAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	d := &fbetaSpec{
+		name: name,
+		kind: "fbeta",
+		expr: args[0].Expr(),
+		beta: args[1].Number(),
+	}
+	return d, nil
+}
+
+func (ad *fbetaSpec) New() Instance {
+	return &fbetaInstance{
+		spec: ad,
+	}
+}
+
+func (ad *fbetaSpec) Name() string {
+	return ad.name
+}
+
+func (ad *fbetaSpec) Kind() string {
+	return ad.kind
+}
+
+func (ad *fbetaSpec) Args() []string {
+	if ad.kind == "f1" {
+		return []string{ad.expr.String()}
+	}
+	return []string{
+		ad.expr.String(),
+		strconv.FormatFloat(ad.beta, 'f', -1, 64),
+	}
+}
+
+func (ai *fbetaInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *fbetaInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return ai.cm.next(ai.spec.expr, record, isRuleTrue)
+}
+
+func (ai *fbetaInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	// If there are no true positives the score is 0, which also avoids
+	// dividing by zero when there are no positives at all
+	if ai.cm.tp == 0 {
+		return dlit.MustNew(0)
+	}
+
+	vars := map[string]*dlit.Literal{
+		"tp":   dlit.MustNew(ai.cm.tp),
+		"fp":   dlit.MustNew(ai.cm.fp),
+		"fn":   dlit.MustNew(ai.cm.fn),
+		"beta": dlit.MustNew(ai.spec.beta),
+	}
+	return roundTo(fbetaExpr.Eval(vars), 4)
+}
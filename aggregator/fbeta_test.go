@@ -0,0 +1,141 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestNewFbeta_errors(t *testing.T) {
+	cases := []struct {
+		args    []string
+		wantErr error
+	}{
+		{args: []string{"y == \"yes\""}, wantErr: ErrInvalidNumArgs},
+		{args: []string{"y == \"yes\"", "0"},
+			wantErr: ArgError{Arg: "beta", Value: "0", Err: ErrOutOfRange},
+		},
+		{args: []string{"y == \"yes\"", "two"},
+			wantErr: ArgError{Arg: "beta", Value: "two", Err: ErrNotNumber},
+		},
+		{args: []string{"3>4{", "2"},
+			wantErr: dexpr.InvalidExprError{Expr: "3>4{", Err: dexpr.ErrSyntax},
+		},
+	}
+	for i, c := range cases {
+		_, err := New("a", "fbeta", c.args...)
+		wantErr := DescError{Name: "a", Kind: "fbeta", Err: c.wantErr}
+		if err == nil || err.Error() != wantErr.Error() {
+			t.Errorf("(%d) New: gotErr: %s, wantErr: %s", i, err, wantErr)
+		}
+	}
+}
+
+func TestFbetaResult(t *testing.T) {
+	records := makeConfusionRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		beta string
+		rule func(int) bool
+		want float64
+	}{
+		{"1", func(i int) bool { return i <= 2 || i == 4 || i == 5 }, 0.6667},
+		{"2", func(i int) bool { return i <= 2 || i == 4 || i == 5 }, 0.7143},
+		{"0.5", func(i int) bool { return i <= 2 || i == 4 || i == 5 }, 0.625},
+		{"2", func(i int) bool { return true }, 0.7692},
+		{"2", func(i int) bool { return i >= 4 }, 0},
+		{"2", func(i int) bool { return false }, 0},
+	}
+	for ci, c := range cases {
+		fbetaDesc := MustNew("fbeta", "fbeta", "y == \"yes\"", c.beta)
+		fbeta := fbetaDesc.New()
+		instances := []Instance{fbeta}
+
+		for i, record := range records {
+			if err := fbeta.NextRecord(record, c.rule(i)); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", ci, err)
+			}
+		}
+		numRecords := int64(len(records))
+		got := fbeta.Result(instances, goals, numRecords)
+		gotFloat, gotIsFloat := got.Float()
+		if !gotIsFloat || gotFloat != c.want {
+			t.Errorf("(%d) - Result, got: %v, want: %f", ci, got, c.want)
+		}
+	}
+}
+
+func TestFbetaResult_noRecords(t *testing.T) {
+	fbeta := MustNew("fbeta", "fbeta", "y == \"yes\"", "2").New()
+	got := fbeta.Result([]Instance{fbeta}, []*goal.Goal{}, 0)
+	if got.String() != "0" {
+		t.Errorf("Result, got: %v, want: 0", got)
+	}
+}
+
+func TestFbetaNextRecord_error(t *testing.T) {
+	as := MustNew("a", "fbeta", "cost > 2", "2")
+	ai := as.New()
+	record := map[string]*dlit.Literal{}
+	got := ai.NextRecord(record, true)
+	want := dexpr.InvalidExprError{
+		Expr: "cost > 2",
+		Err:  dexpr.VarNotExistError("cost"),
+	}
+	if got == nil || got.Error() != want.Error() {
+		t.Errorf("NextRecord: got: %s, want: %s", got, want)
+	}
+}
+
+func TestFbetaSpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "fbeta", "cost > 2", "2")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestFbetaSpecKind(t *testing.T) {
+	kind := "fbeta"
+	as := MustNew("a", kind, "cost > 2", "2")
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestFbetaSpecArgs(t *testing.T) {
+	want := []string{"cost > 2", "0.5"}
+	as := MustNew("a", "fbeta", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
+	}
+}
+
+func TestFbetaInstanceName(t *testing.T) {
+	as := MustNew("abc", "fbeta", "cost > 2", "2")
+	ai := as.New()
+	got := ai.Name()
+	want := "abc"
+	if got != want {
+		t.Errorf("Name: got: %s, want: %s", got, want)
+	}
+}
+
+// makeConfusionRecords returns records where the first four have a
+// y of "yes" and the remaining six have a y of "no"
+func makeConfusionRecords() []map[string]*dlit.Literal {
+	records := make([]map[string]*dlit.Literal, 10)
+	for i := range records {
+		y := "no"
+		if i < 4 {
+			y = "yes"
+		}
+		records[i] = map[string]*dlit.Literal{"y": dlit.NewString(y)}
+	}
+	return records
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+)
+
+// goalsDistanceAggregator gives the sum of how far each goal is from
+// passing, so that rules which narrowly fail their goals can be told
+// apart from those that fail them by a long way
+type goalsDistanceAggregator struct{}
+
+type goalsDistanceSpec struct {
+	name string
+}
+
+type goalsDistanceInstance struct {
+	spec *goalsDistanceSpec
+}
+
+func init() {
+	Register("goalsdistance", &goalsDistanceAggregator{})
+}
+
+func (a *goalsDistanceAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *goalsDistanceAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{}
+}
+
+func (a *goalsDistanceAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	d := &goalsDistanceSpec{name: name}
+	return d, nil
+}
+
+func (ad *goalsDistanceSpec) New() Instance {
+	return &goalsDistanceInstance{spec: ad}
+}
+
+func (ad *goalsDistanceSpec) Name() string {
+	return ad.name
+}
+
+func (ad *goalsDistanceSpec) Kind() string {
+	return "goalsdistance"
+}
+
+func (ad *goalsDistanceSpec) Args() []string {
+	return []string{}
+}
+
+func (ai *goalsDistanceInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *goalsDistanceInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return nil
+}
+
+func (ai *goalsDistanceInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	instancesMap, err :=
+		InstancesToMap(aggregatorInstances, goals, numRecords, ai.Name())
+	if err != nil {
+		return dlit.MustNew(err)
+	}
+	return ai.CalcResult(instancesMap, goals, numRecords)
+}
+
+func (ai *goalsDistanceInstance) CalcResult(
+	results map[string]*dlit.Literal,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	goalsDistance, err := goal.Distance(goals, results)
+	if err != nil {
+		return dlit.MustNew(err)
+	}
+	return dlit.MustNew(goalsDistance)
+}
@@ -0,0 +1,102 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestGoalsDistanceSpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "goalsdistance")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestGoalsDistanceSpecKind(t *testing.T) {
+	kind := "goalsdistance"
+	as := MustNew("a", kind)
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestGoalsDistanceSpecArgs(t *testing.T) {
+	want := []string{}
+	as := MustNew("a", "goalsdistance")
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
+	}
+}
+
+func TestGoalsDistanceNextRecord(t *testing.T) {
+	as := MustNew("a", "goalsdistance")
+	ai := as.New()
+	record := map[string]*dlit.Literal{}
+	got := ai.NextRecord(record, true)
+	if got != nil {
+		t.Errorf("NextRecord: got: %s, want: nil", got)
+	}
+}
+
+func TestGoalsDistanceResult(t *testing.T) {
+	aggregatorSpecs := []Spec{
+		MustNew("income", "calc", "3 + 4"),
+		MustNew("costs", "calc", "5 + 6"),
+		MustNew("goalsDistance", "goalsdistance"),
+	}
+	cases := []struct {
+		goals []*goal.Goal
+		want  *dlit.Literal
+	}{
+		{goals: []*goal.Goal{},
+			want: dlit.MustNew(0),
+		},
+		{goals: []*goal.Goal{
+			goal.MustNew("income > 6"),
+			goal.MustNew("costs < 20"),
+		},
+			want: dlit.MustNew(0),
+		},
+		{goals: []*goal.Goal{
+			goal.MustNew("income > 14"),
+			goal.MustNew("costs < 5.5"),
+		},
+			want: dlit.MustNew(1),
+		},
+		{goals: []*goal.Goal{
+			goal.MustNew("income > 10 || costs < 12"),
+			goal.MustNew("income >= 14 && costs <= 5.5"),
+			goal.MustNew("costs - 3 != 8"),
+		},
+			want: dlit.MustNew(1.125),
+		},
+		{goals: []*goal.Goal{
+			goal.MustNew("income > 6"),
+			goal.MustNew("costs >= nothing"),
+		},
+			want: dlit.MustNew(dexpr.InvalidExprError{
+				Expr: "costs >= nothing",
+				Err:  dexpr.VarNotExistError("nothing"),
+			}),
+		},
+	}
+	numRecords := int64(12)
+	instances := make([]Instance, len(aggregatorSpecs))
+	for i, aggregatorSpec := range aggregatorSpecs {
+		instances[i] = aggregatorSpec.New()
+	}
+	goalsDistanceInstance := instances[len(instances)-1]
+	for i, c := range cases {
+		got := goalsDistanceInstance.Result(instances, c.goals, numRecords)
+		if got.String() != c.want.String() {
+			t.Errorf("(%d) Result: got: %s, want: %s", i, got, c.want)
+		}
+	}
+}
@@ -24,7 +24,18 @@ func init() {
 
 func (a *goalsScoreAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *goalsScoreAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{}
+}
+
+func (a *goalsScoreAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
 	d := &goalsScoreSpec{name: name}
 	return d, nil
@@ -42,8 +53,8 @@ func (ad *goalsScoreSpec) Kind() string {
 	return "goalsscore"
 }
 
-func (ad *goalsScoreSpec) Arg() string {
-	return ""
+func (ad *goalsScoreSpec) Args() []string {
+	return []string{}
 }
 
 func (ai *goalsScoreInstance) Name() string {
@@ -67,19 +78,17 @@ func (ai *goalsScoreInstance) Result(
 	if err != nil {
 		return dlit.MustNew(err)
 	}
-	goalsScore := 0.0
-	increment := 1.0
-	for _, goal := range goals {
-		hasPassed, err := goal.Assess(instancesMap)
-		if err != nil {
-			return dlit.MustNew(err)
-		}
-
-		if hasPassed {
-			goalsScore += increment
-		} else {
-			increment = 0.001
-		}
+	return ai.CalcResult(instancesMap, goals, numRecords)
+}
+
+func (ai *goalsScoreInstance) CalcResult(
+	results map[string]*dlit.Literal,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	goalsScore, err := goal.Score(goals, results)
+	if err != nil {
+		return dlit.MustNew(err)
 	}
 	return dlit.MustNew(goalsScore)
 }
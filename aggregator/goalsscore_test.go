@@ -4,6 +4,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
 	"testing"
 )
 
@@ -25,12 +26,12 @@ func TestGoalsScoreSpecKind(t *testing.T) {
 	}
 }
 
-func TestGoalsScoreSpecArg(t *testing.T) {
-	arg := ""
+func TestGoalsScoreSpecArgs(t *testing.T) {
+	want := []string{}
 	as := MustNew("a", "goalsscore")
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
 
@@ -119,6 +120,45 @@ func TestGoalsScoreResult(t *testing.T) {
 	}
 }
 
+func TestGoalsScoreResult_modes(t *testing.T) {
+	aggregatorSpecs := []Spec{
+		MustNew("income", "calc", "3 + 4"),
+		MustNew("costs", "calc", "5 + 6"),
+		MustNew("goalsScore", "goalsscore"),
+	}
+	exprs := []string{"income > 10", "costs < 20", "income > 6"}
+	cases := []struct {
+		opts goal.Options
+		want *dlit.Literal
+	}{
+		{opts: goal.Options{Mode: goal.Strict, Weights: []float64{1, 2, 3}},
+			want: dlit.MustNew(0.005),
+		},
+		{opts: goal.Options{Mode: goal.WeightedSum, Weights: []float64{1, 2, 3}},
+			want: dlit.MustNew(5),
+		},
+		{opts: goal.Options{Mode: goal.AllOrNothing},
+			want: dlit.MustNew(0),
+		},
+	}
+	numRecords := int64(12)
+	instances := make([]Instance, len(aggregatorSpecs))
+	for i, aggregatorSpec := range aggregatorSpecs {
+		instances[i] = aggregatorSpec.New()
+	}
+	goalsScoreInstance := instances[len(instances)-1]
+	for i, c := range cases {
+		goals, err := goal.MakeGoals(exprs, c.opts)
+		if err != nil {
+			t.Fatalf("(%d) MakeGoals: %s", i, err)
+		}
+		got := goalsScoreInstance.Result(instances, goals, numRecords)
+		if got.String() != c.want.String() {
+			t.Errorf("(%d) Result: got: %s, want: %s", i, got, c.want)
+		}
+	}
+}
+
 func TestGoalsScoreResult_aggregator_error(t *testing.T) {
 	aggregatorSpecs := []Spec{
 		MustNew("mid", "calc", "a+e"),
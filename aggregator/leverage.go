@@ -0,0 +1,45 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+)
+
+// leverageAggregator gives the difference between the support of a rule
+// and the support expected if the rule and the consequent expression
+// were independent.
+type leverageAggregator struct{}
+
+func init() {
+	Register("leverage", &leverageAggregator{})
+}
+
+func (a *leverageAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *leverageAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *leverageAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	return newAssociationSpec(
+		name,
+		"leverage",
+		args[0].Expr(),
+		leverage,
+	), nil
+}
+
+// leverage returns support - coverage*baseRate
+func leverage(cm confusionMatrix) *dlit.Literal {
+	return cm.eval(leverageExpr, cm.tp+cm.fp+cm.tn+cm.fn)
+}
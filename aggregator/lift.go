@@ -0,0 +1,46 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+)
+
+// liftAggregator gives the confidence of a rule divided by the base rate
+// of the consequent expression over all the records.  A lift above 1
+// shows that the consequent is more likely when the rule is true.
+type liftAggregator struct{}
+
+func init() {
+	Register("lift", &liftAggregator{})
+}
+
+func (a *liftAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *liftAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *liftAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	return newAssociationSpec(
+		name,
+		"lift",
+		args[0].Expr(),
+		lift,
+	), nil
+}
+
+// lift returns confidence/baseRate, which is 0 if the rule matches no
+// records or if the base rate is 0
+func lift(cm confusionMatrix) *dlit.Literal {
+	return cm.eval(liftExpr, cm.tp+cm.fp, cm.tp+cm.fn)
+}
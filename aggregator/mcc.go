@@ -20,11 +20,8 @@ type mccSpec struct {
 }
 
 type mccInstance struct {
-	spec              *mccSpec
-	numTruePositives  int64
-	numTrueNegatives  int64
-	numFalsePositives int64
-	numFalseNegatives int64
+	spec *mccSpec
+	cm   confusionMatrix
 }
 
 // This is calculated using a dexpr because this easily handles errors and
@@ -45,26 +42,29 @@ func init() {
 
 func (a *mccAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *mccAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *mccAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
-	dexpr, err := dexpr.New(expr, dexprfuncs.CallFuncs)
-	if err != nil {
-		return nil, err
-	}
 	d := &mccSpec{
 		name: name,
-		expr: dexpr,
+		expr: args[0].Expr(),
 	}
 	return d, nil
 }
 
 func (ad *mccSpec) New() Instance {
 	return &mccInstance{
-		spec:              ad,
-		numTruePositives:  0,
-		numTrueNegatives:  0,
-		numFalsePositives: 0,
-		numFalseNegatives: 0,
+		spec: ad,
 	}
 }
 
@@ -76,8 +76,8 @@ func (ad *mccSpec) Kind() string {
 	return "mcc"
 }
 
-func (ad *mccSpec) Arg() string {
-	return ad.expr.String()
+func (ad *mccSpec) Args() []string {
+	return []string{ad.expr.String()}
 }
 
 func (ai *mccInstance) Name() string {
@@ -88,25 +88,7 @@ func (ai *mccInstance) NextRecord(
 	record map[string]*dlit.Literal,
 	isRuleTrue bool,
 ) error {
-	matchExprIsTrue, err := ai.spec.expr.EvalBool(record)
-	if err != nil {
-		return err
-	}
-	if matchExprIsTrue {
-		if isRuleTrue {
-			ai.numTruePositives++
-		} else {
-			ai.numFalseNegatives++
-		}
-	} else {
-		if isRuleTrue {
-			ai.numFalsePositives++
-		} else {
-			ai.numTrueNegatives++
-		}
-	}
-
-	return nil
+	return ai.cm.next(ai.spec.expr, record, isRuleTrue)
 }
 
 func (ai *mccInstance) Result(
@@ -119,10 +101,10 @@ func (ai *mccInstance) Result(
 	}
 
 	vars := map[string]*dlit.Literal{
-		"tp": dlit.MustNew(ai.numTruePositives),
-		"tn": dlit.MustNew(ai.numTrueNegatives),
-		"fp": dlit.MustNew(ai.numFalsePositives),
-		"fn": dlit.MustNew(ai.numFalseNegatives),
+		"tp": dlit.MustNew(ai.cm.tp),
+		"tn": dlit.MustNew(ai.cm.tn),
+		"fp": dlit.MustNew(ai.cm.fp),
+		"fn": dlit.MustNew(ai.cm.fn),
 	}
 	radIsZero, err := radicandIsZeroExpr.EvalBool(vars)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
 	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+	"reflect"
 	"testing"
 )
 
@@ -165,12 +166,12 @@ func TestMCCSpecKind(t *testing.T) {
 	}
 }
 
-func TestMCCSpecArg(t *testing.T) {
-	arg := "band > 4"
-	as := MustNew("a", "mcc", arg)
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+func TestMCCSpecArgs(t *testing.T) {
+	want := []string{"band > 4"}
+	as := MustNew("a", "mcc", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
 
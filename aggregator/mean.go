@@ -35,15 +35,22 @@ func init() {
 
 func (a *meanAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *meanAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *meanAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
-	dexpr, err := dexpr.New(expr, dexprfuncs.CallFuncs)
-	if err != nil {
-		return nil, err
-	}
 	d := &meanSpec{
 		name: name,
-		expr: dexpr,
+		expr: args[0].Expr(),
 	}
 	return d, nil
 }
@@ -65,8 +72,8 @@ func (ad *meanSpec) Kind() string {
 	return "mean"
 }
 
-func (ad *meanSpec) Arg() string {
-	return ad.expr.String()
+func (ad *meanSpec) Args() []string {
+	return []string{ad.expr.String()}
 }
 
 func (ai *meanInstance) Name() string {
@@ -4,6 +4,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
 	"testing"
 )
 
@@ -149,12 +150,12 @@ func TestMeanSpecKind(t *testing.T) {
 	}
 }
 
-func TestMeanSpecArg(t *testing.T) {
-	arg := "income - cost"
-	as := MustNew("a", "mean", arg)
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+func TestMeanSpecArgs(t *testing.T) {
+	want := []string{"income - cost"}
+	as := MustNew("a", "mean", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
 
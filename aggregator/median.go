@@ -0,0 +1,40 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+// medianAggregator gives the median of an expression over the records
+// that a rule matches.  It takes the expression and optionally the
+// mode: exact or approx, as used by the percentile aggregator.
+type medianAggregator struct{}
+
+func init() {
+	Register("median", &medianAggregator{})
+}
+
+func (a *medianAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *medianAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{
+		{Name: "expr", Kind: ExprArg},
+		percentileModeArgDesc,
+	}
+}
+
+func (a *medianAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	return newPercentileSpec(
+		name,
+		"median",
+		args[0].Expr(),
+		50,
+		args[1].String(),
+	), nil
+}
@@ -0,0 +1,121 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestNewMedian_errors(t *testing.T) {
+	cases := []struct {
+		args    []string
+		wantErr error
+	}{
+		{args: []string{},
+			wantErr: ErrInvalidNumArgs,
+		},
+		{args: []string{"balance", "exact", "approx"},
+			wantErr: ErrInvalidNumArgs,
+		},
+		{args: []string{"balance", "50"},
+			wantErr: ArgError{Arg: "mode", Value: "50", Err: ErrInvalidChoice},
+		},
+	}
+	for i, c := range cases {
+		_, err := New("a", "median", c.args...)
+		wantErr := DescError{Name: "a", Kind: "median", Err: c.wantErr}
+		if err == nil || err.Error() != wantErr.Error() {
+			t.Errorf("(%d) New: gotErr: %s, wantErr: %s", i, err, wantErr)
+		}
+	}
+}
+
+func TestMedianResult(t *testing.T) {
+	records := makeSpreadRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		args []string
+		rule func(int) bool
+		want float64
+	}{
+		{[]string{"duration"}, func(i int) bool { return i < 8 }, 4.5},
+		{[]string{"duration"}, func(i int) bool { return i < 7 }, 4},
+		{[]string{"duration", "exact"}, func(i int) bool { return i >= 8 }, 2},
+		{[]string{"duration", "approx"}, func(i int) bool { return i < 5 }, 4},
+		{[]string{"duration"}, func(i int) bool { return false }, 0},
+	}
+	for ci, c := range cases {
+		medianDesc := MustNew("m", "median", c.args...)
+		median := medianDesc.New()
+		instances := []Instance{median}
+
+		for i, record := range records {
+			if err := median.NextRecord(record, c.rule(i)); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", ci, err)
+			}
+		}
+		numRecords := int64(len(records))
+		got := median.Result(instances, goals, numRecords)
+		gotFloat, gotIsFloat := got.Float()
+		if !gotIsFloat || gotFloat != c.want {
+			t.Errorf("(%d) - Result, got: %v, want: %f", ci, got, c.want)
+		}
+	}
+}
+
+func TestMedianSpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "median", "balance")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestMedianSpecKind(t *testing.T) {
+	kind := "median"
+	as := MustNew("a", kind, "balance")
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestMedianSpecArgs(t *testing.T) {
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{"income - cost"}, want: []string{"income - cost"}},
+		{args: []string{"balance", "approx"},
+			want: []string{"balance", "approx"},
+		},
+	}
+	for _, c := range cases {
+		as := MustNew("a", "median", c.args...)
+		got := as.Args()
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Args - got: %q, want: %q", got, c.want)
+		}
+	}
+}
+
+func TestMedianInstanceName(t *testing.T) {
+	as := MustNew("abc", "median", "cost + 2")
+	ai := as.New()
+	got := ai.Name()
+	want := "abc"
+	if got != want {
+		t.Errorf("Name: got: %s, want: %s", got, want)
+	}
+}
+
+func TestMedianNextRecord(t *testing.T) {
+	as := MustNew("a", "median", "cost")
+	ai := as.New()
+	record := map[string]*dlit.Literal{"cost": dlit.MustNew(3)}
+	if err := ai.NextRecord(record, false); err != nil {
+		t.Errorf("NextRecord: got: %s, want: nil", err)
+	}
+}
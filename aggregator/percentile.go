@@ -0,0 +1,276 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+)
+
+// percentileAggregator gives a percentile of an expression over the
+// records that a rule matches.  It takes the expression, the percentile
+// from 0 to 100 and optionally the mode: exact or approx.  The exact
+// mode stores every value, whereas the approx mode uses the P-squared
+// streaming algorithm which uses a fixed amount of memory and so is
+// better suited to large datasets.
+type percentileAggregator struct{}
+
+type percentileSpec struct {
+	name       string
+	kind       string
+	expr       *dexpr.Expr
+	percentile float64
+	approx     bool
+}
+
+type percentileInstance struct {
+	spec   *percentileSpec
+	values []float64
+	sketch *p2Sketch
+	maxDP  int
+}
+
+// p2Sketch estimates a percentile using the P-squared algorithm by
+// Jain and Chlamtac, which keeps five markers whose heights approximate
+// the minimum, the percentile, the maximum and two points between
+type p2Sketch struct {
+	heights   [5]float64
+	positions [5]float64
+	desired   [5]float64
+	increment [5]float64
+}
+
+func init() {
+	Register("percentile", &percentileAggregator{})
+}
+
+func (a *percentileAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *percentileAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{
+		{Name: "expr", Kind: ExprArg},
+		{Name: "percentile",
+			Kind:  NumberArg,
+			Valid: func(p float64) bool { return p >= 0 && p <= 100 },
+		},
+		percentileModeArgDesc,
+	}
+}
+
+func (a *percentileAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	return newPercentileSpec(
+		name,
+		"percentile",
+		args[0].Expr(),
+		args[1].Number(),
+		args[2].String(),
+	), nil
+}
+
+// percentileModeArgDesc describes the optional mode argument taken by
+// the percentile and median aggregators
+var percentileModeArgDesc = ArgDesc{
+	Name:     "mode",
+	Kind:     ChoiceArg,
+	Optional: true,
+	Default:  "exact",
+	Choices:  []string{"exact", "approx"},
+}
+
+func newPercentileSpec(
+	name string,
+	kind string,
+	expr *dexpr.Expr,
+	percentile float64,
+	mode string,
+) Spec {
+	return &percentileSpec{
+		name:       name,
+		kind:       kind,
+		expr:       expr,
+		percentile: percentile,
+		approx:     mode == "approx",
+	}
+}
+
+func (ad *percentileSpec) New() Instance {
+	ai := &percentileInstance{
+		spec:   ad,
+		values: []float64{},
+		maxDP:  0,
+	}
+	return ai
+}
+
+func (ad *percentileSpec) Name() string {
+	return ad.name
+}
+
+func (ad *percentileSpec) Kind() string {
+	return ad.kind
+}
+
+func (ad *percentileSpec) Args() []string {
+	args := []string{ad.expr.String()}
+	if ad.kind == "percentile" {
+		args = append(args, strconv.FormatFloat(ad.percentile, 'f', -1, 64))
+	}
+	if ad.approx {
+		args = append(args, "approx")
+	}
+	return args
+}
+
+func (ai *percentileInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *percentileInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	if !isRuleTrue {
+		return nil
+	}
+	exprValue := ai.spec.expr.Eval(record)
+	if err := exprValue.Err(); err != nil {
+		return err
+	}
+	v, isFloat := exprValue.Float()
+	if !isFloat {
+		return dexpr.InvalidExprError{
+			Expr: ai.spec.expr.String(),
+			Err:  ErrNotNumber,
+		}
+	}
+	if dp := numDecPlaces(exprValue); dp > ai.maxDP {
+		ai.maxDP = dp
+	}
+	if ai.sketch != nil {
+		ai.sketch.add(v)
+		return nil
+	}
+	ai.values = append(ai.values, v)
+	if ai.spec.approx && len(ai.values) == 5 {
+		ai.sketch = newP2Sketch(ai.spec.percentile/100, ai.values)
+		ai.values = []float64{}
+	}
+	return nil
+}
+
+func (ai *percentileInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	var r float64
+	if ai.sketch != nil {
+		r = ai.sketch.estimate(ai.spec.percentile / 100)
+	} else if len(ai.values) == 0 {
+		return dlit.MustNew(0)
+	} else {
+		r = exactPercentile(ai.values, ai.spec.percentile/100)
+	}
+	return roundTo(dlit.MustNew(r), ai.maxDP+2)
+}
+
+// exactPercentile returns the percentile, p, from 0 to 1, of the values
+// using linear interpolation between the closest ranks
+func exactPercentile(values []float64, p float64) float64 {
+	sort.Float64s(values)
+	rank := p * float64(len(values)-1)
+	lo := math.Floor(rank)
+	hi := math.Ceil(rank)
+	vLo := values[int(lo)]
+	vHi := values[int(hi)]
+	return vLo + (rank-lo)*(vHi-vLo)
+}
+
+// newP2Sketch returns a p2Sketch for percentile p, from 0 to 1,
+// initialised with the first five values
+func newP2Sketch(p float64, values []float64) *p2Sketch {
+	s := &p2Sketch{
+		positions: [5]float64{0, 1, 2, 3, 4},
+		desired:   [5]float64{0, 2 * p, 4 * p, 2 + 2*p, 4},
+		increment: [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}
+	copy(s.heights[:], values)
+	sort.Float64s(s.heights[:])
+	return s
+}
+
+func (s *p2Sketch) add(v float64) {
+	var k int
+	switch {
+	case v < s.heights[0]:
+		s.heights[0] = v
+		k = 0
+	case v >= s.heights[4]:
+		s.heights[4] = v
+		k = 3
+	default:
+		for k = 0; k < 3 && v >= s.heights[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		s.positions[i]++
+	}
+	for i := range s.desired {
+		s.desired[i] += s.increment[i]
+	}
+	for i := 1; i <= 3; i++ {
+		d := s.desired[i] - s.positions[i]
+		if (d >= 1 && s.positions[i+1]-s.positions[i] > 1) ||
+			(d <= -1 && s.positions[i-1]-s.positions[i] < -1) {
+			sign := 1.0
+			if d < 0 {
+				sign = -1.0
+			}
+			h := s.parabolic(i, sign)
+			if s.heights[i-1] < h && h < s.heights[i+1] {
+				s.heights[i] = h
+			} else {
+				s.heights[i] = s.linear(i, sign)
+			}
+			s.positions[i] += sign
+		}
+	}
+}
+
+func (s *p2Sketch) parabolic(i int, d float64) float64 {
+	q, n := s.heights, s.positions
+	return q[i] + d/(n[i+1]-n[i-1])*
+		((n[i]-n[i-1]+d)*(q[i+1]-q[i])/(n[i+1]-n[i])+
+			(n[i+1]-n[i]-d)*(q[i]-q[i-1])/(n[i]-n[i-1]))
+}
+
+func (s *p2Sketch) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return s.heights[i] +
+		d*(s.heights[j]-s.heights[i])/(s.positions[j]-s.positions[i])
+}
+
+// estimate returns the estimate of percentile p, from 0 to 1
+func (s *p2Sketch) estimate(p float64) float64 {
+	switch p {
+	case 0:
+		return s.heights[0]
+	case 1:
+		return s.heights[4]
+	}
+	return s.heights[2]
+}
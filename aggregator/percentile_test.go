@@ -0,0 +1,235 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNewPercentile_errors(t *testing.T) {
+	cases := []struct {
+		args    []string
+		wantErr error
+	}{
+		{args: []string{"balance"},
+			wantErr: ErrInvalidNumArgs,
+		},
+		{args: []string{"balance", "90", "exact", "4"},
+			wantErr: ErrInvalidNumArgs,
+		},
+		{args: []string{"balance", "ninety"},
+			wantErr: ArgError{
+				Arg:   "percentile",
+				Value: "ninety",
+				Err:   ErrNotNumber,
+			},
+		},
+		{args: []string{"balance", "-1"},
+			wantErr: ArgError{Arg: "percentile", Value: "-1", Err: ErrOutOfRange},
+		},
+		{args: []string{"balance", "100.5"},
+			wantErr: ArgError{
+				Arg:   "percentile",
+				Value: "100.5",
+				Err:   ErrOutOfRange,
+			},
+		},
+		{args: []string{"balance", "90", "rough"},
+			wantErr: ArgError{Arg: "mode", Value: "rough", Err: ErrInvalidChoice},
+		},
+		{args: []string{"3+4+{", "90"},
+			wantErr: dexpr.InvalidExprError{
+				Expr: "3+4+{",
+				Err:  dexpr.ErrSyntax,
+			},
+		},
+	}
+	for i, c := range cases {
+		_, err := New("a", "percentile", c.args...)
+		wantErr := DescError{Name: "a", Kind: "percentile", Err: c.wantErr}
+		if err == nil || err.Error() != wantErr.Error() {
+			t.Errorf("(%d) New: gotErr: %s, wantErr: %s", i, err, wantErr)
+		}
+	}
+}
+
+func TestPercentileResult(t *testing.T) {
+	records := makeSpreadRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		args []string
+		rule func(int) bool
+		want float64
+	}{
+		{[]string{"duration", "50"}, func(i int) bool { return i < 8 }, 4.5},
+		{[]string{"duration", "90"}, func(i int) bool { return i < 8 }, 7.6},
+		{[]string{"duration", "0"}, func(i int) bool { return i < 8 }, 2},
+		{[]string{"duration", "100"}, func(i int) bool { return i < 8 }, 9},
+		{[]string{"duration", "25"}, func(i int) bool { return i >= 8 }, 1.75},
+		{[]string{"duration", "25", "exact"},
+			func(i int) bool { return i >= 8 },
+			1.75,
+		},
+		{[]string{"duration", "25", "approx"},
+			func(i int) bool { return i >= 8 },
+			1.75,
+		},
+		{[]string{"duration", "90", "approx"},
+			func(i int) bool { return i == 3 },
+			4,
+		},
+		{[]string{"duration", "90"}, func(i int) bool { return false }, 0},
+		{[]string{"duration", "90", "approx"},
+			func(i int) bool { return false },
+			0,
+		},
+	}
+	for ci, c := range cases {
+		percentileDesc := MustNew("p", "percentile", c.args...)
+		percentile := percentileDesc.New()
+		instances := []Instance{percentile}
+
+		for i, record := range records {
+			if err := percentile.NextRecord(record, c.rule(i)); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", ci, err)
+			}
+		}
+		numRecords := int64(len(records))
+		got := percentile.Result(instances, goals, numRecords)
+		gotFloat, gotIsFloat := got.Float()
+		if !gotIsFloat || gotFloat != c.want {
+			t.Errorf("(%d) - Result, got: %v, want: %f", ci, got, c.want)
+		}
+	}
+}
+
+func TestPercentileResult_approx(t *testing.T) {
+	goals := []*goal.Goal{}
+	numRecords := int64(10000)
+	for _, p := range []string{"0", "10", "50", "90", "99", "100"} {
+		exact := MustNew("exact", "percentile", "v", p).New()
+		approx := MustNew("approx", "percentile", "v", p, "approx").New()
+		// Visit the values 0 to 9999 in a scrambled order
+		for i := int64(0); i < numRecords; i++ {
+			record := map[string]*dlit.Literal{
+				"v": dlit.MustNew((i * 7919) % numRecords),
+			}
+			if err := exact.NextRecord(record, true); err != nil {
+				t.Fatalf("NextRecord: %s", err)
+			}
+			if err := approx.NextRecord(record, true); err != nil {
+				t.Fatalf("NextRecord: %s", err)
+			}
+		}
+		want, _ := exact.Result([]Instance{exact}, goals, numRecords).Float()
+		got, gotIsFloat :=
+			approx.Result([]Instance{approx}, goals, numRecords).Float()
+		if !gotIsFloat || math.Abs(got-want) > float64(numRecords)/100 {
+			t.Errorf("Result - percentile: %s, got: %f, want: %f", p, got, want)
+		}
+	}
+}
+
+func TestPercentileNextRecord_errors(t *testing.T) {
+	cases := []struct {
+		record map[string]*dlit.Literal
+		arg    string
+		want   error
+	}{
+		{record: map[string]*dlit.Literal{},
+			arg: "cost + 2",
+			want: dexpr.InvalidExprError{
+				Expr: "cost + 2",
+				Err:  dexpr.VarNotExistError("cost"),
+			},
+		},
+		{record: map[string]*dlit.Literal{"cost": dlit.NewString("hello")},
+			arg: "cost",
+			want: dexpr.InvalidExprError{
+				Expr: "cost",
+				Err:  ErrNotNumber,
+			},
+		},
+	}
+	for _, c := range cases {
+		as := MustNew("a", "percentile", c.arg, "90")
+		ai := as.New()
+		got := ai.NextRecord(c.record, true)
+		if got == nil || got.Error() != c.want.Error() {
+			t.Errorf("NextRecord: got: %s, want: %s", got, c.want)
+		}
+	}
+}
+
+func TestPercentileSpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "percentile", "balance", "90")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestPercentileSpecKind(t *testing.T) {
+	kind := "percentile"
+	as := MustNew("a", kind, "balance", "90")
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestPercentileSpecArgs(t *testing.T) {
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{"income - cost", "90"},
+			want: []string{"income - cost", "90"},
+		},
+		{args: []string{"balance", "12.5", "exact"},
+			want: []string{"balance", "12.5"},
+		},
+		{args: []string{"balance", "75", "approx"},
+			want: []string{"balance", "75", "approx"},
+		},
+	}
+	for _, c := range cases {
+		as := MustNew("a", "percentile", c.args...)
+		got := as.Args()
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Args - got: %q, want: %q", got, c.want)
+		}
+	}
+}
+
+func TestPercentileInstanceName(t *testing.T) {
+	as := MustNew("abc", "percentile", "cost + 2", "90")
+	ai := as.New()
+	got := ai.Name()
+	want := "abc"
+	if got != want {
+		t.Errorf("Name: got: %s, want: %s", got, want)
+	}
+}
+
+/*************************
+ *       Benchmarks
+ *************************/
+
+func BenchmarkPercentileNextRecord_approx(b *testing.B) {
+	as := MustNew("a", "percentile", "cost + 2", "90", "approx")
+	ai := as.New()
+	record := map[string]*dlit.Literal{"cost": dlit.NewString("17.89245")}
+	for n := 0; n < b.N; n++ {
+		b.StartTimer()
+		got := ai.NextRecord(record, true)
+		b.StopTimer()
+		if got != nil {
+			b.Errorf("NextRecord: %s", got)
+		}
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
-	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+	"github.com/vlifesystems/rhkit/internal"
 )
 
 type precisionAggregator struct{}
@@ -18,41 +18,38 @@ type precisionSpec struct {
 }
 
 type precisionInstance struct {
-	spec  *precisionSpec
-	numTP int64
-	numFP int64
+	spec *precisionSpec
+	cm   confusionMatrix
 }
 
-var precisionExpr = dexpr.MustNew(
-	"roundto(numTP/(numTP+numFP),4)",
-	dexprfuncs.CallFuncs,
-)
-
 func init() {
 	Register("precision", &precisionAggregator{})
 }
 
 func (a *precisionAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *precisionAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *precisionAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
-	dexpr, err := dexpr.New(expr, dexprfuncs.CallFuncs)
-	if err != nil {
-		return nil, err
-	}
 	d := &precisionSpec{
 		name: name,
-		expr: dexpr,
+		expr: args[0].Expr(),
 	}
 	return d, nil
 }
 
 func (ad *precisionSpec) New() Instance {
-	return &precisionInstance{
-		spec:  ad,
-		numTP: 0,
-		numFP: 0,
-	}
+	return &precisionInstance{spec: ad}
 }
 
 func (ad *precisionSpec) Name() string {
@@ -63,28 +60,41 @@ func (ad *precisionSpec) Kind() string {
 	return "precision"
 }
 
-func (ad *precisionSpec) Arg() string {
-	return ad.expr.String()
+func (ad *precisionSpec) Args() []string {
+	return []string{ad.expr.String()}
 }
 
 func (ai *precisionInstance) Name() string {
 	return ai.spec.name
 }
 
-func (ai *precisionInstance) NextRecord(record map[string]*dlit.Literal,
-	isRuleTrue bool) error {
-	matchExprIsTrue, err := ai.spec.expr.EvalBool(record)
-	if err != nil {
-		return err
+func (ai *precisionInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return ai.cm.next(ai.spec.expr, record, isRuleTrue)
+}
+
+// Bounds returns the precision if all the remaining records were to be
+// false positives and if they were all to be true positives
+func (ai *precisionInstance) Bounds(numRemaining int64) (float64, float64) {
+	n := float64(ai.cm.tp + ai.cm.fp + numRemaining)
+	if n == 0 {
+		return 0, 0
 	}
-	if isRuleTrue {
-		if matchExprIsTrue {
-			ai.numTP++
-		} else {
-			ai.numFP++
+	min := internal.RoundFloat(float64(ai.cm.tp)/n, 4)
+	max := internal.RoundFloat(float64(ai.cm.tp+numRemaining)/n, 4)
+	if ai.cm.tp+ai.cm.fp > 0 {
+		// The remaining records might not match the rule
+		p := internal.RoundFloat(float64(ai.cm.tp)/float64(ai.cm.tp+ai.cm.fp), 4)
+		if p < min {
+			min = p
+		}
+		if p > max {
+			max = p
 		}
 	}
-	return nil
+	return min, max
 }
 
 func (ai *precisionInstance) Result(
@@ -92,13 +102,5 @@ func (ai *precisionInstance) Result(
 	goals []*goal.Goal,
 	numRecords int64,
 ) *dlit.Literal {
-	if ai.numTP == 0 && ai.numFP == 0 {
-		return dlit.MustNew(0)
-	}
-
-	vars := map[string]*dlit.Literal{
-		"numTP": dlit.MustNew(ai.numTP),
-		"numFP": dlit.MustNew(ai.numFP),
-	}
-	return roundTo(precisionExpr.Eval(vars), 4)
+	return ai.cm.precision()
 }
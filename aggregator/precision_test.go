@@ -4,6 +4,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
 	"testing"
 )
 
@@ -113,6 +114,44 @@ func TestPrecisionNextRecord_error(t *testing.T) {
 	}
 }
 
+func TestPrecisionBounds(t *testing.T) {
+	cases := []struct {
+		tp           int64
+		fp           int64
+		numRemaining int64
+		wantMin      float64
+		wantMax      float64
+	}{
+		{tp: 0, fp: 0, numRemaining: 0, wantMin: 0, wantMax: 0},
+		{tp: 0, fp: 0, numRemaining: 4, wantMin: 0, wantMax: 1},
+		{tp: 1, fp: 1, numRemaining: 0, wantMin: 0.5, wantMax: 0.5},
+		{tp: 1, fp: 1, numRemaining: 2, wantMin: 0.25, wantMax: 0.75},
+		{tp: 3, fp: 0, numRemaining: 3, wantMin: 0.5, wantMax: 1},
+		{tp: 0, fp: 3, numRemaining: 1, wantMin: 0, wantMax: 0.25},
+		{tp: 1, fp: 2, numRemaining: 3, wantMin: 0.1667, wantMax: 0.6667},
+	}
+	isTP := map[string]*dlit.Literal{"band": dlit.MustNew(6)}
+	isFP := map[string]*dlit.Literal{"band": dlit.MustNew(2)}
+	for i, c := range cases {
+		ai := MustNew("a", "precision", "band > 4").New()
+		for j := int64(0); j < c.tp; j++ {
+			if err := ai.NextRecord(isTP, true); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", i, err)
+			}
+		}
+		for j := int64(0); j < c.fp; j++ {
+			if err := ai.NextRecord(isFP, true); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", i, err)
+			}
+		}
+		gotMin, gotMax := ai.(Bounder).Bounds(c.numRemaining)
+		if gotMin != c.wantMin || gotMax != c.wantMax {
+			t.Errorf("(%d) Bounds - got: (%f, %f), want: (%f, %f)",
+				i, gotMin, gotMax, c.wantMin, c.wantMax)
+		}
+	}
+}
+
 func TestPrecisionSpecName(t *testing.T) {
 	name := "a"
 	as := MustNew(name, "precision", "cost > 2")
@@ -131,12 +170,12 @@ func TestPrecisionSpecKind(t *testing.T) {
 	}
 }
 
-func TestPrecisionSpecArg(t *testing.T) {
-	arg := "cost > 2"
-	as := MustNew("a", "precision", arg)
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+func TestPrecisionSpecArgs(t *testing.T) {
+	want := []string{"cost > 2"}
+	as := MustNew("a", "precision", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
 
@@ -7,7 +7,6 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
-	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
 )
 
 type recallAggregator struct{}
@@ -18,41 +17,38 @@ type recallSpec struct {
 }
 
 type recallInstance struct {
-	spec  *recallSpec
-	numTP int64
-	numFN int64
+	spec *recallSpec
+	cm   confusionMatrix
 }
 
-var recallExpr = dexpr.MustNew(
-	"roundto(numTP/(numTP+numFN),4)",
-	dexprfuncs.CallFuncs,
-)
-
 func init() {
 	Register("recall", &recallAggregator{})
 }
 
 func (a *recallAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *recallAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *recallAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
-	dexpr, err := dexpr.New(expr, dexprfuncs.CallFuncs)
-	if err != nil {
-		return nil, err
-	}
 	d := &recallSpec{
 		name: name,
-		expr: dexpr,
+		expr: args[0].Expr(),
 	}
 	return d, nil
 }
 
 func (ad *recallSpec) New() Instance {
-	return &recallInstance{
-		spec:  ad,
-		numTP: 0,
-		numFN: 0,
-	}
+	return &recallInstance{spec: ad}
 }
 
 func (ad *recallSpec) Name() string {
@@ -63,30 +59,19 @@ func (ad *recallSpec) Kind() string {
 	return "recall"
 }
 
-func (ad *recallSpec) Arg() string {
-	return ad.expr.String()
+func (ad *recallSpec) Args() []string {
+	return []string{ad.expr.String()}
 }
 
 func (ai *recallInstance) Name() string {
 	return ai.spec.name
 }
 
-func (ai *recallInstance) NextRecord(record map[string]*dlit.Literal,
-	isRuleTrue bool) error {
-	matchExprIsTrue, err := ai.spec.expr.EvalBool(record)
-	if err != nil {
-		return err
-	}
-	if isRuleTrue {
-		if matchExprIsTrue {
-			ai.numTP++
-		}
-	} else {
-		if matchExprIsTrue {
-			ai.numFN++
-		}
-	}
-	return nil
+func (ai *recallInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	return ai.cm.next(ai.spec.expr, record, isRuleTrue)
 }
 
 func (ai *recallInstance) Result(
@@ -94,13 +79,5 @@ func (ai *recallInstance) Result(
 	goals []*goal.Goal,
 	numRecords int64,
 ) *dlit.Literal {
-	if ai.numTP == 0 && ai.numFN == 0 {
-		return dlit.MustNew(0)
-	}
-
-	vars := map[string]*dlit.Literal{
-		"numTP": dlit.MustNew(ai.numTP),
-		"numFN": dlit.MustNew(ai.numFN),
-	}
-	return roundTo(recallExpr.Eval(vars), 4)
+	return ai.cm.recall()
 }
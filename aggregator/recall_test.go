@@ -4,6 +4,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
 	"testing"
 )
 
@@ -130,12 +131,12 @@ func TestRecallSpecKind(t *testing.T) {
 	}
 }
 
-func TestRecallSpecArg(t *testing.T) {
-	arg := "cost > 2"
-	as := MustNew("a", "recall", arg)
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+func TestRecallSpecArgs(t *testing.T) {
+	want := []string{"cost > 2"}
+	as := MustNew("a", "recall", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
 
@@ -0,0 +1,98 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// stddevAggregator gives the population standard deviation of an
+// expression over the records that a rule matches
+type stddevAggregator struct{}
+
+type stddevSpec struct {
+	name string
+	expr *dexpr.Expr
+}
+
+type stddevInstance struct {
+	spec    *stddevSpec
+	welford *welford
+}
+
+var stddevExpr = dexpr.MustNew("sqrt(variance)", dexprfuncs.CallFuncs)
+
+func init() {
+	Register("stddev", &stddevAggregator{})
+}
+
+func (a *stddevAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *stddevAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *stddevAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	d := &stddevSpec{
+		name: name,
+		expr: args[0].Expr(),
+	}
+	return d, nil
+}
+
+func (ad *stddevSpec) New() Instance {
+	return &stddevInstance{
+		spec:    ad,
+		welford: newWelford(),
+	}
+}
+
+func (ad *stddevSpec) Name() string {
+	return ad.name
+}
+
+func (ad *stddevSpec) Kind() string {
+	return "stddev"
+}
+
+func (ad *stddevSpec) Args() []string {
+	return []string{ad.expr.String()}
+}
+
+func (ai *stddevInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *stddevInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	if isRuleTrue {
+		return ai.welford.next(ai.spec.expr.Eval(record))
+	}
+	return nil
+}
+
+func (ai *stddevInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	if ai.welford.numRecords == 0 {
+		return dlit.MustNew(0)
+	}
+	vars := map[string]*dlit.Literal{"variance": ai.welford.variance()}
+	return roundTo(stddevExpr.Eval(vars), ai.welford.maxDP+2)
+}
@@ -0,0 +1,126 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestNewStddev_error(t *testing.T) {
+	_, err := New("a", "stddev", "3+4+{")
+	wantErr := DescError{
+		Name: "a",
+		Kind: "stddev",
+		Err: dexpr.InvalidExprError{
+			Expr: "3+4+{",
+			Err:  dexpr.ErrSyntax,
+		},
+	}.Error()
+	if err.Error() != wantErr {
+		t.Errorf("New: gotErr: %s, wantErr: %s", err, wantErr)
+	}
+}
+
+func TestStddevResult(t *testing.T) {
+	records := makeSpreadRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		records []map[string]*dlit.Literal
+		rule    func(int) bool
+		want    float64
+	}{
+		{records, func(i int) bool { return i < 8 }, 2},
+		{records, func(i int) bool { return i >= 8 }, 0.5},
+		{records, func(i int) bool { return i == 0 || i == 8 }, 0.25},
+		{records, func(i int) bool { return i == 3 }, 0},
+		{records, func(i int) bool { return false }, 0},
+		{[]map[string]*dlit.Literal{}, func(i int) bool { return true }, 0},
+	}
+	for ci, c := range cases {
+		stddevDesc := MustNew("stddevDuration", "stddev", "duration")
+		stddev := stddevDesc.New()
+		instances := []Instance{stddev}
+
+		for i, record := range c.records {
+			if err := stddev.NextRecord(record, c.rule(i)); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", ci, err)
+			}
+		}
+		numRecords := int64(len(records))
+		got := stddev.Result(instances, goals, numRecords)
+		gotFloat, gotIsFloat := got.Float()
+		if !gotIsFloat || gotFloat != c.want {
+			t.Errorf("(%d) - Result, got: %v, want: %f", ci, got, c.want)
+		}
+	}
+}
+
+func TestStddevNextRecord_errors(t *testing.T) {
+	cases := []struct {
+		record map[string]*dlit.Literal
+		arg    string
+		want   error
+	}{
+		{record: map[string]*dlit.Literal{},
+			arg: "cost + 2",
+			want: dexpr.InvalidExprError{
+				Expr: "cost + 2",
+				Err:  dexpr.VarNotExistError("cost"),
+			},
+		},
+		{record: map[string]*dlit.Literal{"cost": dlit.NewString("hello")},
+			arg: "cost",
+			want: dexpr.InvalidExprError{
+				Expr: "mean+(value-mean)/n",
+				Err:  dexpr.ErrIncompatibleTypes,
+			},
+		},
+	}
+	for _, c := range cases {
+		as := MustNew("a", "stddev", c.arg)
+		ai := as.New()
+		got := ai.NextRecord(c.record, true)
+		if got == nil || got.Error() != c.want.Error() {
+			t.Errorf("NextRecord: got: %s, want: %s", got, c.want)
+		}
+	}
+}
+
+func TestStddevSpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "stddev", "income - cost")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestStddevSpecKind(t *testing.T) {
+	kind := "stddev"
+	as := MustNew("a", kind, "income - cost")
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestStddevSpecArgs(t *testing.T) {
+	want := []string{"income - cost"}
+	as := MustNew("a", "stddev", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
+	}
+}
+
+func TestStddevInstanceName(t *testing.T) {
+	as := MustNew("abc", "stddev", "cost + 2")
+	ai := as.New()
+	got := ai.Name()
+	want := "abc"
+	if got != want {
+		t.Errorf("Name: got: %s, want: %s", got, want)
+	}
+}
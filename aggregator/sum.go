@@ -30,15 +30,22 @@ func init() {
 
 func (a *sumAggregator) MakeSpec(
 	name string,
-	expr string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *sumAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *sumAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
 ) (Spec, error) {
-	dexpr, err := dexpr.New(expr, dexprfuncs.CallFuncs)
-	if err != nil {
-		return nil, err
-	}
 	d := &sumSpec{
 		name: name,
-		expr: dexpr,
+		expr: args[0].Expr(),
 	}
 	return d, nil
 }
@@ -58,8 +65,8 @@ func (ad *sumSpec) Kind() string {
 	return "sum"
 }
 
-func (ad *sumSpec) Arg() string {
-	return ad.expr.String()
+func (ad *sumSpec) Args() []string {
+	return []string{ad.expr.String()}
 }
 
 func (ai *sumInstance) Name() string {
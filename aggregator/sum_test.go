@@ -4,6 +4,7 @@ import (
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
 	"testing"
 )
 
@@ -111,12 +112,12 @@ func TestSumSpecKind(t *testing.T) {
 	}
 }
 
-func TestSumSpecArg(t *testing.T) {
-	arg := "income-cost"
-	as := MustNew("a", "sum", arg)
-	got := as.Arg()
-	if got != arg {
-		t.Errorf("Arg - got: %s, want: %s", got, arg)
+func TestSumSpecArgs(t *testing.T) {
+	want := []string{"income-cost"}
+	as := MustNew("a", "sum", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
 	}
 }
 
@@ -0,0 +1,44 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dlit"
+)
+
+// supportAggregator gives the proportion of all the records for which
+// both a rule and the consequent expression are true.
+type supportAggregator struct{}
+
+func init() {
+	Register("support", &supportAggregator{})
+}
+
+func (a *supportAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *supportAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *supportAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	return newAssociationSpec(
+		name,
+		"support",
+		args[0].Expr(),
+		support,
+	), nil
+}
+
+// support returns tp/n
+func support(cm confusionMatrix) *dlit.Literal {
+	return cm.eval(supportExpr, cm.tp+cm.fp+cm.tn+cm.fn)
+}
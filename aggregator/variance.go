@@ -0,0 +1,162 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// varianceAggregator gives the population variance of an expression
+// over the records that a rule matches
+type varianceAggregator struct{}
+
+type varianceSpec struct {
+	name string
+	expr *dexpr.Expr
+}
+
+type varianceInstance struct {
+	spec    *varianceSpec
+	welford *welford
+}
+
+// welford keeps a running mean and sum of squared differences from the
+// mean using Welford's online algorithm, which is numerically stable
+type welford struct {
+	mean       *dlit.Literal
+	m2         *dlit.Literal
+	numRecords int64
+	maxDP      int
+}
+
+var welfordMeanExpr = dexpr.MustNew("mean+(value-mean)/n", dexprfuncs.CallFuncs)
+var welfordM2Expr = dexpr.MustNew(
+	"m2+(value-oldMean)*(value-mean)",
+	dexprfuncs.CallFuncs,
+)
+var varianceExpr = dexpr.MustNew("m2/n", dexprfuncs.CallFuncs)
+
+func init() {
+	Register("variance", &varianceAggregator{})
+}
+
+func (a *varianceAggregator) MakeSpec(
+	name string,
+	arg string,
+) (Spec, error) {
+	return makeArgsSpec(a, name, singleArg(arg))
+}
+
+func (a *varianceAggregator) ArgDescs() []ArgDesc {
+	return []ArgDesc{{Name: "expr", Kind: ExprArg}}
+}
+
+func (a *varianceAggregator) MakeSpecArgs(
+	name string,
+	args []Arg,
+) (Spec, error) {
+	d := &varianceSpec{
+		name: name,
+		expr: args[0].Expr(),
+	}
+	return d, nil
+}
+
+func (ad *varianceSpec) New() Instance {
+	return &varianceInstance{
+		spec:    ad,
+		welford: newWelford(),
+	}
+}
+
+func (ad *varianceSpec) Name() string {
+	return ad.name
+}
+
+func (ad *varianceSpec) Kind() string {
+	return "variance"
+}
+
+func (ad *varianceSpec) Args() []string {
+	return []string{ad.expr.String()}
+}
+
+func (ai *varianceInstance) Name() string {
+	return ai.spec.name
+}
+
+func (ai *varianceInstance) NextRecord(
+	record map[string]*dlit.Literal,
+	isRuleTrue bool,
+) error {
+	if isRuleTrue {
+		return ai.welford.next(ai.spec.expr.Eval(record))
+	}
+	return nil
+}
+
+func (ai *varianceInstance) Result(
+	aggregatorInstances []Instance,
+	goals []*goal.Goal,
+	numRecords int64,
+) *dlit.Literal {
+	if ai.welford.numRecords == 0 {
+		return dlit.MustNew(0)
+	}
+	return roundTo(ai.welford.variance(), ai.welford.maxDP*2+2)
+}
+
+func newWelford() *welford {
+	return &welford{
+		mean:       dlit.MustNew(0),
+		m2:         dlit.MustNew(0),
+		numRecords: 0,
+		maxDP:      0,
+	}
+}
+
+// next updates the running mean and sum of squared differences with value
+func (w *welford) next(value *dlit.Literal) error {
+	if err := value.Err(); err != nil {
+		return err
+	}
+	if dp := numDecPlaces(value); dp > w.maxDP {
+		w.maxDP = dp
+	}
+	w.numRecords++
+	vars := map[string]*dlit.Literal{
+		"mean":  w.mean,
+		"value": value,
+		"n":     dlit.MustNew(w.numRecords),
+	}
+	mean := welfordMeanExpr.Eval(vars)
+	if err := mean.Err(); err != nil {
+		return err
+	}
+	vars = map[string]*dlit.Literal{
+		"m2":      w.m2,
+		"value":   value,
+		"oldMean": w.mean,
+		"mean":    mean,
+	}
+	m2 := welfordM2Expr.Eval(vars)
+	if err := m2.Err(); err != nil {
+		return err
+	}
+	w.mean = mean
+	w.m2 = m2
+	return nil
+}
+
+// variance returns the population variance of the values
+func (w *welford) variance() *dlit.Literal {
+	vars := map[string]*dlit.Literal{
+		"m2": w.m2,
+		"n":  dlit.MustNew(w.numRecords),
+	}
+	return varianceExpr.Eval(vars)
+}
@@ -0,0 +1,174 @@
+package aggregator
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/goal"
+	"reflect"
+	"testing"
+)
+
+func TestNewVariance_error(t *testing.T) {
+	_, err := New("a", "variance", "3+4+{")
+	wantErr := DescError{
+		Name: "a",
+		Kind: "variance",
+		Err: dexpr.InvalidExprError{
+			Expr: "3+4+{",
+			Err:  dexpr.ErrSyntax,
+		},
+	}.Error()
+	if err.Error() != wantErr {
+		t.Errorf("New: gotErr: %s, wantErr: %s", err, wantErr)
+	}
+}
+
+func TestVarianceResult(t *testing.T) {
+	records := makeSpreadRecords()
+	goals := []*goal.Goal{}
+	cases := []struct {
+		records []map[string]*dlit.Literal
+		rule    func(int) bool
+		want    float64
+	}{
+		{records, func(i int) bool { return i < 8 }, 4},
+		{records, func(i int) bool { return i >= 8 }, 0.25},
+		{records, func(i int) bool { return i == 3 }, 0},
+		{records, func(i int) bool { return false }, 0},
+		{[]map[string]*dlit.Literal{}, func(i int) bool { return true }, 0},
+	}
+	for ci, c := range cases {
+		varianceDesc := MustNew("varianceDuration", "variance", "duration")
+		variance := varianceDesc.New()
+		instances := []Instance{variance}
+
+		for i, record := range c.records {
+			if err := variance.NextRecord(record, c.rule(i)); err != nil {
+				t.Fatalf("(%d) NextRecord: %s", ci, err)
+			}
+		}
+		numRecords := int64(len(records))
+		got := variance.Result(instances, goals, numRecords)
+		gotFloat, gotIsFloat := got.Float()
+		if !gotIsFloat || gotFloat != c.want {
+			t.Errorf("(%d) - Result, got: %v, want: %f", ci, got, c.want)
+		}
+	}
+}
+
+func TestVarianceResult_stable(t *testing.T) {
+	// A large offset loses precision with the naive sum of squares method
+	offset := 1000000000.0
+	values := []float64{4, 7, 13, 16}
+	as := MustNew("a", "variance", "v")
+	ai := as.New()
+	for _, v := range values {
+		record := map[string]*dlit.Literal{"v": dlit.MustNew(offset + v)}
+		if err := ai.NextRecord(record, true); err != nil {
+			t.Fatalf("NextRecord: %s", err)
+		}
+	}
+	got := ai.Result([]Instance{ai}, []*goal.Goal{}, int64(len(values)))
+	gotFloat, gotIsFloat := got.Float()
+	if !gotIsFloat || gotFloat != 22.5 {
+		t.Errorf("Result, got: %v, want: 22.5", got)
+	}
+}
+
+func TestVarianceNextRecord_errors(t *testing.T) {
+	cases := []struct {
+		record map[string]*dlit.Literal
+		arg    string
+		want   error
+	}{
+		{record: map[string]*dlit.Literal{},
+			arg: "cost + 2",
+			want: dexpr.InvalidExprError{
+				Expr: "cost + 2",
+				Err:  dexpr.VarNotExistError("cost"),
+			},
+		},
+		{record: map[string]*dlit.Literal{"cost": dlit.NewString("hello")},
+			arg: "cost",
+			want: dexpr.InvalidExprError{
+				Expr: "mean+(value-mean)/n",
+				Err:  dexpr.ErrIncompatibleTypes,
+			},
+		},
+	}
+	for _, c := range cases {
+		as := MustNew("a", "variance", c.arg)
+		ai := as.New()
+		got := ai.NextRecord(c.record, true)
+		if got == nil || got.Error() != c.want.Error() {
+			t.Errorf("NextRecord: got: %s, want: %s", got, c.want)
+		}
+	}
+}
+
+func TestVarianceSpecName(t *testing.T) {
+	name := "a"
+	as := MustNew(name, "variance", "income - cost")
+	got := as.Name()
+	if got != name {
+		t.Errorf("Name - got: %s, want: %s", got, name)
+	}
+}
+
+func TestVarianceSpecKind(t *testing.T) {
+	kind := "variance"
+	as := MustNew("a", kind, "income - cost")
+	got := as.Kind()
+	if got != kind {
+		t.Errorf("Kind - got: %s, want: %s", got, kind)
+	}
+}
+
+func TestVarianceSpecArgs(t *testing.T) {
+	want := []string{"income - cost"}
+	as := MustNew("a", "variance", want...)
+	got := as.Args()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args - got: %q, want: %q", got, want)
+	}
+}
+
+func TestVarianceInstanceName(t *testing.T) {
+	as := MustNew("abc", "variance", "cost + 2")
+	ai := as.New()
+	got := ai.Name()
+	want := "abc"
+	if got != want {
+		t.Errorf("Name: got: %s, want: %s", got, want)
+	}
+}
+
+// makeSpreadRecords returns records where the first eight durations have
+// a mean of 5 and a variance of 4 and the last two have a mean of 2 and
+// a variance of 0.25
+func makeSpreadRecords() []map[string]*dlit.Literal {
+	durations := []float64{2, 4, 4, 4, 5, 5, 7, 9, 1.5, 2.5}
+	records := make([]map[string]*dlit.Literal, len(durations))
+	for i, d := range durations {
+		records[i] = map[string]*dlit.Literal{"duration": dlit.MustNew(d)}
+	}
+	return records
+}
+
+/*************************
+ *       Benchmarks
+ *************************/
+
+func BenchmarkVarianceNextRecord(b *testing.B) {
+	as := MustNew("a", "variance", "cost + 2")
+	ai := as.New()
+	record := map[string]*dlit.Literal{"cost": dlit.NewString("17.89245")}
+	for n := 0; n < b.N; n++ {
+		b.StartTimer()
+		got := ai.NextRecord(record, true)
+		b.StopTimer()
+		if got != nil {
+			b.Errorf("NextRecord: %s", got)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/lawrencewoodman/ddataset"
 	"github.com/vlifesystems/rhkit/aggregator"
 	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/bitset"
 	"github.com/vlifesystems/rhkit/rule"
 )
 
@@ -24,12 +25,23 @@ type Assessment struct {
 	aggregatorSpecs []aggregator.Spec
 	goals           []*goal.Goal
 	flags           map[string]bool
-	mux             sync.RWMutex
+	keepCoverage    bool
+	// earlyStop indicates whether AssessRules should drop rules that
+	// can't qualify before the end of a pass
+	earlyStop            bool
+	earlyStopSortOrder   []SortOrder
+	earlyStopMaxNumRules int
+	// refineOptions are the options used by Refine, if nil then
+	// DefaultRefineOptions are used
+	refineOptions *RefineOptions
+	mux           sync.RWMutex
 }
 
 type GoalAssessment struct {
 	Expr   string `json:"expr"`
 	Passed bool   `json:"passed"`
+	// Distance is how far the rule is from passing the goal, 0 if passed
+	Distance float64 `json:"distance"`
 }
 
 func New(aggregatorSpecs []aggregator.Spec, goals []*goal.Goal) *Assessment {
@@ -43,19 +55,41 @@ func New(aggregatorSpecs []aggregator.Spec, goals []*goal.Goal) *Assessment {
 	return a
 }
 
+// KeepCoverage sets whether each RuleAssessment should keep a compressed
+// bitset of the records that its rule matched.  This uses more memory
+// but allows And and Or rules made from already assessed rules to be
+// assessed without testing them against each record and allows Refine
+// to compare exactly which records rules match.  The coverage is
+// recorded by record number, so every call to AssessRules must use the
+// same Dataset, returning its records in the same order.  If the number
+// of records differs then AssessRules returns ErrNumRecordsChanged.
+func (a *Assessment) KeepCoverage(keep bool) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.keepCoverage = keep
+}
+
 func (a *Assessment) AddRules(rules []rule.Rule) {
 	a.mux.Lock()
 	defer a.mux.Unlock()
 	for _, rule := range rules {
 		a.RuleAssessments = append(
 			a.RuleAssessments,
-			newRuleAssessment(rule, a.aggregatorSpecs, a.goals),
+			a.newRuleAssessment(rule, nil),
 		)
 	}
 }
 
+// Sort sorts the RuleAssessments by each SortOrder in turn.  If a
+// SortOrder was created with NewParetoSortOrder then the rules are
+// ranked by Pareto front and the front of each RuleAssessment, for the
+// first such SortOrder, is recorded in its Front field.
 func (a *Assessment) Sort(s []SortOrder) {
-	sort.Sort(by{a.RuleAssessments, s})
+	values := newSortValues(a.RuleAssessments, s)
+	for _, ra := range a.RuleAssessments {
+		ra.Front = values.front(s, ra)
+	}
+	sort.Sort(by{a.RuleAssessments, s, values})
 	a.flags["sorted"] = true
 }
 
@@ -90,16 +124,6 @@ func (a *Assessment) IsEqual(o *Assessment) bool {
 	return true
 }
 
-// Refine removes ruleAssessments that are poorer than similar rules
-func (sortedAssessment *Assessment) Refine() {
-	if !sortedAssessment.IsSorted() {
-		panic("Assessment isn't sorted")
-	}
-	sortedAssessment.excludePoorRules()
-	sortedAssessment.excludeSameRecordsRules()
-	sortedAssessment.excludePoorerOverlappingRules()
-}
-
 func (a *Assessment) Merge(o *Assessment) (*Assessment, error) {
 	if a.NumRecords != o.NumRecords {
 		return nil, ErrNumRecordsChanged
@@ -108,6 +132,8 @@ func (a *Assessment) Merge(o *Assessment) (*Assessment, error) {
 	r := &Assessment{
 		NumRecords:      a.NumRecords,
 		RuleAssessments: newRuleAssessments,
+		keepCoverage:    a.keepCoverage && o.keepCoverage,
+		refineOptions:   a.refineOptions,
 	}
 	r.resetFlags()
 	return r, nil
@@ -140,8 +166,23 @@ func (a *Assessment) TruncateRuleAssessments(
 		if i >= maxRuleAssessments {
 			break
 		}
-		ruleAssessments = append(ruleAssessments, ra.clone())
+		ruleAssessments = append(ruleAssessments, ra)
 	}
+	return a.newTruncated(ruleAssessments, trueRuleAssessment, maxRuleAssessments)
+}
+
+// newTruncated returns a sorted Assessment containing clones of the
+// supplied RuleAssessments, making sure that it includes the True rule
+func (a *Assessment) newTruncated(
+	ruleAssessments []*RuleAssessment,
+	trueRuleAssessment *RuleAssessment,
+	maxRuleAssessments int,
+) *Assessment {
+	clonedRuleAssessments := make([]*RuleAssessment, len(ruleAssessments))
+	for i, ra := range ruleAssessments {
+		clonedRuleAssessments[i] = ra.clone()
+	}
+	ruleAssessments = clonedRuleAssessments
 
 	if getTrueRuleAssessment(ruleAssessments) == nil && maxRuleAssessments > 0 {
 		if len(ruleAssessments) >= maxRuleAssessments {
@@ -158,6 +199,8 @@ func (a *Assessment) TruncateRuleAssessments(
 		NumRecords:      a.NumRecords,
 		RuleAssessments: ruleAssessments,
 		flags:           flags,
+		keepCoverage:    a.keepCoverage,
+		refineOptions:   a.refineOptions,
 	}
 }
 
@@ -187,17 +230,34 @@ func (a *Assessment) Rules(args ...int) []rule.Rule {
 }
 
 // AssessRules assesses the given rules against a Dataset and
-// adds their assessment to the existing assessment.
+// adds their assessment to the existing assessment.  If coverage is
+// being kept then And and Or rules made from already assessed rules
+// use the coverage of those rules rather than being tested against
+// each record.  If EarlyStop has been used then rules that can't qualify
+// are dropped part way through the pass and aren't added.
 // This function is thread safe.
 func (a *Assessment) AssessRules(
 	dataset ddataset.Dataset,
 	rules []rule.Rule,
 ) error {
+	a.mux.RLock()
+	coverages := a.coverages()
 	ruleAssessments := make([]*RuleAssessment, len(rules))
 	for i, rule := range rules {
-		ruleAssessments[i] = newRuleAssessment(rule, a.aggregatorSpecs, a.goals)
+		ruleAssessments[i] = a.newRuleAssessment(rule, coverages)
+	}
+	expectedNumRecords := a.NumRecords
+	a.mux.RUnlock()
+	if expectedNumRecords == 0 {
+		expectedNumRecords = dataset.NumRecords()
+	} else if len(coverages) > 0 &&
+		dataset.NumRecords() != expectedNumRecords {
+		// The coverages are by record number and therefore can't be used
+		// with a different Dataset
+		return ErrNumRecordsChanged
 	}
-	numRecords, err := processDataset(dataset, ruleAssessments)
+	es := a.newEarlyStop(expectedNumRecords)
+	numRecords, err := processDataset(dataset, ruleAssessments, es)
 	if err != nil {
 		return err
 	}
@@ -238,9 +298,87 @@ func (a *Assessment) Update() error {
 	return nil
 }
 
+// newRuleAssessment creates a RuleAssessment for a rule, keeping its
+// coverage if required.  If the coverage of the rule can be worked out
+// from the supplied coverages then this is used.
+func (a *Assessment) newRuleAssessment(
+	r rule.Rule,
+	coverages map[string]*bitset.Bitset,
+) *RuleAssessment {
+	ra := newRuleAssessment(r, a.aggregatorSpecs, a.goals)
+	if !a.keepCoverage {
+		return ra
+	}
+	canonicalRule := rule.Canonical(r).String()
+	if coverage, ok := coverages[canonicalRule]; ok {
+		ra.setCoverage(coverage, canonicalRule)
+	} else if coverage, ok := coverageOf(r, coverages); ok {
+		ra.setCoverage(coverage, canonicalRule)
+	} else {
+		ra.keepCoverage(canonicalRule)
+	}
+	return ra
+}
+
+// coverages returns the coverage of each RuleAssessment that has one,
+// keyed by the canonical form of its rule
+func (a *Assessment) coverages() map[string]*bitset.Bitset {
+	coverages := map[string]*bitset.Bitset{}
+	if !a.keepCoverage {
+		return coverages
+	}
+	for _, ra := range a.RuleAssessments {
+		if ra.coverage != nil {
+			coverages[ra.canonicalRule] = ra.coverage
+		}
+	}
+	return coverages
+}
+
+// coverageOf returns the coverage of a rule if it is in coverages or,
+// for And and Or rules, if it can be worked out from the coverage of
+// the rules that they are made from.  The coverages must all come
+// from the same Dataset with its records in the same order.
+func coverageOf(
+	r rule.Rule,
+	coverages map[string]*bitset.Bitset,
+) (*bitset.Bitset, bool) {
+	if len(coverages) == 0 {
+		return nil, false
+	}
+	switch x := r.(type) {
+	case *rule.And:
+		if coverageA, ok := subCoverageOf(x.RuleA(), coverages); ok {
+			if coverageB, ok := subCoverageOf(x.RuleB(), coverages); ok {
+				return coverageA.And(coverageB), true
+			}
+		}
+	case *rule.Or:
+		if coverageA, ok := subCoverageOf(x.RuleA(), coverages); ok {
+			if coverageB, ok := subCoverageOf(x.RuleB(), coverages); ok {
+				return coverageA.Or(coverageB), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// subCoverageOf returns the coverage of a rule that is part of an And
+// or Or rule
+func subCoverageOf(
+	r rule.Rule,
+	coverages map[string]*bitset.Bitset,
+) (*bitset.Bitset, bool) {
+	if coverage, ok := coverages[rule.Canonical(r).String()]; ok {
+		return coverage, true
+	}
+	return coverageOf(r, coverages)
+}
+
 func processDataset(
 	dataset ddataset.Dataset,
 	ruleAssessments []*RuleAssessment,
+	es *earlyStop,
 ) (int64, error) {
 	numRecords := int64(0)
 	conn, err := dataset.Open()
@@ -249,19 +387,42 @@ func processDataset(
 	}
 	defer conn.Close()
 
+	decoder := compileRules(ruleAssessments)
 	for conn.Next() {
 		record := conn.Read()
+		decodedRecord := decoder.Decode(record)
 		numRecords++
 		for _, ruleAssessment := range ruleAssessments {
-			err := ruleAssessment.NextRecord(record)
+			err := ruleAssessment.nextRecord(record, decodedRecord)
 			if err != nil {
 				return numRecords, err
 			}
 		}
+		if es != nil && numRecords%earlyStopInterval == 0 {
+			es.check(ruleAssessments, numRecords)
+		}
 	}
 	return numRecords, conn.Err()
 }
 
+// compileRules compiles the rules of the RuleAssessments that need
+// testing against each record and returns a RecordDecoder for them
+func compileRules(ruleAssessments []*RuleAssessment) *rule.RecordDecoder {
+	rules := []rule.Rule{}
+	for _, ra := range ruleAssessments {
+		if !ra.coverageKnown {
+			rules = append(rules, ra.Rule)
+		}
+	}
+	decoder := rule.NewRecordDecoder(rule.RulesFields(rules))
+	for _, ra := range ruleAssessments {
+		if !ra.coverageKnown {
+			ra.compiledRule = rule.Compile(ra.Rule, decoder)
+		}
+	}
+	return decoder
+}
+
 func (a *Assessment) resetFlags() {
 	a.flags = map[string]bool{
 		"sorted": false,
@@ -275,6 +436,9 @@ func (a *Assessment) addRuleAssessments(
 	defer a.mux.Unlock()
 	a.resetFlags()
 	for _, ruleAssessment := range ruleAssessments {
+		if ruleAssessment.dropped {
+			continue
+		}
 		if err := ruleAssessment.update(a.NumRecords); err != nil {
 			return err
 		}
@@ -293,82 +457,6 @@ func (a *Assessment) addRuleAssessments(
 	return nil
 }
 
-func (sortedAssessment *Assessment) excludeSameRecordsRules() {
-	if len(sortedAssessment.RuleAssessments) < 2 {
-		return
-	}
-	lastAggregators := sortedAssessment.RuleAssessments[0].Aggregators
-	if len(lastAggregators) <= 3 {
-		return
-	}
-
-	goodRuleAssessments := make([]*RuleAssessment, 1)
-	goodRuleAssessments[0] = sortedAssessment.RuleAssessments[0]
-	for _, a := range sortedAssessment.RuleAssessments[1:] {
-		aggregatorsMatch := true
-		for k, v := range lastAggregators {
-			if a.Aggregators[k].String() != v.String() {
-				aggregatorsMatch = false
-				break
-			}
-		}
-		switch a.Rule.(type) {
-		case rule.True:
-			if aggregatorsMatch {
-				goodRuleAssessments[len(goodRuleAssessments)-1] = a
-			} else {
-				goodRuleAssessments = append(goodRuleAssessments, a)
-			}
-			break
-		default:
-			if !aggregatorsMatch {
-				goodRuleAssessments = append(goodRuleAssessments, a)
-			}
-		}
-		lastAggregators = a.Aggregators
-	}
-	sortedAssessment.RuleAssessments = goodRuleAssessments
-}
-
-func (sortedAssessment *Assessment) excludePoorRules() {
-	goodRuleAssessments := []*RuleAssessment{}
-	for _, a := range sortedAssessment.RuleAssessments {
-		percentMatches, percentMatchesIsFloat :=
-			a.Aggregators["percentMatches"].Float()
-		if !percentMatchesIsFloat {
-			panic("percentMatches aggregator isn't a float")
-		}
-		if percentMatches >= 0.5 {
-			goodRuleAssessments = append(goodRuleAssessments, a)
-		}
-	}
-	sortedAssessment.RuleAssessments = goodRuleAssessments
-}
-
-func (sortedAssessment *Assessment) excludePoorerOverlappingRules() {
-	goodRuleAssessments := make([]*RuleAssessment, 0)
-	for i, aI := range sortedAssessment.RuleAssessments {
-		switch xI := aI.Rule.(type) {
-		case rule.Overlapper:
-			overlaps := false
-			for j, aJ := range sortedAssessment.RuleAssessments {
-				if j >= i {
-					break
-				}
-				if xI.Overlaps(aJ.Rule) {
-					overlaps = true
-				}
-			}
-			if !overlaps {
-				goodRuleAssessments = append(goodRuleAssessments, aI)
-			}
-		default:
-			goodRuleAssessments = append(goodRuleAssessments, aI)
-		}
-	}
-	sortedAssessment.RuleAssessments = goodRuleAssessments
-}
-
 func (g *GoalAssessment) IsEqual(o *GoalAssessment) bool {
-	return g.Expr == o.Expr && g.Passed == o.Passed
+	return g.Expr == o.Expr && g.Passed == o.Passed && g.Distance == o.Distance
 }
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/lawrencewoodman/ddataset"
 	"github.com/lawrencewoodman/ddataset/dcsv"
 	"github.com/lawrencewoodman/ddataset/dtruncate"
 	"github.com/lawrencewoodman/dexpr"
@@ -23,8 +24,8 @@ func TestAssessRules(t *testing.T) {
 		rule.NewGEFV("cost", dlit.MustNew(1.3)),
 	}
 	aggregatorDescs := []*aggregator.Desc{
-		{"numIncomeGt2", "count", "income > 2"},
-		{"numBandGt4", "count", "band > 4"},
+		aggregator.NewDesc("numIncomeGt2", "count", "income > 2"),
+		aggregator.NewDesc("numBandGt4", "count", "band > 4"),
 	}
 	goalExprs := []string{
 		"numIncomeGt2 == 1",
@@ -65,14 +66,14 @@ func TestAssessRules(t *testing.T) {
 				"goalsScore":     dlit.MustNew(1.001),
 			},
 			Goals: []*GoalAssessment{
-				{"numIncomeGt2 == 1", true},
-				{"numIncomeGt2 == 2", false},
-				{"numIncomeGt2 == 3", false},
-				{"numIncomeGt2 == 4", false},
-				{"numBandGt4 == 1", false},
-				{"numBandGt4 == 2", true},
-				{"numBandGt4 == 3", false},
-				{"numBandGt4 == 4", false},
+				{"numIncomeGt2 == 1", true, 0},
+				{"numIncomeGt2 == 2", false, 0.5},
+				{"numIncomeGt2 == 3", false, 2.0 / 3},
+				{"numIncomeGt2 == 4", false, 0.75},
+				{"numBandGt4 == 1", false, 0.5},
+				{"numBandGt4 == 2", true, 0},
+				{"numBandGt4 == 3", false, 1.0 / 3},
+				{"numBandGt4 == 4", false, 0.5},
 			},
 		},
 		{
@@ -85,14 +86,14 @@ func TestAssessRules(t *testing.T) {
 				"goalsScore":     dlit.MustNew(0.002),
 			},
 			Goals: []*GoalAssessment{
-				{"numIncomeGt2 == 1", false},
-				{"numIncomeGt2 == 2", true},
-				{"numIncomeGt2 == 3", false},
-				{"numIncomeGt2 == 4", false},
-				{"numBandGt4 == 1", false},
-				{"numBandGt4 == 2", true},
-				{"numBandGt4 == 3", false},
-				{"numBandGt4 == 4", false},
+				{"numIncomeGt2 == 1", false, 0.5},
+				{"numIncomeGt2 == 2", true, 0},
+				{"numIncomeGt2 == 3", false, 1.0 / 3},
+				{"numIncomeGt2 == 4", false, 0.5},
+				{"numBandGt4 == 1", false, 0.5},
+				{"numBandGt4 == 2", true, 0},
+				{"numBandGt4 == 3", false, 1.0 / 3},
+				{"numBandGt4 == 4", false, 0.5},
 			},
 		},
 		{
@@ -105,14 +106,14 @@ func TestAssessRules(t *testing.T) {
 				"goalsScore":     dlit.MustNew(0.002),
 			},
 			Goals: []*GoalAssessment{
-				{"numIncomeGt2 == 1", false},
-				{"numIncomeGt2 == 2", true},
-				{"numIncomeGt2 == 3", false},
-				{"numIncomeGt2 == 4", false},
-				{"numBandGt4 == 1", true},
-				{"numBandGt4 == 2", false},
-				{"numBandGt4 == 3", false},
-				{"numBandGt4 == 4", false},
+				{"numIncomeGt2 == 1", false, 0.5},
+				{"numIncomeGt2 == 2", true, 0},
+				{"numIncomeGt2 == 3", false, 1.0 / 3},
+				{"numIncomeGt2 == 4", false, 0.5},
+				{"numBandGt4 == 1", true, 0},
+				{"numBandGt4 == 2", false, 0.5},
+				{"numBandGt4 == 3", false, 2.0 / 3},
+				{"numBandGt4 == 4", false, 0.75},
 			},
 		},
 	}
@@ -158,8 +159,8 @@ func TestAssessRules_goroutines(t *testing.T) {
 		allRules[i] = rules
 	}
 	aggregatorDescs := []*aggregator.Desc{
-		{"numIncomeGt2", "count", "income > 2"},
-		{"numBandGt4", "count", "band > 4"},
+		aggregator.NewDesc("numIncomeGt2", "count", "income > 2"),
+		aggregator.NewDesc("numBandGt4", "count", "band > 4"),
 	}
 	goalExprs := []string{
 		"numIncomeGt2 == 1",
@@ -227,6 +228,127 @@ func TestAssessRules_goroutines(t *testing.T) {
 	}
 }
 
+func TestAssessRules_coverage(t *testing.T) {
+	numIsTrue := 0
+	bandGE5 := &countingRule{rule.NewGEFV("band", dlit.MustNew(5)), &numIsTrue}
+	incomeGE2 := &countingRule{rule.NewGEFV("income", dlit.MustNew(2)), &numIsTrue}
+	costLE2 := &countingRule{rule.NewLEFV("cost", dlit.MustNew(2)), &numIsTrue}
+	fields := []string{"income", "cost", "band"}
+	records := [][]string{
+		{"3", "4.5", "4"},
+		{"3", "3.2", "7"},
+		{"2", "1.2", "4"},
+		{"0", "0", "9"},
+		{"1", "1.9", "5"},
+		{"4", "0.5", "6"},
+	}
+	dataset := testhelpers.NewLiteralDataset(fields, records)
+	aggregatorDescs := []*aggregator.Desc{
+		aggregator.NewDesc("numIncomeGt2", "count", "income > 2"),
+	}
+	aggregatorSpecs, err := aggregator.MakeSpecs(fields, aggregatorDescs)
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	goals, err := goal.MakeGoals([]string{"numIncomeGt2 == 1"})
+	if err != nil {
+		t.Fatalf("MakeGoals: %s", err)
+	}
+	combinedRules := []rule.Rule{
+		rule.MustNewAnd(bandGE5, incomeGE2),
+		rule.MustNewOr(bandGE5, incomeGE2),
+		rule.MustNewAnd(rule.MustNewOr(bandGE5, costLE2), incomeGE2),
+	}
+
+	ass := New(aggregatorSpecs, goals)
+	ass.KeepCoverage(true)
+	baseRules := []rule.Rule{bandGE5, incomeGE2, costLE2}
+	if err := ass.AssessRules(dataset, baseRules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	wantNumIsTrue := len(baseRules) * len(records)
+	if numIsTrue != wantNumIsTrue {
+		t.Fatalf("IsTrue called: %d times, want: %d", numIsTrue, wantNumIsTrue)
+	}
+	if err := ass.AssessRules(dataset, combinedRules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	if numIsTrue != wantNumIsTrue {
+		t.Errorf("IsTrue called: %d times, want: %d", numIsTrue, wantNumIsTrue)
+	}
+
+	wantAss := New(aggregatorSpecs, goals)
+	if err := wantAss.AssessRules(dataset, baseRules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	if err := wantAss.AssessRules(dataset, combinedRules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	if !ass.IsEqual(wantAss) {
+		t.Errorf("AssessRules got: %v, want: %v",
+			ass.RuleAssessments, wantAss.RuleAssessments)
+	}
+	for _, ra := range ass.RuleAssessments {
+		if !ra.HasCoverage() {
+			t.Errorf("HasCoverage got: false, rule: %s", ra.Rule)
+		}
+		numMatches, _ := ra.Aggregators["numMatches"].Int()
+		if n := ra.coverage.Count(); n != numMatches {
+			t.Errorf("coverage.Count() got: %d, want: %d, rule: %s",
+				n, numMatches, ra.Rule)
+		}
+	}
+	for _, ra := range wantAss.RuleAssessments {
+		if ra.HasCoverage() {
+			t.Errorf("HasCoverage got: true, rule: %s", ra.Rule)
+		}
+	}
+}
+
+func TestAssessRules_coverageNumRecordsChanged(t *testing.T) {
+	fields := []string{"income", "band"}
+	records := [][]string{
+		{"3", "4"},
+		{"3", "7"},
+		{"2", "4"},
+	}
+	dataset := testhelpers.NewLiteralDataset(fields, records)
+	shortDataset := testhelpers.NewLiteralDataset(fields, records[:2])
+	aggregatorSpecs, err := aggregator.MakeSpecs(fields, []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	bandGE5 := rule.NewGEFV("band", dlit.MustNew(5))
+	incomeGE3 := rule.NewGEFV("income", dlit.MustNew(3))
+	ass := New(aggregatorSpecs, []*goal.Goal{})
+	ass.KeepCoverage(true)
+	if err := ass.AssessRules(dataset, []rule.Rule{bandGE5, incomeGE3}); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	err = ass.AssessRules(
+		shortDataset,
+		[]rule.Rule{rule.MustNewAnd(bandGE5, incomeGE3)},
+	)
+	if err != ErrNumRecordsChanged {
+		t.Errorf("AssessRules got err: %v, want: %s", err, ErrNumRecordsChanged)
+	}
+	if len(ass.RuleAssessments) != 2 {
+		t.Errorf("AssessRules got len(RuleAssessments): %d, want: 2",
+			len(ass.RuleAssessments))
+	}
+}
+
+// countingRule counts the number of times IsTrue is called
+type countingRule struct {
+	rule.Rule
+	numIsTrue *int
+}
+
+func (r *countingRule) IsTrue(record ddataset.Record) (bool, error) {
+	*r.numIsTrue++
+	return r.Rule.IsTrue(record)
+}
+
 func TestAssessRules_errors(t *testing.T) {
 	cases := []struct {
 		rules           []rule.Rule
@@ -236,14 +358,18 @@ func TestAssessRules_errors(t *testing.T) {
 	}{
 		{[]rule.Rule{rule.NewGEFV("hand", dlit.MustNew(3))},
 			[]*aggregator.Desc{
-				{"numIncomeGt2", "count", "income > 2"},
+				aggregator.NewDesc(
+					"numIncomeGt2", "count", "income > 2",
+				),
 			},
 			[]string{"numIncomeGt2 == 1"},
 			rule.InvalidRuleError{Rule: rule.NewGEFV("hand", dlit.MustNew(3))},
 		},
 		{[]rule.Rule{rule.NewGEFV("band", dlit.MustNew(3))},
 			[]*aggregator.Desc{
-				{"numIncomeGt2", "count", "bincome > 2"},
+				aggregator.NewDesc(
+					"numIncomeGt2", "count", "bincome > 2",
+				),
 			},
 			[]string{"numIncomeGt2 == 1"},
 			AggregatorError{
@@ -256,7 +382,9 @@ func TestAssessRules_errors(t *testing.T) {
 		},
 		{[]rule.Rule{rule.NewGEFV("band", dlit.MustNew(3))},
 			[]*aggregator.Desc{
-				{"numIncomeGt2", "count", "income > 2"},
+				aggregator.NewDesc(
+					"numIncomeGt2", "count", "income > 2",
+				),
 			},
 			[]string{"numIncomeGt == 1"},
 			dexpr.InvalidExprError{
@@ -294,8 +422,8 @@ func TestProcessRecord(t *testing.T) {
 		rule.NewGEFV("cost", dlit.MustNew(1.3)),
 	}
 	aggregatorDescs := []*aggregator.Desc{
-		{"numIncomeGt2", "count", "income > 2"},
-		{"numBandGt4", "count", "band > 4"},
+		aggregator.NewDesc("numIncomeGt2", "count", "income > 2"),
+		aggregator.NewDesc("numBandGt4", "count", "band > 4"),
 	}
 	goalExprs := []string{
 		"numIncomeGt2 == 1",
@@ -334,14 +462,14 @@ func TestProcessRecord(t *testing.T) {
 				"goalsScore":     dlit.MustNew(0),
 			},
 			Goals: []*GoalAssessment{
-				{"numIncomeGt2 == 1", false},
-				{"numIncomeGt2 == 2", false},
-				{"numIncomeGt2 == 3", false},
-				{"numIncomeGt2 == 4", false},
-				{"numBandGt4 == 1", false},
-				{"numBandGt4 == 2", false},
-				{"numBandGt4 == 3", false},
-				{"numBandGt4 == 4", false},
+				{"numIncomeGt2 == 1", false, 1},
+				{"numIncomeGt2 == 2", false, 1},
+				{"numIncomeGt2 == 3", false, 1},
+				{"numIncomeGt2 == 4", false, 1},
+				{"numBandGt4 == 1", false, 1},
+				{"numBandGt4 == 2", false, 1},
+				{"numBandGt4 == 3", false, 1},
+				{"numBandGt4 == 4", false, 1},
 			},
 		},
 		{
@@ -354,14 +482,14 @@ func TestProcessRecord(t *testing.T) {
 				"goalsScore":     dlit.MustNew(1),
 			},
 			Goals: []*GoalAssessment{
-				{"numIncomeGt2 == 1", true},
-				{"numIncomeGt2 == 2", false},
-				{"numIncomeGt2 == 3", false},
-				{"numIncomeGt2 == 4", false},
-				{"numBandGt4 == 1", false},
-				{"numBandGt4 == 2", false},
-				{"numBandGt4 == 3", false},
-				{"numBandGt4 == 4", false},
+				{"numIncomeGt2 == 1", true, 0},
+				{"numIncomeGt2 == 2", false, 0.5},
+				{"numIncomeGt2 == 3", false, 2.0 / 3},
+				{"numIncomeGt2 == 4", false, 0.75},
+				{"numBandGt4 == 1", false, 1},
+				{"numBandGt4 == 2", false, 1},
+				{"numBandGt4 == 3", false, 1},
+				{"numBandGt4 == 4", false, 1},
 			},
 		},
 		{
@@ -374,14 +502,14 @@ func TestProcessRecord(t *testing.T) {
 				"goalsScore":     dlit.MustNew(1),
 			},
 			Goals: []*GoalAssessment{
-				{"numIncomeGt2 == 1", true},
-				{"numIncomeGt2 == 2", false},
-				{"numIncomeGt2 == 3", false},
-				{"numIncomeGt2 == 4", false},
-				{"numBandGt4 == 1", false},
-				{"numBandGt4 == 2", false},
-				{"numBandGt4 == 3", false},
-				{"numBandGt4 == 4", false},
+				{"numIncomeGt2 == 1", true, 0},
+				{"numIncomeGt2 == 2", false, 0.5},
+				{"numIncomeGt2 == 3", false, 2.0 / 3},
+				{"numIncomeGt2 == 4", false, 0.75},
+				{"numBandGt4 == 1", false, 1},
+				{"numBandGt4 == 2", false, 1},
+				{"numBandGt4 == 3", false, 1},
+				{"numBandGt4 == 4", false, 1},
 			},
 		},
 	}
@@ -415,8 +543,8 @@ func TestAssessmentAssessRules_dataset_changed_error(t *testing.T) {
 		rule.NewGEFV("cost", dlit.MustNew(1.3)),
 	}
 	aggregatorDescs := []*aggregator.Desc{
-		{"numIncomeGt2", "count", "income > 2"},
-		{"numBandGt4", "count", "band > 4"},
+		aggregator.NewDesc("numIncomeGt2", "count", "income > 2"),
+		aggregator.NewDesc("numBandGt4", "count", "band > 4"),
 	}
 	goalExprs := []string{
 		"numIncomeGt2 == 1",
@@ -484,7 +612,7 @@ func TestRules(t *testing.T) {
 					"percentMatches": dlit.MustNew("65.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -494,7 +622,7 @@ func TestRules(t *testing.T) {
 					"percentMatches": dlit.MustNew("50"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -504,7 +632,7 @@ func TestRules(t *testing.T) {
 					"percentMatches": dlit.MustNew("76.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -514,7 +642,7 @@ func TestRules(t *testing.T) {
 					"percentMatches": dlit.MustNew("50"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 		},
@@ -579,7 +707,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("65.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -589,7 +717,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("50"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -599,7 +727,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("76.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -609,7 +737,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("50"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 		},
@@ -627,7 +755,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("5.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -637,7 +765,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("19"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -647,7 +775,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("6.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -657,7 +785,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("3.5"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 		},
@@ -676,7 +804,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("65.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -686,7 +814,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("50"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -696,7 +824,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("76.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -706,7 +834,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("50"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -716,7 +844,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("5.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -726,7 +854,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("19"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -736,7 +864,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("6.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 			{
@@ -746,7 +874,7 @@ func TestMerge(t *testing.T) {
 					"percentMatches": dlit.MustNew("3.5"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 		},
@@ -777,7 +905,7 @@ func TestMerge_errors(t *testing.T) {
 					"percentMatches": dlit.MustNew("65.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -787,7 +915,7 @@ func TestMerge_errors(t *testing.T) {
 					"percentMatches": dlit.MustNew("50"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 		},
@@ -801,7 +929,7 @@ func TestMerge_errors(t *testing.T) {
 					"percentMatches": dlit.MustNew("5.3"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", true},
+					{"numMatches > 3 ", true, 0},
 				},
 			},
 			{
@@ -811,7 +939,7 @@ func TestMerge_errors(t *testing.T) {
 					"percentMatches": dlit.MustNew("19"),
 				},
 				Goals: []*GoalAssessment{
-					{"numMatches > 3 ", false},
+					{"numMatches > 3 ", false, 0},
 				},
 			},
 		},
@@ -839,8 +967,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -852,8 +980,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -868,8 +996,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -881,8 +1009,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -897,8 +1025,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -910,8 +1038,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -923,8 +1051,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -936,8 +1064,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -949,8 +1077,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -962,8 +1090,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -975,8 +1103,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -988,8 +1116,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -1001,8 +1129,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 			{
@@ -1014,8 +1142,8 @@ func TestRefine(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 		},
@@ -1038,6 +1166,61 @@ func TestRefine(t *testing.T) {
 	}
 }
 
+func TestRefine_coverage(t *testing.T) {
+	fields := []string{"band", "team"}
+	records := [][]string{
+		{"4", "a"},
+		{"7", "b"},
+		{"5", "b"},
+		{"9", "c"},
+	}
+	dataset := testhelpers.NewLiteralDataset(fields, records)
+	aggregatorSpecs, err := aggregator.MakeSpecs(fields, []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	rules := []rule.Rule{
+		rule.NewGEFV("band", dlit.MustNew(5)),
+		rule.NewInFV("team", testhelpers.MakeStringsDlitSlice("b", "c")),
+		rule.NewLEFV("band", dlit.MustNew(4)),
+		rule.NewTrue(),
+	}
+	cases := []struct {
+		keepCoverage bool
+		wantRules    []rule.Rule
+	}{
+		{keepCoverage: false,
+			wantRules: []rule.Rule{
+				rule.NewGEFV("band", dlit.MustNew(5)),
+				rule.NewInFV("team", testhelpers.MakeStringsDlitSlice("b", "c")),
+				rule.NewLEFV("band", dlit.MustNew(4)),
+				rule.NewTrue(),
+			},
+		},
+		{keepCoverage: true,
+			wantRules: []rule.Rule{
+				rule.NewGEFV("band", dlit.MustNew(5)),
+				rule.NewLEFV("band", dlit.MustNew(4)),
+				rule.NewTrue(),
+			},
+		},
+	}
+	for i, c := range cases {
+		ass := New(aggregatorSpecs, []*goal.Goal{})
+		ass.KeepCoverage(c.keepCoverage)
+		if err := ass.AssessRules(dataset, rules); err != nil {
+			t.Fatalf("(%d) AssessRules: %s", i, err)
+		}
+		ass.flags["sorted"] = true
+		ass.Refine()
+		gotRules := ass.Rules()
+		if !matchRules(gotRules, c.wantRules) {
+			t.Errorf("(%d) matchRules() rules don't match:\ngot: %s\nwant: %s\n",
+				i, gotRules, c.wantRules)
+		}
+	}
+}
+
 func TestRefine_few_ruleassessments(t *testing.T) {
 	cases := []struct {
 		in        *Assessment
@@ -1058,8 +1241,8 @@ func TestRefine_few_ruleassessments(t *testing.T) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 			},
@@ -1080,8 +1263,8 @@ func TestRefine_few_ruleassessments(t *testing.T) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -1093,8 +1276,8 @@ func TestRefine_few_ruleassessments(t *testing.T) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 			},
@@ -1132,8 +1315,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1145,8 +1328,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1162,8 +1345,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1175,8 +1358,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1192,8 +1375,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1205,8 +1388,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1218,8 +1401,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1235,8 +1418,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1252,8 +1435,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1269,8 +1452,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1286,8 +1469,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1299,8 +1482,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1312,8 +1495,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1329,8 +1512,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1342,8 +1525,8 @@ func TestRefine_between(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 		},
@@ -1394,8 +1577,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1407,8 +1590,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1424,8 +1607,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1437,8 +1620,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1454,8 +1637,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1467,8 +1650,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1480,8 +1663,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1497,8 +1680,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
 				},
 			},
 			{
@@ -1510,8 +1693,8 @@ func TestRefine_outside(t *testing.T) {
 					"goalsScore":     dlit.MustNew(0.1),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
 				},
 			},
 		},
@@ -1587,7 +1770,7 @@ func TestTruncateRuleAssessments(t *testing.T) {
 					"numMatches": dlit.MustNew("2"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
+					{"numIncomeGt2 == 1", true, 0},
 				},
 			},
 			{
@@ -1599,7 +1782,7 @@ func TestTruncateRuleAssessments(t *testing.T) {
 					"numMatches": dlit.MustNew("4"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
+					{"numIncomeGt2 == 1", false, 0},
 				},
 			},
 			{
@@ -1608,7 +1791,7 @@ func TestTruncateRuleAssessments(t *testing.T) {
 					"numMatches": dlit.MustNew("4"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
+					{"numIncomeGt2 == 1", false, 0},
 				},
 			},
 			{
@@ -1620,7 +1803,7 @@ func TestTruncateRuleAssessments(t *testing.T) {
 					"numMatches": dlit.MustNew("4"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
+					{"numIncomeGt2 == 1", false, 0},
 				},
 			},
 			{
@@ -1629,7 +1812,7 @@ func TestTruncateRuleAssessments(t *testing.T) {
 					"numMatches": dlit.MustNew("2"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
+					{"numIncomeGt2 == 1", false, 0},
 				},
 			},
 			{
@@ -1641,7 +1824,7 @@ func TestTruncateRuleAssessments(t *testing.T) {
 					"numMatches": dlit.MustNew("4"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
+					{"numIncomeGt2 == 1", false, 0},
 				},
 			},
 		},
@@ -1821,14 +2004,14 @@ func TestSort(t *testing.T) {
 					"numBandGt4":     dlit.MustNew("2"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
-					{"numIncomeGt2 == 3", false},
-					{"numIncomeGt2 == 4", false},
-					{"numBandGt4 == 1", false},
-					{"numBandGt4 == 2", true},
-					{"numBandGt4 == 3", false},
-					{"numBandGt4 == 4", true},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
+					{"numIncomeGt2 == 3", false, 0},
+					{"numIncomeGt2 == 4", false, 0},
+					{"numBandGt4 == 1", false, 0},
+					{"numBandGt4 == 2", true, 0},
+					{"numBandGt4 == 3", false, 0},
+					{"numBandGt4 == 4", true, 0},
 				},
 			},
 			{
@@ -1841,14 +2024,14 @@ func TestSort(t *testing.T) {
 					"numBandGt4":     dlit.MustNew("2"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", true},
-					{"numIncomeGt2 == 2", false},
-					{"numIncomeGt2 == 3", false},
-					{"numIncomeGt2 == 4", false},
-					{"numBandGt4 == 1", false},
-					{"numBandGt4 == 2", true},
-					{"numBandGt4 == 3", false},
-					{"numBandGt4 == 4", false},
+					{"numIncomeGt2 == 1", true, 0},
+					{"numIncomeGt2 == 2", false, 0},
+					{"numIncomeGt2 == 3", false, 0},
+					{"numIncomeGt2 == 4", false, 0},
+					{"numBandGt4 == 1", false, 0},
+					{"numBandGt4 == 2", true, 0},
+					{"numBandGt4 == 3", false, 0},
+					{"numBandGt4 == 4", false, 0},
 				},
 			},
 			{
@@ -1861,14 +2044,14 @@ func TestSort(t *testing.T) {
 					"numBandGt4":     dlit.MustNew("2"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
-					{"numIncomeGt2 == 3", false},
-					{"numIncomeGt2 == 4", false},
-					{"numBandGt4 == 1", false},
-					{"numBandGt4 == 2", true},
-					{"numBandGt4 == 3", false},
-					{"numBandGt4 == 4", false},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
+					{"numIncomeGt2 == 3", false, 0},
+					{"numIncomeGt2 == 4", false, 0},
+					{"numBandGt4 == 1", false, 0},
+					{"numBandGt4 == 2", true, 0},
+					{"numBandGt4 == 3", false, 0},
+					{"numBandGt4 == 4", false, 0},
 				},
 			},
 			{
@@ -1881,14 +2064,14 @@ func TestSort(t *testing.T) {
 					"numBandGt4":     dlit.MustNew("1"),
 				},
 				Goals: []*GoalAssessment{
-					{"numIncomeGt2 == 1", false},
-					{"numIncomeGt2 == 2", true},
-					{"numIncomeGt2 == 3", false},
-					{"numIncomeGt2 == 4", false},
-					{"numBandGt4 == 1", true},
-					{"numBandGt4 == 2", false},
-					{"numBandGt4 == 3", false},
-					{"numBandGt4 == 4", false},
+					{"numIncomeGt2 == 1", false, 0},
+					{"numIncomeGt2 == 2", true, 0},
+					{"numIncomeGt2 == 3", false, 0},
+					{"numIncomeGt2 == 4", false, 0},
+					{"numBandGt4 == 1", true, 0},
+					{"numBandGt4 == 2", false, 0},
+					{"numBandGt4 == 3", false, 0},
+					{"numBandGt4 == 4", false, 0},
 				},
 			},
 		},
@@ -1898,7 +2081,7 @@ func TestSort(t *testing.T) {
 		wantRules []rule.Rule
 	}{
 		{[]SortOrder{
-			{"goalsScore", ASCENDING},
+			{Aggregator: "goalsScore", Direction: ASCENDING},
 		},
 			[]rule.Rule{
 				rule.NewGEFV("band", dlit.MustNew(3)),
@@ -1907,7 +2090,7 @@ func TestSort(t *testing.T) {
 				rule.NewGEFV("band", dlit.MustNew(456)),
 			}},
 		{[]SortOrder{
-			{"percentMatches", DESCENDING},
+			{Aggregator: "percentMatches", Direction: DESCENDING},
 		},
 			[]rule.Rule{
 				rule.NewGEFV("band", dlit.MustNew(3)),
@@ -1916,7 +2099,7 @@ func TestSort(t *testing.T) {
 				rule.NewGEFV("cost", dlit.MustNew(1.2)),
 			}},
 		{[]SortOrder{
-			{"percentMatches", ASCENDING},
+			{Aggregator: "percentMatches", Direction: ASCENDING},
 		},
 			[]rule.Rule{
 				rule.NewGEFV("band", dlit.MustNew(456)),
@@ -1925,8 +2108,8 @@ func TestSort(t *testing.T) {
 				rule.NewGEFV("band", dlit.MustNew(3)),
 			}},
 		{[]SortOrder{
-			{"percentMatches", ASCENDING},
-			{"numIncomeGt2", ASCENDING},
+			{Aggregator: "percentMatches", Direction: ASCENDING},
+			{Aggregator: "numIncomeGt2", Direction: ASCENDING},
 		},
 			[]rule.Rule{
 				rule.NewGEFV("cost", dlit.MustNew(1.2)),
@@ -1935,8 +2118,8 @@ func TestSort(t *testing.T) {
 				rule.NewGEFV("band", dlit.MustNew(3)),
 			}},
 		{[]SortOrder{
-			{"percentMatches", DESCENDING},
-			{"numIncomeGt2", ASCENDING},
+			{Aggregator: "percentMatches", Direction: DESCENDING},
+			{Aggregator: "numIncomeGt2", Direction: ASCENDING},
 		},
 			[]rule.Rule{
 				rule.NewGEFV("band", dlit.MustNew(3)),
@@ -1990,11 +2173,13 @@ func BenchmarkAssessRules(b *testing.B) {
 		fields,
 	)
 	aggregatorDescs := []*aggregator.Desc{
-		{"numMarried", "count", "marital == \"married\""},
-		{"numSignedUp", "count", "y == \"yes\""},
-		{"cost", "calc", "numMatches * 4.5"},
-		{"income", "calc", "numSignedUp * 24"},
-		{"profit", "calc", "income - cost"},
+		aggregator.NewDesc(
+			"numMarried", "count", "marital == \"married\"",
+		),
+		aggregator.NewDesc("numSignedUp", "count", "y == \"yes\""),
+		aggregator.NewDesc("cost", "calc", "numMatches * 4.5"),
+		aggregator.NewDesc("income", "calc", "numSignedUp * 24"),
+		aggregator.NewDesc("profit", "calc", "income - cost"),
 	}
 	goalExprs := []string{
 		"profit > 0",
@@ -2037,8 +2222,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", true},
-						{"numIncomeGt2 == 2", false},
+						{"numIncomeGt2 == 1", true, 0},
+						{"numIncomeGt2 == 2", false, 0},
 					},
 				},
 				{
@@ -2050,8 +2235,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", true},
-						{"numIncomeGt2 == 2", false},
+						{"numIncomeGt2 == 1", true, 0},
+						{"numIncomeGt2 == 2", false, 0},
 					},
 				},
 				{
@@ -2066,8 +2251,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2079,8 +2264,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2095,8 +2280,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2108,8 +2293,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2121,8 +2306,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2134,8 +2319,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2147,8 +2332,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2160,8 +2345,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2173,8 +2358,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2186,8 +2371,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2199,8 +2384,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 				{
@@ -2212,8 +2397,8 @@ func BenchmarkRefine(b *testing.B) {
 						"goalsScore":     dlit.MustNew(0.1),
 					},
 					Goals: []*GoalAssessment{
-						{"numIncomeGt2 == 1", false},
-						{"numIncomeGt2 == 2", true},
+						{"numIncomeGt2 == 1", false, 0},
+						{"numIncomeGt2 == 2", true, 0},
 					},
 				},
 			},
@@ -2288,3 +2473,79 @@ func areAssessmentsEqv(
 	}
 	return true
 }
+
+func TestSort_complexity(t *testing.T) {
+	fields := []string{"band", "cost", "team"}
+	records := [][]string{
+		{"1", "2", "a"},
+		{"5", "7", "b"},
+		{"7", "3", "c"},
+	}
+	dataset := testhelpers.NewLiteralDataset(fields, records)
+	aggregatorSpecs, err := aggregator.MakeSpecs(
+		fields,
+		[]*aggregator.Desc{{Name: "complexity", Kind: "complexity"}},
+	)
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	rules := []rule.Rule{
+		rule.NewInFV("team", testhelpers.MakeStringsDlitSlice("a", "b", "c")),
+		rule.NewAddGEF("band", "cost", dlit.MustNew(5)),
+		rule.NewGEFF("band", "cost"),
+		rule.NewEQFV("team", dlit.MustNew("a")),
+		rule.NewTrue(),
+	}
+	wantRules := []rule.Rule{
+		rule.NewTrue(),
+		rule.NewEQFV("team", dlit.MustNew("a")),
+		rule.NewGEFF("band", "cost"),
+		rule.NewAddGEF("band", "cost", dlit.MustNew(5)),
+		rule.NewInFV("team", testhelpers.MakeStringsDlitSlice("a", "b", "c")),
+	}
+	wantComplexities := []int64{0, 1, 2, 3, 3}
+	assessment := New(aggregatorSpecs, []*goal.Goal{})
+	if err := assessment.AssessRules(dataset, rules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	assessment.Sort([]SortOrder{{Aggregator: "complexity", Direction: ASCENDING}})
+	for i, ra := range assessment.RuleAssessments {
+		if ra.Rule.String() != wantRules[i].String() {
+			t.Errorf("Sort: (%d) got rule: %s, want: %s", i, ra.Rule, wantRules[i])
+		}
+		got, ok := ra.Aggregators["complexity"].Int()
+		if !ok || got != wantComplexities[i] {
+			t.Errorf("Sort: (%d) got complexity: %s, want: %d",
+				i, ra.Aggregators["complexity"], wantComplexities[i])
+		}
+	}
+}
+
+func TestSort_tieBreakComplexity(t *testing.T) {
+	fields := []string{"x", "y"}
+	records := [][]string{
+		{"1", "2"},
+		{"5", "7"},
+	}
+	dataset := testhelpers.NewLiteralDataset(fields, records)
+	rules := []rule.Rule{
+		rule.NewGEFF("x", "y"),
+		rule.NewGEFV("x", dlit.MustNew(100)),
+		rule.NewTrue(),
+	}
+	wantRules := []string{"true()", "x >= 100", "x >= y"}
+	aggregatorSpecs, err := aggregator.MakeSpecs(fields, []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	assessment := New(aggregatorSpecs, []*goal.Goal{})
+	if err := assessment.AssessRules(dataset, rules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	assessment.Sort([]SortOrder{})
+	for i, ra := range assessment.RuleAssessments {
+		if ra.Rule.String() != wantRules[i] {
+			t.Errorf("Sort: (%d) got rule: %s, want: %s", i, ra.Rule, wantRules[i])
+		}
+	}
+}
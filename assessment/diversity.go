@@ -0,0 +1,134 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package assessment
+
+// Similarity describes how the similarity of two rules is measured when
+// selecting a diverse set of rules
+type Similarity int
+
+const (
+	// CoverageSimilarity measures how much the records matched by two
+	// rules overlap.  If the coverage of either rule hasn't been kept
+	// then FieldSimilarity is used instead.
+	CoverageSimilarity Similarity = iota
+	// FieldSimilarity measures how many fields two rules share
+	FieldSimilarity
+)
+
+// DiversityOptions control how TruncateRuleAssessmentsDiverse selects
+// rules
+type DiversityOptions struct {
+	// Relevance is the weight, from 0 to 1, given to how highly a rule is
+	// ranked compared to how different it is from the rules already
+	// selected.  1 selects the same rules as TruncateRuleAssessments and
+	// 0, the zero value, selects rules only by how different they are,
+	// with the ranking just used to break ties.
+	Relevance float64
+	// Similarity is how the similarity of two rules is measured
+	Similarity Similarity
+}
+
+// DefaultDiversityOptions returns DiversityOptions that give more weight
+// to how highly a rule is ranked than to how different it is
+func DefaultDiversityOptions() DiversityOptions {
+	return DiversityOptions{Relevance: 0.7, Similarity: CoverageSimilarity}
+}
+
+// TruncateRuleAssessmentsDiverse returns an Assessment of the best
+// maxRuleAssessments rules that also differ from each other, using
+// maximal marginal relevance.  Each rule is selected in turn by trading
+// off how highly it is ranked against how similar it is to the rules
+// already selected.  The selected rules keep their sorted order.
+// Assessment must be sorted first.
+func (a *Assessment) TruncateRuleAssessmentsDiverse(
+	maxRuleAssessments int,
+	opts DiversityOptions,
+) *Assessment {
+	if !a.IsSorted() {
+		panic("Assessment isn't sorted")
+	}
+	trueRuleAssessment := getTrueRuleAssessment(a.RuleAssessments)
+	if trueRuleAssessment == nil {
+		panic("Assessment doesn't have True rule")
+	}
+
+	numRuleAssessments := len(a.RuleAssessments)
+	selected := make([]bool, numRuleAssessments)
+	// maxSimilarity is the greatest similarity of each rule to the
+	// rules already selected
+	maxSimilarity := make([]float64, numRuleAssessments)
+	numSelected := 0
+	for numSelected < maxRuleAssessments && numSelected < numRuleAssessments {
+		best := -1
+		bestScore := 0.0
+		for i := range a.RuleAssessments {
+			if selected[i] {
+				continue
+			}
+			relevance := 1.0 - float64(i)/float64(numRuleAssessments)
+			score := opts.Relevance*relevance -
+				(1.0-opts.Relevance)*maxSimilarity[i]
+			if best == -1 || score > bestScore {
+				best = i
+				bestScore = score
+			}
+		}
+		selected[best] = true
+		numSelected++
+		for i, ra := range a.RuleAssessments {
+			if selected[i] {
+				continue
+			}
+			s := similarity(a.RuleAssessments[best], ra, opts.Similarity)
+			if s > maxSimilarity[i] {
+				maxSimilarity[i] = s
+			}
+		}
+	}
+
+	ruleAssessments := []*RuleAssessment{}
+	for i, ra := range a.RuleAssessments {
+		if selected[i] {
+			ruleAssessments = append(ruleAssessments, ra)
+		}
+	}
+	return a.newTruncated(ruleAssessments, trueRuleAssessment, maxRuleAssessments)
+}
+
+// similarity returns the Jaccard similarity, from 0 to 1, of two
+// RuleAssessments
+func similarity(a, b *RuleAssessment, s Similarity) float64 {
+	if s == CoverageSimilarity && a.coverage != nil && b.coverage != nil {
+		numA := a.coverage.Count()
+		numB := b.coverage.Count()
+		numBoth := a.coverage.And(b.coverage).Count()
+		numEither := numA + numB - numBoth
+		if numEither == 0 {
+			return 1
+		}
+		return float64(numBoth) / float64(numEither)
+	}
+	return fieldSimilarity(a.Rule.Fields(), b.Rule.Fields())
+}
+
+func fieldSimilarity(fieldsA, fieldsB []string) float64 {
+	inA := make(map[string]bool, len(fieldsA))
+	for _, f := range fieldsA {
+		inA[f] = true
+	}
+	inB := make(map[string]bool, len(fieldsB))
+	for _, f := range fieldsB {
+		inB[f] = true
+	}
+	if len(inA) == 0 && len(inB) == 0 {
+		return 0
+	}
+	numBoth := 0
+	for f := range inB {
+		if inA[f] {
+			numBoth++
+		}
+	}
+	return float64(numBoth) / float64(len(inA)+len(inB)-numBoth)
+}
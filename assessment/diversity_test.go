@@ -0,0 +1,159 @@
+package assessment
+
+import (
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/aggregator"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+func TestTruncateRuleAssessmentsDiverse_fields(t *testing.T) {
+	bandGE5 := rule.NewGEFV("band", dlit.MustNew(5))
+	bandGE6 := rule.NewGEFV("band", dlit.MustNew(6))
+	bandGE7 := rule.NewGEFV("band", dlit.MustNew(7))
+	teamEQA := rule.NewEQFV("team", dlit.NewString("a"))
+	incomeGE3 := rule.NewGEFV("income", dlit.MustNew(3))
+	cases := []struct {
+		max       int
+		opts      DiversityOptions
+		wantRules []rule.Rule
+	}{
+		{max: 4,
+			opts: DiversityOptions{Relevance: 1, Similarity: FieldSimilarity},
+			wantRules: []rule.Rule{
+				bandGE5, bandGE6, bandGE7, rule.NewTrue(),
+			},
+		},
+		{max: 4,
+			opts: DiversityOptions{Relevance: 0.5, Similarity: FieldSimilarity},
+			wantRules: []rule.Rule{
+				bandGE5, teamEQA, incomeGE3, rule.NewTrue(),
+			},
+		},
+		{max: 3,
+			opts:      DiversityOptions{Relevance: 0.5, Similarity: FieldSimilarity},
+			wantRules: []rule.Rule{bandGE5, teamEQA, rule.NewTrue()},
+		},
+		// Without coverage the fields are compared
+		{max: 3,
+			opts:      DiversityOptions{Relevance: 0.5, Similarity: CoverageSimilarity},
+			wantRules: []rule.Rule{bandGE5, teamEQA, rule.NewTrue()},
+		},
+		// The zero value of Relevance only uses the ranking to break ties
+		{max: 4,
+			opts: DiversityOptions{Similarity: FieldSimilarity},
+			wantRules: []rule.Rule{
+				bandGE5, teamEQA, incomeGE3, rule.NewTrue(),
+			},
+		},
+		{max: 4,
+			opts: DefaultDiversityOptions(),
+			wantRules: []rule.Rule{
+				bandGE5, bandGE6, teamEQA, rule.NewTrue(),
+			},
+		},
+		{max: 10,
+			opts: DiversityOptions{Relevance: 0.5, Similarity: FieldSimilarity},
+			wantRules: []rule.Rule{
+				bandGE5, bandGE6, bandGE7, teamEQA, incomeGE3, rule.NewTrue(),
+			},
+		},
+	}
+	for i, c := range cases {
+		ass := &Assessment{
+			NumRecords: 1000,
+			flags: map[string]bool{
+				"sorted": true,
+			},
+			RuleAssessments: []*RuleAssessment{
+				makeRefineRuleAssessment(bandGE5, 600),
+				makeRefineRuleAssessment(bandGE6, 500),
+				makeRefineRuleAssessment(bandGE7, 400),
+				makeRefineRuleAssessment(teamEQA, 300),
+				makeRefineRuleAssessment(incomeGE3, 200),
+				makeRefineRuleAssessment(rule.NewTrue(), 1000),
+			},
+		}
+		got := ass.TruncateRuleAssessmentsDiverse(c.max, c.opts)
+		gotRules := got.Rules()
+		if !matchRules(gotRules, c.wantRules) {
+			t.Errorf("(%d) matchRules() rules don't match:\ngot: %s\nwant: %s\n",
+				i, gotRules, c.wantRules)
+		}
+		if !got.IsSorted() {
+			t.Errorf("(%d) IsSorted() got: false", i)
+		}
+	}
+}
+
+func TestTruncateRuleAssessmentsDiverse_coverage(t *testing.T) {
+	dataset := makeBandDataset(1000)
+	aggregatorSpecs, err :=
+		aggregator.MakeSpecs(dataset.Fields(), []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	rules := []rule.Rule{
+		rule.NewTrue(),
+		rule.NewGEFV("band", dlit.MustNew(5)),
+		rule.NewGEFV("band", dlit.MustNew(6)),
+		rule.NewLEFV("band", dlit.MustNew(2)),
+	}
+	cases := []struct {
+		keepCoverage bool
+		wantRules    []rule.Rule
+	}{
+		{keepCoverage: true,
+			wantRules: []rule.Rule{
+				rule.NewTrue(),
+				rule.NewGEFV("band", dlit.MustNew(5)),
+				rule.NewLEFV("band", dlit.MustNew(2)),
+			},
+		},
+		{keepCoverage: false,
+			wantRules: []rule.Rule{
+				rule.NewTrue(),
+				rule.NewGEFV("band", dlit.MustNew(5)),
+				rule.NewGEFV("band", dlit.MustNew(6)),
+			},
+		},
+	}
+	for i, c := range cases {
+		ass := New(aggregatorSpecs, []*goal.Goal{})
+		ass.KeepCoverage(c.keepCoverage)
+		if err := ass.AssessRules(dataset, rules); err != nil {
+			t.Fatalf("(%d) AssessRules: %s", i, err)
+		}
+		ass.Sort([]SortOrder{{Aggregator: "numMatches", Direction: DESCENDING}})
+		opts := DiversityOptions{Relevance: 0.5, Similarity: CoverageSimilarity}
+		gotRules := ass.TruncateRuleAssessmentsDiverse(3, opts).Rules()
+		if !matchRules(gotRules, c.wantRules) {
+			t.Errorf("(%d) matchRules() rules don't match:\ngot: %s\nwant: %s\n",
+				i, gotRules, c.wantRules)
+		}
+	}
+}
+
+func TestTruncateRuleAssessmentsDiverse_panic(t *testing.T) {
+	ass := &Assessment{
+		NumRecords:      1000,
+		flags:           map[string]bool{"sorted": false},
+		RuleAssessments: []*RuleAssessment{},
+	}
+	paniced := false
+	defer func() {
+		if r := recover(); r != nil {
+			if r.(string) == "Assessment isn't sorted" {
+				paniced = true
+			} else {
+				t.Errorf("TruncateRuleAssessmentsDiverse: got panic: %s", r)
+			}
+		}
+		if !paniced {
+			t.Errorf("TruncateRuleAssessmentsDiverse: failed to panic")
+		}
+	}()
+	ass.TruncateRuleAssessmentsDiverse(3, DiversityOptions{})
+}
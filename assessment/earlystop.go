@@ -0,0 +1,156 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package assessment
+
+import (
+	"github.com/vlifesystems/rhkit/aggregator"
+	"github.com/vlifesystems/rhkit/internal"
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+// earlyStopInterval is the number of records between each check for
+// rules that can no longer qualify
+const earlyStopInterval = 100
+
+// earlyStop holds what is needed to find rules that can no longer
+// qualify part way through a pass of a Dataset
+type earlyStop struct {
+	numRecords        int64
+	minPercentMatches float64
+	// aggregator is the name of the aggregator that rules are sorted by
+	aggregator string
+	direction  direction
+	// threshold is the value of aggregator that a rule must be at least
+	// as good as to be among the best rules
+	threshold    float64
+	hasThreshold bool
+}
+
+// EarlyStop makes AssessRules drop rules part way through a pass of
+// the Dataset once they provably can't reach the minimum percentMatches
+// or can't be among the best maxNumRules rules already assessed, when
+// sorted by the first SortOrder.  The best rules are those that remain
+// once the rules already assessed have been sorted by sortOrder and
+// refined using the options set with SetRefineOptions.  This only works
+// for aggregators that implement aggregator.Bounder such as count,
+// numMatches and precision.  Rules that are dropped aren't added to the
+// Assessment.  Passing a maxNumRules of 0 only drops rules that can't
+// reach the minimum percentMatches.  EarlyStop may be called again to
+// change these for later passes.
+func (a *Assessment) EarlyStop(sortOrder []SortOrder, maxNumRules int) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.earlyStop = true
+	a.earlyStopSortOrder = sortOrder
+	a.earlyStopMaxNumRules = maxNumRules
+}
+
+// newEarlyStop returns an earlyStop for a pass over a Dataset with
+// numRecords records, or nil if early stopping isn't being used
+func (a *Assessment) newEarlyStop(numRecords int64) *earlyStop {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	if !a.earlyStop || numRecords <= 0 {
+		return nil
+	}
+	es := &earlyStop{
+		numRecords:        numRecords,
+		minPercentMatches: a.getMinPercentMatches(),
+	}
+	if len(a.earlyStopSortOrder) == 0 || a.earlyStopMaxNumRules <= 0 ||
+		len(a.RuleAssessments) < a.earlyStopMaxNumRules ||
+		a.earlyStopSortOrder[0].isPareto() {
+		return es
+	}
+	es.aggregator = a.earlyStopSortOrder[0].Aggregator
+	es.direction = a.earlyStopSortOrder[0].Direction
+	// The threshold is taken from the rules that remain once refined so
+	// that rules which Refine would exclude don't count towards the best
+	refined := a.refinedCopy()
+	if len(refined.RuleAssessments) < a.earlyStopMaxNumRules {
+		return es
+	}
+	l, ok :=
+		refined.RuleAssessments[a.earlyStopMaxNumRules-1].Aggregators[es.aggregator]
+	if !ok {
+		return es
+	}
+	v, isFloat := l.Float()
+	if !isFloat {
+		return es
+	}
+	es.threshold = v
+	es.hasThreshold = true
+	return es
+}
+
+// refinedCopy returns a copy of the Assessment, sorted by the
+// earlyStopSortOrder and refined.  The RuleAssessments are cloned so
+// that those in the Assessment aren't changed.
+func (a *Assessment) refinedCopy() *Assessment {
+	ruleAssessments := make([]*RuleAssessment, len(a.RuleAssessments))
+	for i, ra := range a.RuleAssessments {
+		ruleAssessments[i] = ra.clone()
+	}
+	r := &Assessment{
+		NumRecords:      a.NumRecords,
+		RuleAssessments: ruleAssessments,
+		keepCoverage:    a.keepCoverage,
+		refineOptions:   a.refineOptions,
+	}
+	r.resetFlags()
+	r.Sort(a.earlyStopSortOrder)
+	r.Refine()
+	return r
+}
+
+// check drops any rules that can no longer qualify once numProcessed
+// records have been processed
+func (es *earlyStop) check(
+	ruleAssessments []*RuleAssessment,
+	numProcessed int64,
+) {
+	numRemaining := es.numRecords - numProcessed
+	if numRemaining < 0 {
+		return
+	}
+	for _, ra := range ruleAssessments {
+		if ra.dropped {
+			continue
+		}
+		if _, isTrue := ra.Rule.(rule.True); isTrue {
+			continue
+		}
+		if es.isHopeless(ra, numRemaining) {
+			ra.dropped = true
+		}
+	}
+}
+
+func (es *earlyStop) isHopeless(ra *RuleAssessment, numRemaining int64) bool {
+	for _, ai := range ra.aggregators {
+		b, ok := ai.(aggregator.Bounder)
+		if !ok {
+			continue
+		}
+		switch ai.Name() {
+		case "numMatches":
+			_, max := b.Bounds(numRemaining)
+			percentMatches := internal.RoundFloat(100.0*max/float64(es.numRecords), 2)
+			if percentMatches < es.minPercentMatches {
+				return true
+			}
+		}
+		if es.hasThreshold && ai.Name() == es.aggregator {
+			min, max := b.Bounds(numRemaining)
+			if es.direction == DESCENDING && max < es.threshold {
+				return true
+			}
+			if es.direction == ASCENDING && min > es.threshold {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package assessment
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lawrencewoodman/ddataset"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/aggregator"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+func TestAssessRules_earlyStop(t *testing.T) {
+	dataset := makeBandDataset(1000)
+	aggregatorSpecs, err :=
+		aggregator.MakeSpecs(dataset.Fields(), []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	sortOrder := []SortOrder{{Aggregator: "numMatches", Direction: DESCENDING}}
+	baseRules := []rule.Rule{
+		rule.NewTrue(),
+		rule.NewGEFV("band", dlit.MustNew(5)),
+		rule.NewGEFV("band", dlit.MustNew(8)),
+	}
+	cases := []struct {
+		rule           rule.Rule
+		wantNumIsTrue  int
+		wantNotDropped bool
+	}{
+		// Can't beat the 2nd best numMatches of 500 after 600 records
+		{rule: rule.NewGEFV("band", dlit.MustNew(9)), wantNumIsTrue: 600},
+		// Can't beat 500 or reach percentMatches of 50 after 700 records
+		{rule: rule.NewLEFV("band", dlit.MustNew(1)), wantNumIsTrue: 700},
+		{rule: rule.NewGEFV("band", dlit.MustNew(3)),
+			wantNumIsTrue:  1000,
+			wantNotDropped: true,
+		},
+	}
+	for i, c := range cases {
+		numIsTrue := 0
+		r := &countingRule{c.rule, &numIsTrue}
+		ass := New(aggregatorSpecs, []*goal.Goal{})
+		ass.SetRefineOptions(RefineOptions{MinPercentMatches: 50})
+		if err := ass.AssessRules(dataset, baseRules); err != nil {
+			t.Fatalf("(%d) AssessRules: %s", i, err)
+		}
+		ass.EarlyStop(sortOrder, 2)
+		if err := ass.AssessRules(dataset, []rule.Rule{r}); err != nil {
+			t.Fatalf("(%d) AssessRules: %s", i, err)
+		}
+		if numIsTrue != c.wantNumIsTrue {
+			t.Errorf("(%d) IsTrue called: %d times, want: %d",
+				i, numIsTrue, c.wantNumIsTrue)
+		}
+		gotNotDropped := len(ass.RuleAssessments) == len(baseRules)+1
+		if gotNotDropped != c.wantNotDropped {
+			t.Errorf("(%d) AssessRules - rule: %s, got not dropped: %t, want: %t",
+				i, c.rule, gotNotDropped, c.wantNotDropped)
+		}
+	}
+}
+
+func TestAssessRules_earlyStop_minPercentMatches(t *testing.T) {
+	dataset := makeBandDataset(1000)
+	aggregatorSpecs, err :=
+		aggregator.MakeSpecs(dataset.Fields(), []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	numIsTrue := 0
+	r := &countingRule{rule.NewLEFV("band", dlit.MustNew(1)), &numIsTrue}
+	ass := New(aggregatorSpecs, []*goal.Goal{})
+	ass.SetRefineOptions(RefineOptions{MinPercentMatches: 50})
+	ass.EarlyStop([]SortOrder{}, 0)
+	rules := []rule.Rule{rule.NewTrue(), r}
+	if err := ass.AssessRules(dataset, rules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	// After 700 records the rule can match at most 44% of records
+	if numIsTrue != 700 {
+		t.Errorf("IsTrue called: %d times, want: %d", numIsTrue, 700)
+	}
+	if len(ass.RuleAssessments) != 1 {
+		t.Errorf("AssessRules - got: %d rules, want: 1", len(ass.RuleAssessments))
+	}
+}
+
+func TestAssessRules_earlyStop_same(t *testing.T) {
+	dataset := makeBandDataset(1000)
+	aggregatorSpecs, err :=
+		aggregator.MakeSpecs(dataset.Fields(), []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	sortOrder := []SortOrder{{Aggregator: "numMatches", Direction: DESCENDING}}
+	rules := []rule.Rule{rule.NewTrue()}
+	for band := int64(0); band < 10; band++ {
+		rules = append(rules, rule.NewGEFV("band", dlit.MustNew(band)))
+	}
+	ass := New(aggregatorSpecs, []*goal.Goal{})
+	ass.SetRefineOptions(RefineOptions{
+		MinPercentMatches: 0.5,
+		Overlap:           KeepOverlapping,
+	})
+	ass.EarlyStop(sortOrder, 3)
+	wantAss := New(aggregatorSpecs, []*goal.Goal{})
+	for _, a := range []*Assessment{ass, wantAss} {
+		if err := a.AssessRules(dataset, rules[:4]); err != nil {
+			t.Fatalf("AssessRules: %s", err)
+		}
+		if err := a.AssessRules(dataset, rules[4:]); err != nil {
+			t.Fatalf("AssessRules: %s", err)
+		}
+		a.Sort(sortOrder)
+	}
+	wantAss = wantAss.TruncateRuleAssessments(3)
+	gotAss := ass.TruncateRuleAssessments(3)
+	if !gotAss.IsEqual(wantAss) {
+		t.Errorf("AssessRules got: %v, want: %v",
+			gotAss.RuleAssessments, wantAss.RuleAssessments)
+	}
+	if len(ass.RuleAssessments) >= len(rules) {
+		t.Errorf("AssessRules - got: %d rules, want less than: %d",
+			len(ass.RuleAssessments), len(rules))
+	}
+}
+
+func TestAssessRules_earlyStop_refined(t *testing.T) {
+	dataset := makeBandDataset(1000)
+	aggregatorSpecs, err :=
+		aggregator.MakeSpecs(dataset.Fields(), []*aggregator.Desc{})
+	if err != nil {
+		t.Fatalf("MakeSpecs: %s", err)
+	}
+	sortOrder := []SortOrder{{Aggregator: "numMatches", Direction: DESCENDING}}
+	// band >= 1 and band >= 2 overlap band >= 0 and would be excluded by
+	// Refine, so only two rules remain and nothing can be dropped for
+	// not being among the best 3
+	baseRules := []rule.Rule{
+		rule.NewTrue(),
+		rule.NewGEFV("band", dlit.MustNew(0)),
+		rule.NewGEFV("band", dlit.MustNew(1)),
+		rule.NewGEFV("band", dlit.MustNew(2)),
+	}
+	numIsTrue := 0
+	r := &countingRule{rule.NewLEFV("band", dlit.MustNew(3)), &numIsTrue}
+	ass := New(aggregatorSpecs, []*goal.Goal{})
+	if err := ass.AssessRules(dataset, baseRules); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	ass.EarlyStop(sortOrder, 3)
+	if err := ass.AssessRules(dataset, []rule.Rule{r}); err != nil {
+		t.Fatalf("AssessRules: %s", err)
+	}
+	if numIsTrue != 1000 {
+		t.Errorf("IsTrue called: %d times, want: %d", numIsTrue, 1000)
+	}
+	if len(ass.RuleAssessments) != len(baseRules)+1 {
+		t.Errorf("AssessRules - got: %d rules, want: %d",
+			len(ass.RuleAssessments), len(baseRules)+1)
+	}
+}
+
+// makeBandDataset returns a Dataset with numRecords records whose band
+// field cycles from 0 to 9
+func makeBandDataset(numRecords int) ddataset.Dataset {
+	records := make([][]string, numRecords)
+	for i := range records {
+		records[i] = []string{fmt.Sprintf("%d", i%10)}
+	}
+	return testhelpers.NewLiteralDataset([]string{"band"}, records)
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package assessment
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lawrencewoodman/dlit"
+)
+
+// paretoFrontAggregator is the Aggregator of a Pareto SortOrder
+const paretoFrontAggregator = "front"
+
+// NewParetoSortOrder returns a SortOrder that ranks the RuleAssessments
+// by Pareto front using the objectives, so that rules which trade off one
+// objective against another can be ranked together.  A rule dominates
+// another if it is no worse for any objective and better for at least
+// one.  The first front is made up of the rules that no other rule
+// dominates, the second of those only dominated by rules in the first
+// front and so on.  Within a front, rules in less crowded parts of the
+// front come first.  When sorted, the front of each RuleAssessment,
+// starting from 1, is recorded in its Front field.
+func NewParetoSortOrder(objectives []SortOrder) SortOrder {
+	return SortOrder{
+		Aggregator: paretoFrontAggregator,
+		Direction:  ASCENDING,
+		Objectives: objectives,
+	}
+}
+
+// isPareto returns whether the SortOrder ranks by Pareto front
+func (s SortOrder) isPareto() bool {
+	return len(s.Objectives) > 0
+}
+
+// paretoRanks returns the front, starting from 1, and the crowding
+// distance of each RuleAssessment using the objectives
+func paretoRanks(
+	ruleAssessments []*RuleAssessment,
+	objectives []SortOrder,
+) (map[*RuleAssessment]*dlit.Literal, map[*RuleAssessment]float64) {
+	values := newSortValues(ruleAssessments, objectives)
+	fronts := paretoFronts(ruleAssessments, objectives, values)
+	crowding := make([]float64, len(ruleAssessments))
+	for _, front := range fronts {
+		crowdingDistances(ruleAssessments, front, objectives, values, crowding)
+	}
+	frontValues := make(map[*RuleAssessment]*dlit.Literal, len(ruleAssessments))
+	crowdingValues := make(map[*RuleAssessment]float64, len(ruleAssessments))
+	for f, front := range fronts {
+		for _, i := range front {
+			frontValues[ruleAssessments[i]] = dlit.MustNew(f + 1)
+			crowdingValues[ruleAssessments[i]] = crowding[i]
+		}
+	}
+	return frontValues, crowdingValues
+}
+
+// paretoFronts returns the indices of the RuleAssessments in each front
+// using fast non-dominated sorting
+func paretoFronts(
+	ruleAssessments []*RuleAssessment,
+	objectives []SortOrder,
+	values sortValues,
+) [][]int {
+	n := len(ruleAssessments)
+	// dominates lists the RuleAssessments that each one dominates
+	dominates := make([][]int, n)
+	// numDominatedBy is the number of RuleAssessments that dominate each one
+	numDominatedBy := make([]int, n)
+	front := []int{}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := dominance(
+				ruleAssessments[i],
+				ruleAssessments[j],
+				objectives,
+				values,
+			)
+			switch d {
+			case 1:
+				dominates[i] = append(dominates[i], j)
+				numDominatedBy[j]++
+			case -1:
+				dominates[j] = append(dominates[j], i)
+				numDominatedBy[i]++
+			}
+		}
+		if numDominatedBy[i] == 0 {
+			front = append(front, i)
+		}
+	}
+
+	fronts := [][]int{}
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		nextFront := []int{}
+		for _, i := range front {
+			for _, j := range dominates[i] {
+				numDominatedBy[j]--
+				if numDominatedBy[j] == 0 {
+					nextFront = append(nextFront, j)
+				}
+			}
+		}
+		front = nextFront
+	}
+	return fronts
+}
+
+// dominance returns 1 if a dominates b, -1 if b dominates a and
+// 0 if neither dominates the other
+func dominance(
+	a, b *RuleAssessment,
+	objectives []SortOrder,
+	values sortValues,
+) int {
+	aBetter := false
+	bBetter := false
+	for k, o := range objectives {
+		c := compareDlitNums(values.value(k, o, a), values.value(k, o, b))
+		if o.Direction == DESCENDING {
+			c *= -1
+		}
+		if c < 0 {
+			aBetter = true
+		} else if c > 0 {
+			bBetter = true
+		}
+	}
+	switch {
+	case aBetter && !bBetter:
+		return 1
+	case bBetter && !aBetter:
+		return -1
+	}
+	return 0
+}
+
+// crowdingDistances sets the crowding distance of each RuleAssessment in
+// a front.  This is the sum over the objectives of the normalised
+// distance between the neighbours either side of it.  Those at the
+// edges of the front have an infinite crowding distance.
+func crowdingDistances(
+	ruleAssessments []*RuleAssessment,
+	front []int,
+	objectives []SortOrder,
+	values sortValues,
+	crowding []float64,
+) {
+	if len(front) <= 2 {
+		for _, i := range front {
+			crowding[i] = math.Inf(1)
+		}
+		return
+	}
+	sorted := make([]int, len(front))
+	copy(sorted, front)
+	floats := make([]float64, len(ruleAssessments))
+	for k, o := range objectives {
+		for _, i := range sorted {
+			v, _ := values.value(k, o, ruleAssessments[i]).Float()
+			floats[i] = v
+		}
+		sort.Sort(byValue{sorted, floats})
+		first, last := sorted[0], sorted[len(sorted)-1]
+		crowding[first] = math.Inf(1)
+		crowding[last] = math.Inf(1)
+		valueRange := floats[last] - floats[first]
+		if valueRange == 0 || math.IsInf(valueRange, 0) {
+			continue
+		}
+		for j := 1; j < len(sorted)-1; j++ {
+			crowding[sorted[j]] +=
+				(floats[sorted[j+1]] - floats[sorted[j-1]]) / valueRange
+		}
+	}
+}
+
+// byValue implements sort.Interface for indices based on their values
+type byValue struct {
+	indices []int
+	values  []float64
+}
+
+func (b byValue) Len() int { return len(b.indices) }
+func (b byValue) Swap(i, j int) {
+	b.indices[i], b.indices[j] = b.indices[j], b.indices[i]
+}
+func (b byValue) Less(i, j int) bool {
+	return b.values[b.indices[i]] < b.values[b.indices[j]]
+}
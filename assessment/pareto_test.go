@@ -0,0 +1,181 @@
+package assessment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+func TestSort_pareto(t *testing.T) {
+	a := rule.NewGEFV("a", dlit.MustNew(1))
+	b := rule.NewGEFV("b", dlit.MustNew(1))
+	c := rule.NewGEFV("c", dlit.MustNew(1))
+	d := rule.NewGEFV("d", dlit.MustNew(1))
+	e := rule.NewGEFV("e", dlit.MustNew(1))
+	f := rule.NewGEFV("f", dlit.MustNew(1))
+	ass := &Assessment{
+		NumRecords: 1000,
+		flags:      map[string]bool{"sorted": false},
+		RuleAssessments: []*RuleAssessment{
+			makeParetoRuleAssessment(f, 0.3, 30),
+			makeParetoRuleAssessment(e, 0.4, 90),
+			makeParetoRuleAssessment(d, 0.7, 40),
+			makeParetoRuleAssessment(c, 0.5, 100),
+			makeParetoRuleAssessment(b, 0.8, 50),
+			makeParetoRuleAssessment(a, 0.9, 10),
+		},
+	}
+	objectives := []SortOrder{
+		{Aggregator: "precision", Direction: DESCENDING},
+		{Aggregator: "numMatches", Direction: DESCENDING},
+	}
+	ass.Sort([]SortOrder{NewParetoSortOrder(objectives)})
+	wantRules := []rule.Rule{a, c, b, d, e, f}
+	wantFronts := []int{1, 1, 1, 2, 2, 3}
+	gotRules := ass.Rules()
+	if !matchRules(gotRules, wantRules) {
+		t.Errorf("Sort rules don't match:\ngot: %s\nwant: %s\n",
+			gotRules, wantRules)
+	}
+	for i, ra := range ass.RuleAssessments {
+		if ra.Front != wantFronts[i] {
+			t.Errorf("Sort - rule: %s, got Front: %d, want: %d",
+				ra.Rule, ra.Front, wantFronts[i])
+		}
+	}
+	if !ass.IsSorted() {
+		t.Errorf("IsSorted() got: false")
+	}
+
+	ass.Sort(objectives)
+	for _, ra := range ass.RuleAssessments {
+		if ra.Front != 0 {
+			t.Errorf("Sort - rule: %s, got Front: %d, want: 0", ra.Rule, ra.Front)
+		}
+	}
+}
+
+func TestSort_paretoThenAggregator(t *testing.T) {
+	a := makeParetoRuleAssessment(rule.NewGEFV("a", dlit.MustNew(1)), 0.9, 10)
+	b := makeParetoRuleAssessment(rule.NewGEFV("b", dlit.MustNew(1)), 0.5, 50)
+	c := makeParetoRuleAssessment(rule.NewGEFV("c", dlit.MustNew(1)), 0.5, 50)
+	a.Aggregators["cost"] = dlit.MustNew(3)
+	b.Aggregators["cost"] = dlit.MustNew(2)
+	c.Aggregators["cost"] = dlit.MustNew(1)
+	ass := &Assessment{
+		NumRecords:      1000,
+		flags:           map[string]bool{"sorted": false},
+		RuleAssessments: []*RuleAssessment{b, a, c},
+	}
+	objectives := []SortOrder{
+		{Aggregator: "precision", Direction: DESCENDING},
+		{Aggregator: "numMatches", Direction: DESCENDING},
+	}
+	ass.Sort([]SortOrder{
+		NewParetoSortOrder(objectives),
+		{Aggregator: "cost", Direction: ASCENDING},
+	})
+	wantRules := []rule.Rule{c.Rule, b.Rule, a.Rule}
+	wantFronts := []int{1, 1, 1}
+	gotRules := ass.Rules()
+	if !matchRules(gotRules, wantRules) {
+		t.Errorf("Sort rules don't match:\ngot: %s\nwant: %s\n",
+			gotRules, wantRules)
+	}
+	for i, ra := range ass.RuleAssessments {
+		if ra.Front != wantFronts[i] {
+			t.Errorf("Sort - rule: %s, got Front: %d, want: %d",
+				ra.Rule, ra.Front, wantFronts[i])
+		}
+	}
+}
+
+func TestDominance(t *testing.T) {
+	better := makeParetoRuleAssessment(rule.NewTrue(), 0.8, 50)
+	worse := makeParetoRuleAssessment(rule.NewTrue(), 0.7, 50)
+	tradeOff := makeParetoRuleAssessment(rule.NewTrue(), 0.6, 90)
+	cases := []struct {
+		a          *RuleAssessment
+		b          *RuleAssessment
+		objectives []SortOrder
+		want       int
+	}{
+		{a: better, b: worse,
+			objectives: []SortOrder{{Aggregator: "precision", Direction: DESCENDING}},
+			want:       1,
+		},
+		{a: better, b: worse,
+			objectives: []SortOrder{{Aggregator: "precision", Direction: ASCENDING}},
+			want:       -1,
+		},
+		{a: better, b: better,
+			objectives: []SortOrder{{Aggregator: "precision", Direction: DESCENDING}},
+			want:       0,
+		},
+		{a: better, b: tradeOff,
+			objectives: []SortOrder{
+				{Aggregator: "precision", Direction: DESCENDING},
+				{Aggregator: "numMatches", Direction: DESCENDING},
+			},
+			want: 0,
+		},
+		{a: worse, b: tradeOff,
+			objectives: []SortOrder{
+				{Aggregator: "precision", Direction: ASCENDING},
+				{Aggregator: "numMatches", Direction: DESCENDING},
+			},
+			want: -1,
+		},
+	}
+	for i, c := range cases {
+		got := dominance(c.a, c.b, c.objectives, sortValues{})
+		if got != c.want {
+			t.Errorf("(%d) dominance - got: %d, want: %d", i, got, c.want)
+		}
+	}
+}
+
+func TestCrowdingDistances(t *testing.T) {
+	ruleAssessments := []*RuleAssessment{
+		makeParetoRuleAssessment(rule.NewTrue(), 0.9, 10),
+		makeParetoRuleAssessment(rule.NewTrue(), 0.8, 50),
+		makeParetoRuleAssessment(rule.NewTrue(), 0.7, 80),
+		makeParetoRuleAssessment(rule.NewTrue(), 0.5, 100),
+	}
+	objectives := []SortOrder{
+		{Aggregator: "precision", Direction: DESCENDING},
+		{Aggregator: "numMatches", Direction: DESCENDING},
+	}
+	crowding := make([]float64, len(ruleAssessments))
+	crowdingDistances(
+		ruleAssessments,
+		[]int{0, 1, 2, 3},
+		objectives,
+		sortValues{},
+		crowding,
+	)
+	want := []float64{math.Inf(1), 0.5 + 7.0/9.0, 0.75 + 5.0/9.0, math.Inf(1)}
+	for i, w := range want {
+		if math.Abs(crowding[i]-w) > 0.0001 && crowding[i] != w {
+			t.Errorf("crowdingDistances - got: %v, want: %v", crowding, want)
+			break
+		}
+	}
+}
+
+func makeParetoRuleAssessment(
+	r rule.Rule,
+	precision float64,
+	numMatches int64,
+) *RuleAssessment {
+	return &RuleAssessment{
+		Rule: r,
+		Aggregators: map[string]*dlit.Literal{
+			"precision":  dlit.MustNew(precision),
+			"numMatches": dlit.MustNew(numMatches),
+		},
+		Goals: []*GoalAssessment{},
+	}
+}
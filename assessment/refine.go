@@ -0,0 +1,251 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package assessment
+
+import (
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+// OverlapPolicy describes what Refine does with rules that overlap
+// better rules
+type OverlapPolicy int
+
+const (
+	// ExcludePoorerOverlapping excludes rules that overlap a better rule
+	ExcludePoorerOverlapping OverlapPolicy = iota
+	// KeepOverlapping keeps rules that overlap a better rule
+	KeepOverlapping
+)
+
+// RefineOptions control which rules Refine excludes
+type RefineOptions struct {
+	// MinPercentMatches is the minimum percentMatches that a rule must
+	// have, 0 means there is no minimum.  This is also used by AssessRules
+	// if EarlyStop is used.
+	MinPercentMatches float64
+	// MaxPercentMatches is the maximum percentMatches that a rule other
+	// than True may have, 0 means there is no maximum
+	MaxPercentMatches float64
+	// KeepSameRecords indicates whether to keep rules that match the same
+	// records as a better rule
+	KeepSameRecords bool
+	// SameRecordsAggregators are the names of the aggregators that are
+	// compared to decide whether two rules match the same records when
+	// their coverage hasn't been kept.  If this is empty then all the
+	// aggregators are compared, but only if there are more than 3 of them.
+	SameRecordsAggregators []string
+	// Overlap describes what to do with rules that overlap a better rule
+	Overlap OverlapPolicy
+	// MaxRulesPerField is the maximum number of rules kept that use each
+	// field, 0 means there is no limit
+	MaxRulesPerField int
+	// Filters are run in order after the other criteria have been applied
+	Filters []RefineFilter
+}
+
+// RefineFilter is implemented by custom filters used by Refine
+type RefineFilter interface {
+	// Filter is passed the sorted RuleAssessments and returns those to
+	// keep in the same order.  The True rule must be kept.
+	Filter(numRecords int64, ruleAssessments []*RuleAssessment) []*RuleAssessment
+}
+
+// RefineFilterFunc allows an ordinary function to be used as a
+// RefineFilter
+type RefineFilterFunc func(
+	numRecords int64,
+	ruleAssessments []*RuleAssessment,
+) []*RuleAssessment
+
+// Filter calls f(numRecords, ruleAssessments)
+func (f RefineFilterFunc) Filter(
+	numRecords int64,
+	ruleAssessments []*RuleAssessment,
+) []*RuleAssessment {
+	return f(numRecords, ruleAssessments)
+}
+
+// DefaultRefineOptions returns the RefineOptions used by Refine unless
+// they are changed with SetRefineOptions
+func DefaultRefineOptions() RefineOptions {
+	return RefineOptions{
+		MinPercentMatches: 0.5,
+		Overlap:           ExcludePoorerOverlapping,
+	}
+}
+
+// SetRefineOptions sets the options used by Refine
+func (a *Assessment) SetRefineOptions(opts RefineOptions) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.refineOptions = &opts
+}
+
+func (a *Assessment) getRefineOptions() RefineOptions {
+	if a.refineOptions == nil {
+		return DefaultRefineOptions()
+	}
+	return *a.refineOptions
+}
+
+func (a *Assessment) getMinPercentMatches() float64 {
+	return a.getRefineOptions().MinPercentMatches
+}
+
+// Refine removes ruleAssessments that are poorer than similar rules
+// or don't meet the criteria set with SetRefineOptions
+func (sortedAssessment *Assessment) Refine() {
+	if !sortedAssessment.IsSorted() {
+		panic("Assessment isn't sorted")
+	}
+	opts := sortedAssessment.getRefineOptions()
+	sortedAssessment.excludePoorRules(opts)
+	if !opts.KeepSameRecords {
+		sortedAssessment.excludeSameRecordsRules(opts.SameRecordsAggregators)
+	}
+	if opts.Overlap == ExcludePoorerOverlapping {
+		sortedAssessment.excludePoorerOverlappingRules()
+	}
+	if opts.MaxRulesPerField > 0 {
+		sortedAssessment.excludeExcessFieldRules(opts.MaxRulesPerField)
+	}
+	for _, f := range opts.Filters {
+		sortedAssessment.RuleAssessments =
+			f.Filter(sortedAssessment.NumRecords, sortedAssessment.RuleAssessments)
+	}
+}
+
+func (sortedAssessment *Assessment) excludeSameRecordsRules(
+	aggregatorNames []string,
+) {
+	if len(sortedAssessment.RuleAssessments) < 2 {
+		return
+	}
+	last := sortedAssessment.RuleAssessments[0]
+	if len(aggregatorNames) == 0 &&
+		len(last.Aggregators) <= 3 && last.coverage == nil {
+		return
+	}
+
+	goodRuleAssessments := make([]*RuleAssessment, 1)
+	goodRuleAssessments[0] = sortedAssessment.RuleAssessments[0]
+	for _, a := range sortedAssessment.RuleAssessments[1:] {
+		aggregatorsMatch := isSameRecords(last, a, aggregatorNames)
+		switch a.Rule.(type) {
+		case rule.True:
+			if aggregatorsMatch {
+				goodRuleAssessments[len(goodRuleAssessments)-1] = a
+			} else {
+				goodRuleAssessments = append(goodRuleAssessments, a)
+			}
+			break
+		default:
+			if !aggregatorsMatch {
+				goodRuleAssessments = append(goodRuleAssessments, a)
+			}
+		}
+		last = a
+	}
+	sortedAssessment.RuleAssessments = goodRuleAssessments
+}
+
+// isSameRecords returns whether two RuleAssessments appear to match the
+// same records.  If both have kept their coverage then this is compared
+// exactly, otherwise the named aggregators are compared or, if none are
+// named, all their aggregators.
+func isSameRecords(a, b *RuleAssessment, aggregatorNames []string) bool {
+	if a.coverage != nil && b.coverage != nil {
+		return a.coverage.Equal(b.coverage)
+	}
+	if len(aggregatorNames) > 0 {
+		for _, name := range aggregatorNames {
+			va, okA := a.Aggregators[name]
+			vb, okB := b.Aggregators[name]
+			if !okA || !okB || va.String() != vb.String() {
+				return false
+			}
+		}
+		return true
+	}
+	if len(a.Aggregators) <= 3 {
+		return false
+	}
+	for k, v := range a.Aggregators {
+		if b.Aggregators[k].String() != v.String() {
+			return false
+		}
+	}
+	return true
+}
+
+func (sortedAssessment *Assessment) excludePoorRules(opts RefineOptions) {
+	goodRuleAssessments := []*RuleAssessment{}
+	for _, a := range sortedAssessment.RuleAssessments {
+		percentMatches, percentMatchesIsFloat :=
+			a.Aggregators["percentMatches"].Float()
+		if !percentMatchesIsFloat {
+			panic("percentMatches aggregator isn't a float")
+		}
+		if percentMatches < opts.MinPercentMatches {
+			continue
+		}
+		if _, isTrue := a.Rule.(rule.True); !isTrue &&
+			opts.MaxPercentMatches > 0 && percentMatches > opts.MaxPercentMatches {
+			continue
+		}
+		goodRuleAssessments = append(goodRuleAssessments, a)
+	}
+	sortedAssessment.RuleAssessments = goodRuleAssessments
+}
+
+func (sortedAssessment *Assessment) excludePoorerOverlappingRules() {
+	goodRuleAssessments := make([]*RuleAssessment, 0)
+	for i, aI := range sortedAssessment.RuleAssessments {
+		switch xI := aI.Rule.(type) {
+		case rule.Overlapper:
+			overlaps := false
+			for j, aJ := range sortedAssessment.RuleAssessments {
+				if j >= i {
+					break
+				}
+				if xI.Overlaps(aJ.Rule) {
+					overlaps = true
+				}
+			}
+			if !overlaps {
+				goodRuleAssessments = append(goodRuleAssessments, aI)
+			}
+		default:
+			goodRuleAssessments = append(goodRuleAssessments, aI)
+		}
+	}
+	sortedAssessment.RuleAssessments = goodRuleAssessments
+}
+
+// excludeExcessFieldRules excludes rules that use a field which is
+// already used by maxRulesPerField better rules
+func (sortedAssessment *Assessment) excludeExcessFieldRules(
+	maxRulesPerField int,
+) {
+	numFieldRules := map[string]int{}
+	goodRuleAssessments := []*RuleAssessment{}
+	for _, a := range sortedAssessment.RuleAssessments {
+		fields := a.Rule.Fields()
+		isExcess := false
+		for _, f := range fields {
+			if numFieldRules[f] >= maxRulesPerField {
+				isExcess = true
+				break
+			}
+		}
+		if isExcess {
+			continue
+		}
+		for _, f := range fields {
+			numFieldRules[f]++
+		}
+		goodRuleAssessments = append(goodRuleAssessments, a)
+	}
+	sortedAssessment.RuleAssessments = goodRuleAssessments
+}
@@ -0,0 +1,169 @@
+package assessment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/aggregator"
+	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+func TestRefine_options(t *testing.T) {
+	bandGE5 := rule.NewGEFV("band", dlit.MustNew(5))
+	bandBetween4And8 :=
+		rule.MustNewBetweenFV("band", dlit.MustNew(4), dlit.MustNew(8))
+	bandBetween5And9 :=
+		rule.MustNewBetweenFV("band", dlit.MustNew(5), dlit.MustNew(9))
+	teamEQA := rule.NewEQFV("team", dlit.NewString("a"))
+	incomeLE2 := rule.NewLEFV("income", dlit.MustNew(2))
+	noTeamRules := RefineFilterFunc(
+		func(numRecords int64, ras []*RuleAssessment) []*RuleAssessment {
+			r := []*RuleAssessment{}
+			for _, ra := range ras {
+				if !strings.Contains(ra.Rule.String(), "team") {
+					r = append(r, ra)
+				}
+			}
+			return r
+		},
+	)
+	cases := []struct {
+		opts      *RefineOptions
+		wantRules []rule.Rule
+	}{
+		{opts: nil,
+			wantRules: []rule.Rule{
+				bandGE5, bandBetween4And8, teamEQA, rule.NewTrue(),
+			},
+		},
+		// A MinPercentMatches of 0 means there is no minimum
+		{opts: &RefineOptions{},
+			wantRules: []rule.Rule{
+				bandGE5, bandBetween4And8, teamEQA, incomeLE2, rule.NewTrue(),
+			},
+		},
+		{opts: &RefineOptions{MinPercentMatches: 0.01},
+			wantRules: []rule.Rule{
+				bandGE5, bandBetween4And8, teamEQA, incomeLE2, rule.NewTrue(),
+			},
+		},
+		{opts: &RefineOptions{MinPercentMatches: 0.5, MaxPercentMatches: 50},
+			wantRules: []rule.Rule{bandBetween4And8, teamEQA, rule.NewTrue()},
+		},
+		{opts: &RefineOptions{
+			MinPercentMatches:      0.5,
+			SameRecordsAggregators: []string{"numMatches"},
+		},
+			wantRules: []rule.Rule{bandGE5, bandBetween4And8, rule.NewTrue()},
+		},
+		{opts: &RefineOptions{
+			MinPercentMatches:      0.5,
+			KeepSameRecords:        true,
+			SameRecordsAggregators: []string{"numMatches"},
+		},
+			wantRules: []rule.Rule{
+				bandGE5, bandBetween4And8, teamEQA, rule.NewTrue(),
+			},
+		},
+		{opts: &RefineOptions{MinPercentMatches: 0.5, Overlap: KeepOverlapping},
+			wantRules: []rule.Rule{
+				bandGE5, bandBetween4And8, bandBetween5And9, teamEQA, rule.NewTrue(),
+			},
+		},
+		{opts: &RefineOptions{MinPercentMatches: 0.5, MaxRulesPerField: 1},
+			wantRules: []rule.Rule{bandGE5, teamEQA, rule.NewTrue()},
+		},
+		{opts: &RefineOptions{
+			MinPercentMatches: 0.5,
+			Filters:           []RefineFilter{noTeamRules},
+		},
+			wantRules: []rule.Rule{bandGE5, bandBetween4And8, rule.NewTrue()},
+		},
+	}
+	for i, c := range cases {
+		ass := &Assessment{
+			NumRecords: 1000,
+			flags: map[string]bool{
+				"sorted": true,
+			},
+			RuleAssessments: []*RuleAssessment{
+				makeRefineRuleAssessment(bandGE5, 600),
+				makeRefineRuleAssessment(bandBetween4And8, 500),
+				makeRefineRuleAssessment(bandBetween5And9, 400),
+				makeRefineRuleAssessment(teamEQA, 400),
+				makeRefineRuleAssessment(incomeLE2, 3),
+				makeRefineRuleAssessment(rule.NewTrue(), 1000),
+			},
+		}
+		if c.opts != nil {
+			ass.SetRefineOptions(*c.opts)
+		}
+		ass.Refine()
+		gotRules := ass.Rules()
+		if !matchRules(gotRules, c.wantRules) {
+			t.Errorf("(%d) matchRules() rules don't match:\ngot: %s\nwant: %s\n",
+				i, gotRules, c.wantRules)
+		}
+	}
+}
+
+func TestGetMinPercentMatches(t *testing.T) {
+	cases := []struct {
+		opts *RefineOptions
+		want float64
+	}{
+		{opts: nil, want: 0.5},
+		{opts: &RefineOptions{MaxRulesPerField: 3}, want: 0},
+		{opts: &RefineOptions{MinPercentMatches: 0}, want: 0},
+		{opts: &RefineOptions{MinPercentMatches: 2}, want: 2},
+	}
+	for i, c := range cases {
+		ass := New([]aggregator.Spec{}, []*goal.Goal{})
+		if c.opts != nil {
+			ass.SetRefineOptions(*c.opts)
+		}
+		if got := ass.getMinPercentMatches(); got != c.want {
+			t.Errorf("(%d) getMinPercentMatches - got: %f, want: %f",
+				i, got, c.want)
+		}
+	}
+}
+
+func TestRefine_noMinPercentMatches(t *testing.T) {
+	bandGE5 := rule.NewGEFV("band", dlit.MustNew(5))
+	incomeLE2 := rule.NewLEFV("income", dlit.MustNew(2))
+	ass := &Assessment{
+		NumRecords: 1000,
+		flags: map[string]bool{
+			"sorted": true,
+		},
+		RuleAssessments: []*RuleAssessment{
+			makeRefineRuleAssessment(bandGE5, 600),
+			makeRefineRuleAssessment(incomeLE2, 1),
+			makeRefineRuleAssessment(rule.NewTrue(), 1000),
+		},
+	}
+	ass.SetRefineOptions(RefineOptions{MinPercentMatches: 0})
+	ass.Refine()
+	wantRules := []rule.Rule{bandGE5, incomeLE2, rule.NewTrue()}
+	gotRules := ass.Rules()
+	if !matchRules(gotRules, wantRules) {
+		t.Errorf("Refine - got: %s, want: %s", gotRules, wantRules)
+	}
+}
+
+// makeRefineRuleAssessment returns a RuleAssessment for a rule that
+// matched numMatches records out of 1000
+func makeRefineRuleAssessment(r rule.Rule, numMatches int64) *RuleAssessment {
+	return &RuleAssessment{
+		Rule: r,
+		Aggregators: map[string]*dlit.Literal{
+			"numMatches":     dlit.MustNew(numMatches),
+			"percentMatches": dlit.MustNew(float64(numMatches) / 10),
+			"goalsScore":     dlit.MustNew(0),
+		},
+		Goals: []*GoalAssessment{},
+	}
+}
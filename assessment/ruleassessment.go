@@ -1,4 +1,4 @@
-// Copyright (C) 2016-2017 vLife Systems Ltd <http://vlifesystems.com>
+// Copyright (C) 2016-2018 vLife Systems Ltd <http://vlifesystems.com>
 // Licensed under an MIT licence.  Please see LICENSE.md for details.
 
 package assessment
@@ -10,6 +10,7 @@ import (
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/aggregator"
 	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/bitset"
 	"github.com/vlifesystems/rhkit/rule"
 )
 
@@ -17,8 +18,26 @@ type RuleAssessment struct {
 	Rule        rule.Rule                `json:"rule"`
 	Aggregators map[string]*dlit.Literal `json:"aggregators"`
 	Goals       []*GoalAssessment        `json:"goals"`
+	// Front is the Pareto front of the rule if the Assessment was sorted
+	// using a SortOrder made with NewParetoSortOrder, starting from 1,
+	// otherwise it is 0
+	Front       int `json:"front,omitempty"`
 	aggregators []aggregator.Instance
 	goals       []*goal.Goal
+	// coverage records which records the rule matched if it is being kept
+	coverage *bitset.Bitset
+	// coverageKnown indicates that coverage has already been worked out
+	// and therefore the rule doesn't need to be tested against each record
+	coverageKnown bool
+	// canonicalRule is the String() of the canonical form of Rule, it is
+	// only set if coverage is being kept
+	canonicalRule string
+	numRecords    uint64
+	// compiledRule is used to test the rule against decoded records
+	compiledRule rule.CompiledRule
+	// dropped indicates that the rule can't qualify and therefore
+	// doesn't need to be tested against any more records
+	dropped bool
 }
 
 type AggregatorError struct {
@@ -38,6 +57,9 @@ func newRuleAssessment(
 	aggregatorInstances := make([]aggregator.Instance, len(aggregatorSpecs))
 	for i, ad := range aggregatorSpecs {
 		aggregatorInstances[i] = ad.New()
+		if rs, ok := aggregatorInstances[i].(aggregator.RuleSetter); ok {
+			rs.SetRule(rule)
+		}
 	}
 	return &RuleAssessment{
 		Rule:        rule,
@@ -52,13 +74,23 @@ func (r *RuleAssessment) String() string {
 }
 
 func (r *RuleAssessment) NextRecord(record ddataset.Record) error {
-	var ruleIsTrue bool
-	var err error
+	return r.nextRecord(record, nil)
+}
+
+// nextRecord processes the next record, using the compiled rule
+// if there is one and the record has been decoded
+func (r *RuleAssessment) nextRecord(
+	record ddataset.Record,
+	decodedRecord *rule.DecodedRecord,
+) error {
+	if r.dropped {
+		return nil
+	}
+	ruleIsTrue, err := r.isTrue(record, decodedRecord)
+	if err != nil {
+		return err
+	}
 	for _, aggregator := range r.aggregators {
-		ruleIsTrue, err = r.Rule.IsTrue(record)
-		if err != nil {
-			return err
-		}
 		err = aggregator.NextRecord(record, ruleIsTrue)
 		if err != nil {
 			return AggregatorError{Name: aggregator.Name(), Err: err}
@@ -67,8 +99,61 @@ func (r *RuleAssessment) NextRecord(record ddataset.Record) error {
 	return nil
 }
 
+// isTrue returns whether the rule is true for the next record, using
+// the coverage if it is known and recording it if it is being kept
+func (r *RuleAssessment) isTrue(
+	record ddataset.Record,
+	decodedRecord *rule.DecodedRecord,
+) (bool, error) {
+	var ruleIsTrue bool
+	var err error
+	recordNum := r.numRecords
+	r.numRecords++
+	if r.coverageKnown {
+		return r.coverage.Contains(recordNum), nil
+	}
+	if r.compiledRule != nil && decodedRecord != nil {
+		ruleIsTrue, err = r.compiledRule(decodedRecord)
+	} else {
+		ruleIsTrue, err = r.Rule.IsTrue(record)
+	}
+	if err != nil {
+		return false, err
+	}
+	if ruleIsTrue && r.coverage != nil {
+		r.coverage.Add(recordNum)
+	}
+	return ruleIsTrue, nil
+}
+
+// keepCoverage makes the RuleAssessment record which records its
+// rule matched.  canonicalRule is the String() of the canonical form
+// of the rule.
+func (r *RuleAssessment) keepCoverage(canonicalRule string) {
+	r.coverage = bitset.New()
+	r.canonicalRule = canonicalRule
+}
+
+// setCoverage sets the records that the rule is known to match so that
+// the rule doesn't have to be tested against each record.  canonicalRule
+// is the String() of the canonical form of the rule.
+func (r *RuleAssessment) setCoverage(
+	coverage *bitset.Bitset,
+	canonicalRule string,
+) {
+	r.coverage = coverage
+	r.coverageKnown = true
+	r.canonicalRule = canonicalRule
+}
+
+// HasCoverage returns whether the RuleAssessment has kept a record of
+// which records its rule matched
+func (r *RuleAssessment) HasCoverage() bool {
+	return r.coverage != nil
+}
+
 func (r *RuleAssessment) IsEqual(o *RuleAssessment) bool {
-	if r.Rule.String() != o.Rule.String() {
+	if r.Rule.String() != o.Rule.String() || r.Front != o.Front {
 		return false
 	}
 	if len(r.Aggregators) != len(o.Aggregators) {
@@ -95,8 +180,12 @@ func (r *RuleAssessment) clone() *RuleAssessment {
 		Rule:        r.Rule,
 		Aggregators: r.Aggregators,
 		Goals:       r.Goals,
+		Front:       r.Front,
 		aggregators: r.aggregators,
 		goals:       r.goals,
+		coverage:    r.coverage,
+		// canonicalRule is kept so that it doesn't have to be worked out again
+		canonicalRule: r.canonicalRule,
 	}
 }
 
@@ -112,7 +201,15 @@ func (r *RuleAssessment) update(numRecords int64) error {
 		if err != nil {
 			return err
 		}
-		goalAssessments[j] = &GoalAssessment{goal.String(), passed}
+		distance, err := goal.Distance(aggregatorInstancesMap)
+		if err != nil {
+			return err
+		}
+		goalAssessments[j] = &GoalAssessment{
+			Expr:     goal.String(),
+			Passed:   passed,
+			Distance: distance,
+		}
 	}
 	// TODO: Work out why this is here
 	delete(aggregatorInstancesMap, "numRecords")
@@ -51,10 +51,11 @@ func TestNextRecord(t *testing.T) {
 		wantNumIncomeGt2 int64
 		wantNumBandGt4   int64
 		wantGoalsScore   float64
+		wantDistances    []float64
 	}{
-		{rule.NewGEFV("band", dlit.MustNew(5)), 1, 2, 2.0},
-		{rule.NewGEFV("band", dlit.MustNew(3)), 2, 2, 0.001},
-		{rule.NewGEFV("cost", dlit.MustNew(1.3)), 2, 1, 0},
+		{rule.NewGEFV("band", dlit.MustNew(5)), 1, 2, 2.0, []float64{0, 0}},
+		{rule.NewGEFV("band", dlit.MustNew(3)), 2, 2, 0.001, []float64{0.5, 0}},
+		{rule.NewGEFV("cost", dlit.MustNew(1.3)), 2, 1, 0, []float64{0.5, 0.5}},
 	}
 	for _, c := range cases {
 		ra := newRuleAssessment(c.rule, inAggregators, goals)
@@ -102,6 +103,12 @@ func TestNextRecord(t *testing.T) {
 			t.Errorf("nextRecord() rule: %s, aggregators: %v, goals: %v - wantGoalsScore: %f, got: %f",
 				c.rule, inAggregators, goals, c.wantGoalsScore, gotGoalsScoreFloat)
 		}
+		for i, g := range ra.Goals {
+			if g.Distance != c.wantDistances[i] {
+				t.Errorf("nextRecord() rule: %s, goal: %s - wantDistance: %f, got: %f",
+					c.rule, g.Expr, c.wantDistances[i], g.Distance)
+			}
+		}
 	}
 }
 
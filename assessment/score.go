@@ -0,0 +1,224 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package assessment
+
+import (
+	"errors"
+	"math"
+
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/aggregator"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// ErrScoreNotNumber indicates that a weighted score expression doesn't
+// return a number
+var ErrScoreNotNumber = errors.New("score isn't a number")
+
+// newScoreSortOrder returns a SortOrder that sorts by a weighted score
+// expression over aggregator names, such as
+// "0.7*precision + 0.3*recall - 0.01*complexity".  Each aggregator is
+// normalised to between 0 and 1 over the RuleAssessments being sorted
+// before the expression is evaluated.
+func newScoreSortOrder(
+	aggregatorSpecs []aggregator.Spec,
+	expr string,
+	direction direction,
+	directionStr string,
+) (SortOrder, error) {
+	e, err := dexpr.New(expr, dexprfuncs.CallFuncs)
+	if err != nil {
+		return SortOrder{}, SortOrderError{
+			Aggregator: expr,
+			Direction:  directionStr,
+			Err:        err,
+		}
+	}
+	// Evaluate the expression with each aggregator set to a normalised
+	// value to check that it only refers to aggregators and returns a number
+	names := aggregator.ResultNames(aggregatorSpecs)
+	vars := make(map[string]*dlit.Literal, len(names))
+	for _, name := range names {
+		vars[name] = dlit.MustNew(0.5)
+	}
+	l := e.Eval(vars)
+	if err := l.Err(); err != nil {
+		if isVarNotExistError(err) {
+			err = ErrUnrecognisedAggregator
+		}
+		return SortOrder{}, SortOrderError{
+			Aggregator: expr,
+			Direction:  directionStr,
+			Err:        err,
+		}
+	}
+	if _, isFloat := l.Float(); !isFloat {
+		return SortOrder{}, SortOrderError{
+			Aggregator: expr,
+			Direction:  directionStr,
+			Err:        ErrScoreNotNumber,
+		}
+	}
+	return SortOrder{Aggregator: expr, Direction: direction}, nil
+}
+
+// isVarNotExistError returns whether err is because an expression refers
+// to a variable that doesn't exist
+func isVarNotExistError(err error) bool {
+	if e, ok := err.(dexpr.InvalidExprError); ok {
+		_, ok := e.Err.(dexpr.VarNotExistError)
+		return ok
+	}
+	return false
+}
+
+// isScore returns whether the SortOrder sorts by a weighted score
+// expression rather than an aggregator
+func (s SortOrder) isScore() bool {
+	return !isIdentifier(s.Aggregator)
+}
+
+// isIdentifier returns whether s could be the name of an aggregator
+func isIdentifier(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i, c := range s {
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && (i == 0 || !isDigit) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortValues holds the values that RuleAssessments are sorted by
+type sortValues struct {
+	// scores holds the score of each RuleAssessment for each SortOrder
+	// that is a weighted score, or its front for each Pareto SortOrder,
+	// otherwise it is nil
+	scores []map[*RuleAssessment]*dlit.Literal
+	// crowding holds the crowding distance of each RuleAssessment for
+	// each Pareto SortOrder, otherwise it is nil
+	crowding []map[*RuleAssessment]float64
+}
+
+// newSortValues works out the scores of the RuleAssessments for each
+// SortOrder that is a weighted score and the fronts and crowding
+// distances for each Pareto SortOrder.  If the score of a rule can't be
+// worked out then it is given the worst possible score.
+func newSortValues(
+	ruleAssessments []*RuleAssessment,
+	sortOrders []SortOrder,
+) sortValues {
+	sv := sortValues{
+		scores:   make([]map[*RuleAssessment]*dlit.Literal, len(sortOrders)),
+		crowding: make([]map[*RuleAssessment]float64, len(sortOrders)),
+	}
+	var normalised []map[string]*dlit.Literal
+	for i, so := range sortOrders {
+		if so.isPareto() {
+			sv.scores[i], sv.crowding[i] =
+				paretoRanks(ruleAssessments, so.Objectives)
+			continue
+		}
+		if !so.isScore() {
+			continue
+		}
+		if normalised == nil {
+			normalised = normaliseAggregators(ruleAssessments)
+		}
+		worst := dlit.MustNew(-math.MaxFloat64)
+		if so.Direction == ASCENDING {
+			worst = dlit.MustNew(math.MaxFloat64)
+		}
+		sv.scores[i] =
+			make(map[*RuleAssessment]*dlit.Literal, len(ruleAssessments))
+		e, err := dexpr.New(so.Aggregator, dexprfuncs.CallFuncs)
+		for j, ra := range ruleAssessments {
+			if err != nil {
+				sv.scores[i][ra] = worst
+				continue
+			}
+			l := e.Eval(normalised[j])
+			if _, isFloat := l.Float(); !isFloat {
+				l = worst
+			}
+			sv.scores[i][ra] = l
+		}
+	}
+	return sv
+}
+
+// value returns the value of a RuleAssessment for the SortOrder at
+// index i
+func (sv sortValues) value(
+	i int,
+	sortOrder SortOrder,
+	ra *RuleAssessment,
+) *dlit.Literal {
+	if sv.scores != nil && sv.scores[i] != nil {
+		return sv.scores[i][ra]
+	}
+	return ra.Aggregators[sortOrder.Aggregator]
+}
+
+// front returns the Pareto front of a RuleAssessment for the first
+// Pareto SortOrder, or 0 if there isn't one
+func (sv sortValues) front(
+	sortOrders []SortOrder,
+	ra *RuleAssessment,
+) int {
+	for i, so := range sortOrders {
+		if so.isPareto() {
+			front, _ := sv.scores[i][ra].Int()
+			return int(front)
+		}
+	}
+	return 0
+}
+
+// normaliseAggregators returns the aggregators of each RuleAssessment
+// scaled to between 0 and 1 using the minimum and maximum value of each
+// aggregator across the RuleAssessments
+func normaliseAggregators(
+	ruleAssessments []*RuleAssessment,
+) []map[string]*dlit.Literal {
+	mins := map[string]float64{}
+	maxs := map[string]float64{}
+	for _, ra := range ruleAssessments {
+		for name, l := range ra.Aggregators {
+			v, isFloat := l.Float()
+			if !isFloat {
+				continue
+			}
+			if min, ok := mins[name]; !ok || v < min {
+				mins[name] = v
+			}
+			if max, ok := maxs[name]; !ok || v > max {
+				maxs[name] = v
+			}
+		}
+	}
+	r := make([]map[string]*dlit.Literal, len(ruleAssessments))
+	for i, ra := range ruleAssessments {
+		r[i] = make(map[string]*dlit.Literal, len(ra.Aggregators))
+		for name, l := range ra.Aggregators {
+			v, isFloat := l.Float()
+			if !isFloat {
+				r[i][name] = l
+				continue
+			}
+			valueRange := maxs[name] - mins[name]
+			if valueRange == 0 {
+				r[i][name] = dlit.MustNew(0)
+			} else {
+				r[i][name] = dlit.MustNew((v - mins[name]) / valueRange)
+			}
+		}
+	}
+	return r
+}
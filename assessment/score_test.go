@@ -0,0 +1,106 @@
+package assessment
+
+import (
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/rule"
+)
+
+func TestSort_score(t *testing.T) {
+	a := rule.NewGEFV("a", dlit.MustNew(1))
+	b := rule.NewGEFV("b", dlit.MustNew(1))
+	c := rule.NewGEFV("c", dlit.MustNew(1))
+	cases := []struct {
+		sortOrder []SortOrder
+		wantRules []rule.Rule
+	}{
+		{sortOrder: []SortOrder{
+			{Aggregator: "0.5*precision + 0.5*numMatches", Direction: DESCENDING},
+		},
+			wantRules: []rule.Rule{b, a, c},
+		},
+		{sortOrder: []SortOrder{
+			{Aggregator: "0.5*precision + 0.5*numMatches", Direction: ASCENDING},
+		},
+			wantRules: []rule.Rule{a, c, b},
+		},
+		{sortOrder: []SortOrder{
+			{Aggregator: "precision - 0.1*numMatches", Direction: DESCENDING},
+		},
+			wantRules: []rule.Rule{a, b, c},
+		},
+		{sortOrder: []SortOrder{
+			{Aggregator: "0.5*precision + 0.5*numMatches", Direction: DESCENDING},
+			{Aggregator: "numMatches", Direction: DESCENDING},
+		},
+			wantRules: []rule.Rule{b, c, a},
+		},
+		// A score that can't be worked out is the worst possible score
+		{sortOrder: []SortOrder{
+			{Aggregator: "precision / numMatches", Direction: DESCENDING},
+		},
+			wantRules: []rule.Rule{b, c, a},
+		},
+	}
+	for i, cs := range cases {
+		ass := &Assessment{
+			NumRecords: 1000,
+			flags:      map[string]bool{"sorted": false},
+			RuleAssessments: []*RuleAssessment{
+				makeParetoRuleAssessment(c, 0.5, 100),
+				makeParetoRuleAssessment(b, 0.8, 50),
+				makeParetoRuleAssessment(a, 0.9, 10),
+			},
+		}
+		ass.Sort(cs.sortOrder)
+		gotRules := ass.Rules()
+		if !matchRules(gotRules, cs.wantRules) {
+			t.Errorf("(%d) Sort rules don't match:\ngot: %s\nwant: %s\n",
+				i, gotRules, cs.wantRules)
+		}
+	}
+}
+
+func TestNormaliseAggregators(t *testing.T) {
+	ruleAssessments := []*RuleAssessment{
+		makeParetoRuleAssessment(rule.NewTrue(), 0.5, 100),
+		makeParetoRuleAssessment(rule.NewTrue(), 0.5, 50),
+		makeParetoRuleAssessment(rule.NewTrue(), 0.5, 0),
+	}
+	want := []map[string]float64{
+		{"precision": 0, "numMatches": 1},
+		{"precision": 0, "numMatches": 0.5},
+		{"precision": 0, "numMatches": 0},
+	}
+	got := normaliseAggregators(ruleAssessments)
+	for i, w := range want {
+		for name, wv := range w {
+			gv, isFloat := got[i][name].Float()
+			if !isFloat || gv != wv {
+				t.Errorf("(%d) normaliseAggregators - %s got: %s, want: %f",
+					i, name, got[i][name], wv)
+			}
+		}
+	}
+}
+
+func TestIsIdentifier(t *testing.T) {
+	cases := []struct {
+		in   string
+		want bool
+	}{
+		{in: "numMatches", want: true},
+		{in: "_a1", want: true},
+		{in: "a_2b", want: true},
+		{in: "", want: false},
+		{in: "1a", want: false},
+		{in: "0.7*precision", want: false},
+		{in: "a b", want: false},
+	}
+	for _, c := range cases {
+		if got := isIdentifier(c.in); got != c.want {
+			t.Errorf("isIdentifier(%s) got: %t, want: %t", c.in, got, c.want)
+		}
+	}
+}
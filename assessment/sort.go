@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/aggregator"
+	"github.com/vlifesystems/rhkit/rule"
 	"strings"
 )
 
@@ -19,6 +20,9 @@ type SortDesc struct {
 type SortOrder struct {
 	Aggregator string    `json:"aggregator"`
 	Direction  direction `json:"direction"`
+	// Objectives are the SortOrders used to rank by Pareto front if this
+	// SortOrder was created with NewParetoSortOrder
+	Objectives []SortOrder `json:"objectives,omitempty"`
 }
 
 type direction int
@@ -50,25 +54,55 @@ type Namer interface {
 	Name() string
 }
 
+// NewSortOrder creates a SortOrder for an aggregator or, as an
+// alternative, for a weighted score expression over aggregator names
+// such as: 0.7*precision + 0.3*recall - 0.01*complexity
 func NewSortOrder(
 	aggregatorSpecs []aggregator.Spec,
 	aggregator string,
 	direction string,
 ) (SortOrder, error) {
-	for _, s := range aggregatorSpecs {
-		if s.Name() == aggregator {
-			// TODO: Make case insensitive?
-			switch direction {
-			case "ascending":
-				return SortOrder{aggregator, ASCENDING}, nil
-			case "descending":
-				return SortOrder{aggregator, DESCENDING}, nil
-			}
-			return SortOrder{}, SortOrderError{
+	if !isIdentifier(aggregator) {
+		switch direction {
+		case "ascending":
+			return newScoreSortOrder(
+				aggregatorSpecs,
+				aggregator,
+				ASCENDING,
+				direction,
+			)
+		case "descending":
+			return newScoreSortOrder(
+				aggregatorSpecs,
+				aggregator,
+				DESCENDING,
+				direction,
+			)
+		}
+		return SortOrder{}, SortOrderError{
+			Aggregator: aggregator,
+			Direction:  direction,
+			Err:        ErrInvalidDirection,
+		}
+	}
+	if isResultName(aggregatorSpecs, aggregator) {
+		// TODO: Make case insensitive?
+		switch direction {
+		case "ascending":
+			return SortOrder{
 				Aggregator: aggregator,
-				Direction:  direction,
-				Err:        ErrInvalidDirection,
-			}
+				Direction:  ASCENDING,
+			}, nil
+		case "descending":
+			return SortOrder{
+				Aggregator: aggregator,
+				Direction:  DESCENDING,
+			}, nil
+		}
+		return SortOrder{}, SortOrderError{
+			Aggregator: aggregator,
+			Direction:  direction,
+			Err:        ErrInvalidDirection,
 		}
 	}
 	return SortOrder{}, SortOrderError{
@@ -78,6 +112,18 @@ func NewSortOrder(
 	}
 }
 
+// isResultName returns whether name is the name of a result given by
+// the aggregators, including the further results of a confusion
+// aggregator such as: cm_specificity
+func isResultName(aggregatorSpecs []aggregator.Spec, name string) bool {
+	for _, n := range aggregator.ResultNames(aggregatorSpecs) {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func MakeSortOrders(
 	aggregatorSpecs []aggregator.Spec,
 	descs []SortDesc,
@@ -109,6 +155,7 @@ func (d direction) String() string {
 type by struct {
 	ruleAssessments []*RuleAssessment
 	sortOrders      []SortOrder
+	values          sortValues
 }
 
 func (b by) Len() int { return len(b.ruleAssessments) }
@@ -120,11 +167,10 @@ func (b by) Swap(i, j int) {
 func (b by) Less(i, j int) bool {
 	var vI *dlit.Literal
 	var vJ *dlit.Literal
-	for _, sortOrder := range b.sortOrders {
-		aggregator := sortOrder.Aggregator
+	for k, sortOrder := range b.sortOrders {
 		direction := sortOrder.Direction
-		vI = b.ruleAssessments[i].Aggregators[aggregator]
-		vJ = b.ruleAssessments[j].Aggregators[aggregator]
+		vI = b.values.value(k, sortOrder, b.ruleAssessments[i])
+		vJ = b.values.value(k, sortOrder, b.ruleAssessments[j])
 		c := compareDlitNums(vI, vJ)
 
 		if direction == DESCENDING {
@@ -135,16 +181,31 @@ func (b by) Less(i, j int) bool {
 		} else if c > 0 {
 			return false
 		}
+		if sortOrder.isPareto() {
+			crowdingI := b.values.crowding[k][b.ruleAssessments[i]]
+			crowdingJ := b.values.crowding[k][b.ruleAssessments[j]]
+			if crowdingI != crowdingJ {
+				return crowdingI > crowdingJ
+			}
+		}
 	}
 
-	ruleStrI := b.ruleAssessments[i].Rule.String()
-	ruleStrJ := b.ruleAssessments[j].Rule.String()
-	ruleLenI := len(ruleStrI)
-	ruleLenJ := len(ruleStrJ)
-	if ruleLenI != ruleLenJ {
-		return ruleLenI < ruleLenJ
-	}
+	return isSimplerRule(b.ruleAssessments[i].Rule, b.ruleAssessments[j].Rule)
+}
 
+// isSimplerRule is used to break ties between rules, preferring the rule
+// with the lower complexity and then the shorter string
+func isSimplerRule(ruleI, ruleJ rule.Rule) bool {
+	complexityI := rule.Complexity(ruleI)
+	complexityJ := rule.Complexity(ruleJ)
+	if complexityI != complexityJ {
+		return complexityI < complexityJ
+	}
+	ruleStrI := ruleI.String()
+	ruleStrJ := ruleJ.String()
+	if len(ruleStrI) != len(ruleStrJ) {
+		return len(ruleStrI) < len(ruleStrJ)
+	}
 	return strings.Compare(ruleStrI, ruleStrJ) == -1
 }
 
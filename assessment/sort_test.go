@@ -1,6 +1,7 @@
 package assessment
 
 import (
+	"github.com/lawrencewoodman/dexpr"
 	"github.com/vlifesystems/rhkit/aggregator"
 	"testing"
 )
@@ -16,9 +17,36 @@ func TestMakeSortOrders(t *testing.T) {
 			SortDesc{"percentMatches", "descending"},
 		},
 			want: []SortOrder{
-				SortOrder{"income", DESCENDING},
-				SortOrder{"numMatches", ASCENDING},
-				SortOrder{"percentMatches", DESCENDING},
+				SortOrder{Aggregator: "income", Direction: DESCENDING},
+				SortOrder{Aggregator: "numMatches", Direction: ASCENDING},
+				SortOrder{Aggregator: "percentMatches", Direction: DESCENDING},
+			},
+		},
+		{descs: []SortDesc{
+			SortDesc{"0.7*income + 0.3*numMatches", "descending"},
+			SortDesc{"percentMatches", "descending"},
+		},
+			want: []SortOrder{
+				SortOrder{Aggregator: "0.7*income + 0.3*numMatches", Direction: DESCENDING},
+				SortOrder{Aggregator: "percentMatches", Direction: DESCENDING},
+			},
+		},
+		{descs: []SortDesc{
+			SortDesc{"cm_specificity", "descending"},
+			SortDesc{"cm_npv + income", "ascending"},
+		},
+			want: []SortOrder{
+				SortOrder{Aggregator: "cm_specificity", Direction: DESCENDING},
+				SortOrder{Aggregator: "cm_npv + income", Direction: ASCENDING},
+			},
+		},
+		{descs: []SortDesc{
+			SortDesc{"lift", "descending"},
+			SortDesc{"leverage * 100 + lift", "descending"},
+		},
+			want: []SortOrder{
+				SortOrder{Aggregator: "lift", Direction: DESCENDING},
+				SortOrder{Aggregator: "leverage * 100 + lift", Direction: DESCENDING},
 			},
 		},
 		{descs: []SortDesc{},
@@ -28,6 +56,9 @@ func TestMakeSortOrders(t *testing.T) {
 	fields := []string{"in"}
 	aggregatorDescs := []*aggregator.Desc{
 		{Name: "income", Kind: "sum", Arg: "in"},
+		{Name: "cm", Kind: "confusion", Arg: "in > 2"},
+		{Name: "lift", Kind: "lift", Arg: "in > 2"},
+		{Name: "leverage", Kind: "leverage", Arg: "in > 2"},
 	}
 	aggregatorSpecs, err := aggregator.MakeSpecs(fields, aggregatorDescs)
 	if err != nil {
@@ -126,6 +157,45 @@ func TestMakeSortOrders_errors(t *testing.T) {
 				Err:        ErrUnrecognisedAggregator,
 			},
 		},
+		{descs: []SortDesc{
+			SortDesc{"0.7*income + boris", "descending"},
+		},
+			wantErr: SortOrderError{
+				Aggregator: "0.7*income + boris",
+				Direction:  "descending",
+				Err:        ErrUnrecognisedAggregator,
+			},
+		},
+		{descs: []SortDesc{
+			SortDesc{"0.7*income +", "descending"},
+		},
+			wantErr: SortOrderError{
+				Aggregator: "0.7*income +",
+				Direction:  "descending",
+				Err: dexpr.InvalidExprError{
+					Expr: "0.7*income +",
+					Err:  dexpr.ErrSyntax,
+				},
+			},
+		},
+		{descs: []SortDesc{
+			SortDesc{"income > 2", "descending"},
+		},
+			wantErr: SortOrderError{
+				Aggregator: "income > 2",
+				Direction:  "descending",
+				Err:        ErrScoreNotNumber,
+			},
+		},
+		{descs: []SortDesc{
+			SortDesc{"0.7*income", "down"},
+		},
+			wantErr: SortOrderError{
+				Aggregator: "0.7*income",
+				Direction:  "down",
+				Err:        ErrInvalidDirection,
+			},
+		},
 	}
 	fields := []string{"in"}
 	aggregatorDescs := []*aggregator.Desc{
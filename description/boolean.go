@@ -0,0 +1,123 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package description
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lawrencewoodman/dlit"
+)
+
+// BooleanTokens describes which values of a field represent true and
+// which represent false.  The tokens are compared case insensitively.
+type BooleanTokens struct {
+	True  []string `json:"true"`
+	False []string `json:"false"`
+}
+
+// DefaultBooleanTokens are commonly used tokens which can be passed to
+// DescribeDatasetWithTokens to identify Boolean fields
+var DefaultBooleanTokens = BooleanTokens{
+	True:  []string{"yes", "true", "1"},
+	False: []string{"no", "false", "0"},
+}
+
+// IsTrue returns whether the value is one of the true tokens
+func (t BooleanTokens) IsTrue(v *dlit.Literal) bool {
+	return isToken(v, t.True)
+}
+
+// IsFalse returns whether the value is one of the false tokens
+func (t BooleanTokens) IsFalse(v *dlit.Literal) bool {
+	return isToken(v, t.False)
+}
+
+// TrueValue returns the value of a Boolean field that represents true
+func (d *Description) TrueValue(field string) (*dlit.Literal, bool) {
+	return d.booleanValue(field, BooleanTokens.IsTrue)
+}
+
+// FalseValue returns the value of a Boolean field that represents false
+func (d *Description) FalseValue(field string) (*dlit.Literal, bool) {
+	return d.booleanValue(field, BooleanTokens.IsFalse)
+}
+
+func (d *Description) booleanValue(
+	field string,
+	isToken func(BooleanTokens, *dlit.Literal) bool,
+) (*dlit.Literal, bool) {
+	fd, ok := d.Fields[field]
+	if !ok || fd.Kind != Boolean {
+		return nil, false
+	}
+	tokens := DefaultBooleanTokens
+	if d.BooleanTokens != nil {
+		tokens = *d.BooleanTokens
+	}
+	for _, v := range fd.Values {
+		if isToken(tokens, v.Value) {
+			return v.Value, true
+		}
+	}
+	return nil, false
+}
+
+// HaveSameBooleanValues returns whether two Boolean fields use the same
+// values to represent true and false
+func (d *Description) HaveSameBooleanValues(fieldA, fieldB string) bool {
+	trueA, okA := d.TrueValue(fieldA)
+	trueB, okB := d.TrueValue(fieldB)
+	if !okA || !okB || trueA.String() != trueB.String() {
+		return false
+	}
+	falseA, okA := d.FalseValue(fieldA)
+	falseB, okB := d.FalseValue(fieldB)
+	return okA && okB && falseA.String() == falseB.String()
+}
+
+// identifyBooleans changes the Kind of any field with just a true and
+// a false value to Boolean
+func (d *Description) identifyBooleans() {
+	for _, fd := range d.Fields {
+		if (fd.Kind != String && fd.Kind != Number) || fd.NumValues != 2 {
+			continue
+		}
+		numTrue := 0
+		numFalse := 0
+		for _, v := range fd.Values {
+			if d.BooleanTokens.IsTrue(v.Value) {
+				numTrue++
+			} else if d.BooleanTokens.IsFalse(v.Value) {
+				numFalse++
+			}
+		}
+		if numTrue == 1 && numFalse == 1 {
+			fd.Kind = Boolean
+		}
+	}
+}
+
+func (d *Description) checkBooleanTokensEqual(o *Description) error {
+	if (d.BooleanTokens == nil) != (o.BooleanTokens == nil) ||
+		(d.BooleanTokens != nil &&
+			!reflect.DeepEqual(*d.BooleanTokens, *o.BooleanTokens)) {
+		return fmt.Errorf("BooleanTokens not equal: %v != %v",
+			d.BooleanTokens, o.BooleanTokens)
+	}
+	return nil
+}
+
+func isToken(v *dlit.Literal, tokens []string) bool {
+	if v.Err() != nil {
+		return false
+	}
+	for _, t := range tokens {
+		if strings.EqualFold(v.String(), t) {
+			return true
+		}
+	}
+	return false
+}
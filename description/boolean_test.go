@@ -0,0 +1,195 @@
+package description
+
+import (
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+var booleanFieldNames = []string{
+	"default", "housing", "flag", "state", "mixed", "single",
+}
+
+var booleanRecords = [][]string{
+	{"no", "YES", "1", "on", "yes", "no"},
+	{"yes", "no", "0", "off", "1", "no"},
+	{"no", "NO", "0", "on", "no", "no"},
+	{"yes", "yes", "1", "off", "0", "no"},
+}
+
+func TestDescribeDataset_booleans(t *testing.T) {
+	cases := []struct {
+		field string
+		want  FieldType
+	}{
+		{field: "default", want: Boolean},
+		{field: "housing", want: String},
+		{field: "flag", want: Boolean},
+		{field: "state", want: String},
+		{field: "mixed", want: String},
+		{field: "single", want: String},
+	}
+	dataset := testhelpers.NewLiteralDataset(booleanFieldNames, booleanRecords)
+	d, err := DescribeDatasetWithTokens(dataset, DefaultBooleanTokens)
+	if err != nil {
+		t.Fatalf("DescribeDatasetWithTokens: %s", err)
+	}
+	for _, c := range cases {
+		got := d.Fields[c.field].Kind
+		if got != c.want {
+			t.Errorf("DescribeDatasetWithTokens: field: %s, got: %s, want: %s",
+				c.field, got, c.want)
+		}
+	}
+}
+
+func TestDescribeDataset_noBooleans(t *testing.T) {
+	cases := []struct {
+		field string
+		want  FieldType
+	}{
+		{field: "default", want: String},
+		{field: "flag", want: Number},
+		{field: "state", want: String},
+	}
+	dataset := testhelpers.NewLiteralDataset(booleanFieldNames, booleanRecords)
+	d, err := DescribeDataset(dataset)
+	if err != nil {
+		t.Fatalf("DescribeDataset: %s", err)
+	}
+	for _, c := range cases {
+		got := d.Fields[c.field].Kind
+		if got != c.want {
+			t.Errorf("DescribeDataset: field: %s, got: %s, want: %s",
+				c.field, got, c.want)
+		}
+	}
+	if d.BooleanTokens != nil {
+		t.Errorf("DescribeDataset: got BooleanTokens: %v, want: nil",
+			d.BooleanTokens)
+	}
+}
+
+func TestDescribeDatasetWithTokens(t *testing.T) {
+	cases := []struct {
+		field string
+		want  FieldType
+	}{
+		{field: "default", want: String},
+		{field: "flag", want: Number},
+		{field: "state", want: Boolean},
+	}
+	tokens := BooleanTokens{True: []string{"on"}, False: []string{"off"}}
+	dataset := testhelpers.NewLiteralDataset(booleanFieldNames, booleanRecords)
+	d, err := DescribeDatasetWithTokens(dataset, tokens)
+	if err != nil {
+		t.Fatalf("DescribeDatasetWithTokens: %s", err)
+	}
+	for _, c := range cases {
+		got := d.Fields[c.field].Kind
+		if got != c.want {
+			t.Errorf("DescribeDatasetWithTokens: field: %s, got: %s, want: %s",
+				c.field, got, c.want)
+		}
+	}
+	v, ok := d.TrueValue("state")
+	if !ok || v.String() != "on" {
+		t.Errorf("TrueValue: got: %s, %t, want: on, true", v, ok)
+	}
+}
+
+func TestDescriptionTrueValue(t *testing.T) {
+	d := &Description{
+		Fields: map[string]*Field{
+			"default": {
+				Kind: Boolean,
+				Values: map[string]Value{
+					"No":  {dlit.NewString("No"), 3},
+					"Yes": {dlit.NewString("Yes"), 2},
+				},
+				NumValues: 2,
+			},
+			"flag": {
+				Kind: Boolean,
+				Values: map[string]Value{
+					"0": {dlit.NewString("0"), 3},
+					"1": {dlit.NewString("1"), 2},
+				},
+				NumValues: 2,
+			},
+			"band": {
+				Kind: String,
+				Values: map[string]Value{
+					"yes": {dlit.NewString("yes"), 3},
+					"no":  {dlit.NewString("no"), 2},
+				},
+				NumValues: 2,
+			},
+		},
+	}
+	cases := []struct {
+		field  string
+		want   string
+		wantOK bool
+	}{
+		{field: "default", want: "Yes", wantOK: true},
+		{field: "flag", want: "1", wantOK: true},
+		{field: "band", wantOK: false},
+		{field: "missing", wantOK: false},
+	}
+	for _, c := range cases {
+		got, ok := d.TrueValue(c.field)
+		if ok != c.wantOK {
+			t.Errorf("TrueValue(%s) got ok: %t, want: %t", c.field, ok, c.wantOK)
+			continue
+		}
+		if ok && got.String() != c.want {
+			t.Errorf("TrueValue(%s) got: %s, want: %s", c.field, got, c.want)
+		}
+	}
+}
+
+func TestDescriptionFalseValue(t *testing.T) {
+	d := &Description{
+		Fields: map[string]*Field{
+			"default": {
+				Kind: Boolean,
+				Values: map[string]Value{
+					"No":  {dlit.NewString("No"), 3},
+					"Yes": {dlit.NewString("Yes"), 2},
+				},
+				NumValues: 2,
+			},
+		},
+	}
+	got, ok := d.FalseValue("default")
+	if !ok || got.String() != "No" {
+		t.Errorf("FalseValue(default) got: %s, %t, want: No, true", got, ok)
+	}
+	if _, ok := d.FalseValue("missing"); ok {
+		t.Errorf("FalseValue(missing) got ok: true, want: false")
+	}
+}
+
+func TestDescriptionCheckEqual_booleanTokens(t *testing.T) {
+	onOff := BooleanTokens{True: []string{"on"}, False: []string{"off"}}
+	cases := []struct {
+		a       *BooleanTokens
+		b       *BooleanTokens
+		wantErr bool
+	}{
+		{a: nil, b: nil, wantErr: false},
+		{a: &onOff, b: &onOff, wantErr: false},
+		{a: &DefaultBooleanTokens, b: &onOff, wantErr: true},
+		{a: nil, b: &onOff, wantErr: true},
+	}
+	for i, c := range cases {
+		dA := &Description{Fields: map[string]*Field{}, BooleanTokens: c.a}
+		dB := &Description{Fields: map[string]*Field{}, BooleanTokens: c.b}
+		err := dA.CheckEqual(dB)
+		if (err != nil) != c.wantErr {
+			t.Errorf("(%d) CheckEqual got err: %v, want err: %t", i, err, c.wantErr)
+		}
+	}
+}
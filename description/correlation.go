@@ -0,0 +1,366 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package description
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/lawrencewoodman/ddataset"
+)
+
+// Correlation describes how strongly a pair of fields are associated
+type Correlation struct {
+	FieldA string
+	FieldB string
+	Kind   CorrelationKind
+	Value  float64
+}
+
+// CorrelationKind is the statistic used to measure a Correlation
+type CorrelationKind int
+
+const (
+	// Pearson is the Pearson correlation coefficient between two
+	// Number fields, it ranges from -1 to 1
+	Pearson CorrelationKind = iota
+	// CramersV is Cramér's V between two categorical fields,
+	// it ranges from 0 to 1
+	CramersV
+)
+
+// correlationJ is used for JSON Marshal/Unmarshal
+type correlationJ struct {
+	FieldA string  `json:"fieldA"`
+	FieldB string  `json:"fieldB"`
+	Kind   string  `json:"kind"`
+	Value  float64 `json:"value"`
+}
+
+// InvalidCorrelationKindError indicates that a CorrelationKind isn't
+// supported
+type InvalidCorrelationKindError string
+
+func (e InvalidCorrelationKindError) Error() string {
+	return "unsupported correlation kind: " + string(e)
+}
+
+// NewCorrelationKind creates a new CorrelationKind and will panic if an
+// unsupported kind is given
+func NewCorrelationKind(s string) CorrelationKind {
+	k, err := parseCorrelationKind(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return k
+}
+
+func parseCorrelationKind(s string) (CorrelationKind, error) {
+	switch s {
+	case "Pearson":
+		return Pearson, nil
+	case "CramersV":
+		return CramersV, nil
+	}
+	return 0, InvalidCorrelationKindError(s)
+}
+
+// String returns the string representation of the CorrelationKind
+func (k CorrelationKind) String() string {
+	switch k {
+	case Pearson:
+		return "Pearson"
+	case CramersV:
+		return "CramersV"
+	}
+	panic(fmt.Sprintf("unsupported correlation kind: %d", k))
+}
+
+func (c *Correlation) UnmarshalJSON(b []byte) error {
+	var cj correlationJ
+	if err := json.Unmarshal(b, &cj); err != nil {
+		return err
+	}
+	kind, err := parseCorrelationKind(cj.Kind)
+	if err != nil {
+		return err
+	}
+	c.FieldA = cj.FieldA
+	c.FieldB = cj.FieldB
+	c.Kind = kind
+	c.Value = cj.Value
+	return nil
+}
+
+func (c *Correlation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&correlationJ{
+		FieldA: c.FieldA,
+		FieldB: c.FieldB,
+		Kind:   c.Kind.String(),
+		Value:  c.Value,
+	})
+}
+
+// Correlate analyses a Dataset to find the pairwise correlation between
+// its fields and records them in the Description.  This is an optional
+// extra pass over the Dataset and must be made after DescribeDataset.
+// Pairs of Number fields use the Pearson correlation coefficient and
+// pairs of fields with a limited number of values use Cramér's V.
+func (d *Description) Correlate(dataset ddataset.Dataset) error {
+	pearsons, cramers := d.makeCorrelators()
+	conn, err := dataset.Open()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for conn.Next() {
+		record := conn.Read()
+		for _, p := range pearsons {
+			p.nextRecord(record)
+		}
+		for _, c := range cramers {
+			c.nextRecord(record)
+		}
+	}
+	if err := conn.Err(); err != nil {
+		return err
+	}
+
+	correlations := []*Correlation{}
+	for _, p := range pearsons {
+		if v, ok := p.result(); ok {
+			correlations = append(correlations, &Correlation{
+				FieldA: p.fieldA,
+				FieldB: p.fieldB,
+				Kind:   Pearson,
+				Value:  v,
+			})
+		}
+	}
+	for _, c := range cramers {
+		if v, ok := c.result(); ok {
+			correlations = append(correlations, &Correlation{
+				FieldA: c.fieldA,
+				FieldB: c.fieldB,
+				Kind:   CramersV,
+				Value:  v,
+			})
+		}
+	}
+	sort.Sort(byFields(correlations))
+	d.Correlations = correlations
+	return nil
+}
+
+// correlationIndexMu protects the correlation index of each Description
+var correlationIndexMu sync.RWMutex
+
+// correlationIndex maps a pair of fields, in sorted order, to their
+// Correlation.  It records the Correlations it was made from so that it
+// can be remade if they are replaced.
+type correlationIndex struct {
+	correlations []*Correlation
+	pairs        map[[2]string]*Correlation
+}
+
+// Correlation returns the Correlation between two fields, if known.
+// This is called for each pair of fields when generating rules and
+// therefore looks the pair up in an index made from Correlations.
+func (d *Description) Correlation(fieldA, fieldB string) (*Correlation, bool) {
+	if fieldA > fieldB {
+		fieldA, fieldB = fieldB, fieldA
+	}
+	correlationIndexMu.RLock()
+	index := d.correlationIndex
+	correlationIndexMu.RUnlock()
+	if !index.isFor(d.Correlations) {
+		index = d.makeCorrelationIndex()
+	}
+	c, ok := index.pairs[[2]string{fieldA, fieldB}]
+	return c, ok
+}
+
+func (d *Description) makeCorrelationIndex() *correlationIndex {
+	correlationIndexMu.Lock()
+	defer correlationIndexMu.Unlock()
+	if d.correlationIndex.isFor(d.Correlations) {
+		return d.correlationIndex
+	}
+	index := &correlationIndex{
+		correlations: d.Correlations,
+		pairs:        make(map[[2]string]*Correlation, len(d.Correlations)),
+	}
+	for _, c := range d.Correlations {
+		index.pairs[[2]string{c.FieldA, c.FieldB}] = c
+	}
+	d.correlationIndex = index
+	return index
+}
+
+// isFor returns whether the index was made from correlations
+func (ci *correlationIndex) isFor(correlations []*Correlation) bool {
+	if ci == nil || len(ci.correlations) != len(correlations) {
+		return false
+	}
+	return len(correlations) == 0 || &ci.correlations[0] == &correlations[0]
+}
+
+// IsStrong returns whether the Correlation indicates that the fields
+// are near duplicates of each other
+func (c *Correlation) IsStrong(threshold float64) bool {
+	return math.Abs(c.Value) >= threshold
+}
+
+func (d *Description) makeCorrelators() ([]*pearsonCorrelator, []*cramersCorrelator) {
+	fields := d.FieldNames()
+	sort.Strings(fields)
+	pearsons := []*pearsonCorrelator{}
+	cramers := []*cramersCorrelator{}
+	for i, fieldA := range fields {
+		fdA := d.Fields[fieldA]
+		for _, fieldB := range fields[i+1:] {
+			fdB := d.Fields[fieldB]
+			if fdA.Kind == Number && fdB.Kind == Number {
+				pearsons = append(pearsons, &pearsonCorrelator{
+					fieldA: fieldA,
+					fieldB: fieldB,
+				})
+			} else if fdA.isCategorical() && fdB.isCategorical() {
+				cramers = append(cramers, &cramersCorrelator{
+					fieldA:    fieldA,
+					fieldB:    fieldB,
+					counts:    map[[2]string]float64{},
+					rowTotals: map[string]float64{},
+					colTotals: map[string]float64{},
+				})
+			}
+		}
+	}
+	return pearsons, cramers
+}
+
+func (f *Field) isCategorical() bool {
+	return (f.Kind == String || f.Kind == Number || f.Kind == Boolean) &&
+		f.NumValues >= 2
+}
+
+func (d *Description) checkCorrelationsEqual(o *Description) error {
+	if len(d.Correlations) != len(o.Correlations) {
+		return fmt.Errorf(
+			"number of Correlations doesn't match: %d != %d",
+			len(d.Correlations), len(o.Correlations),
+		)
+	}
+	for i, c := range d.Correlations {
+		oc := o.Correlations[i]
+		if *c != *oc {
+			return fmt.Errorf("Correlation not equal: %v != %v", *c, *oc)
+		}
+	}
+	return nil
+}
+
+// pearsonCorrelator uses Welford's online algorithm, extended to find
+// the co-moment, so that it remains accurate for values with a large
+// mean compared to their spread
+type pearsonCorrelator struct {
+	fieldA string
+	fieldB string
+	n      float64
+	meanA  float64
+	meanB  float64
+	m2A    float64
+	m2B    float64
+	coAB   float64
+}
+
+func (p *pearsonCorrelator) nextRecord(record ddataset.Record) {
+	a, aIsFloat := record[p.fieldA].Float()
+	b, bIsFloat := record[p.fieldB].Float()
+	if !aIsFloat || !bIsFloat {
+		return
+	}
+	p.n++
+	deltaA := a - p.meanA
+	deltaB := b - p.meanB
+	p.meanA += deltaA / p.n
+	p.meanB += deltaB / p.n
+	p.m2A += deltaA * (a - p.meanA)
+	p.m2B += deltaB * (b - p.meanB)
+	p.coAB += deltaA * (b - p.meanB)
+}
+
+func (p *pearsonCorrelator) result() (float64, bool) {
+	if p.n < 2 || p.m2A <= 0 || p.m2B <= 0 {
+		return 0, false
+	}
+	r := p.coAB / math.Sqrt(p.m2A*p.m2B)
+	if math.IsNaN(r) || math.IsInf(r, 0) {
+		return 0, false
+	}
+	return roundCorrelation(math.Max(-1, math.Min(1, r))), true
+}
+
+type cramersCorrelator struct {
+	fieldA    string
+	fieldB    string
+	n         float64
+	counts    map[[2]string]float64
+	rowTotals map[string]float64
+	colTotals map[string]float64
+}
+
+func (c *cramersCorrelator) nextRecord(record ddataset.Record) {
+	a := record[c.fieldA].String()
+	b := record[c.fieldB].String()
+	c.n++
+	c.counts[[2]string{a, b}]++
+	c.rowTotals[a]++
+	c.colTotals[b]++
+}
+
+func (c *cramersCorrelator) result() (float64, bool) {
+	minDim := math.Min(float64(len(c.rowTotals)), float64(len(c.colTotals))) - 1
+	if c.n == 0 || minDim < 1 {
+		return 0, false
+	}
+	chi2 := 0.0
+	for a, rowTotal := range c.rowTotals {
+		for b, colTotal := range c.colTotals {
+			expected := rowTotal * colTotal / c.n
+			diff := c.counts[[2]string{a, b}] - expected
+			chi2 += diff * diff / expected
+		}
+	}
+	v := math.Sqrt(chi2 / c.n / minDim)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return roundCorrelation(math.Min(1, v)), true
+}
+
+func roundCorrelation(v float64) float64 {
+	if v < 0 {
+		return -math.Floor(-v*10000+0.5) / 10000
+	}
+	return math.Floor(v*10000+0.5) / 10000
+}
+
+// byFields implements sort.Interface for []*Correlation
+type byFields []*Correlation
+
+func (cs byFields) Len() int { return len(cs) }
+func (cs byFields) Swap(i, j int) {
+	cs[i], cs[j] = cs[j], cs[i]
+}
+func (cs byFields) Less(i, j int) bool {
+	if cs[i].FieldA != cs[j].FieldA {
+		return cs[i].FieldA < cs[j].FieldA
+	}
+	return cs[i].FieldB < cs[j].FieldB
+}
@@ -0,0 +1,190 @@
+package description
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+func TestCorrelate(t *testing.T) {
+	fieldNames := []string{"age", "years", "height", "job", "role", "town"}
+	records := [][]string{
+		{"20", "40", "150", "a", "x", "p"},
+		{"25", "50", "140", "b", "y", "q"},
+		{"30", "60", "190", "a", "x", "r"},
+		{"35", "70", "160", "b", "y", "p"},
+		{"40", "80", "170", "a", "x", "q"},
+		{"45", "90", "150", "b", "y", "r"},
+	}
+	dataset := testhelpers.NewLiteralDataset(fieldNames, records)
+	d, err := DescribeDataset(dataset)
+	if err != nil {
+		t.Fatalf("DescribeDataset: %s", err)
+	}
+	if err := d.Correlate(dataset); err != nil {
+		t.Fatalf("Correlate: %s", err)
+	}
+	cases := []struct {
+		fieldA string
+		fieldB string
+		kind   CorrelationKind
+		want   float64
+	}{
+		{fieldA: "age", fieldB: "years", kind: Pearson, want: 1},
+		{fieldA: "years", fieldB: "age", kind: Pearson, want: 1},
+		{fieldA: "age", fieldB: "height", kind: Pearson, want: 0.1793},
+		{fieldA: "job", fieldB: "role", kind: CramersV, want: 1},
+		{fieldA: "job", fieldB: "town", kind: CramersV, want: 0},
+		{fieldA: "age", fieldB: "job", kind: CramersV, want: 1},
+	}
+	for i, c := range cases {
+		got, ok := d.Correlation(c.fieldA, c.fieldB)
+		if !ok {
+			t.Errorf("(%d) Correlation(%s, %s) not found", i, c.fieldA, c.fieldB)
+			continue
+		}
+		if got.Kind != c.kind || got.Value != c.want {
+			t.Errorf("(%d) Correlation(%s, %s) got: %s %f, want: %s %f",
+				i, c.fieldA, c.fieldB, got.Kind, got.Value, c.kind, c.want)
+		}
+	}
+}
+
+func TestCorrelate_errors(t *testing.T) {
+	fieldNames :=
+		[]string{"band", "inputA", "inputB", "version", "flow", "score", "method"}
+	dataset := testhelpers.NewLiteralDataset(fieldNames, flowRecords)
+	d, err := DescribeDataset(dataset)
+	if err != nil {
+		t.Fatalf("DescribeDataset: %s", err)
+	}
+	cases := []struct {
+		stage int
+		err   error
+	}{
+		{stage: 0, err: errors.New("can't open database")},
+		{stage: 1, err: errors.New("read error")},
+	}
+	for _, c := range cases {
+		fdataset := NewFailingDataset(dataset, c.stage, c.err)
+		err := d.Correlate(fdataset)
+		if err == nil || err.Error() != c.err.Error() {
+			t.Errorf("Correlate(dataset) err: %s, want: %s", err, c.err)
+		}
+	}
+}
+
+func TestCorrelationMarshalUnmarshalJSON(t *testing.T) {
+	fieldNames :=
+		[]string{"band", "inputA", "inputB", "version", "flow", "score", "method"}
+	dataset := testhelpers.NewLiteralDataset(fieldNames, flowRecords)
+	description, err := DescribeDataset(dataset)
+	if err != nil {
+		t.Fatalf("DescribeDataset: %s", err)
+	}
+	if err := description.Correlate(dataset); err != nil {
+		t.Fatalf("Correlate: %s", err)
+	}
+	if len(description.Correlations) == 0 {
+		t.Fatalf("Correlate: no correlations found")
+	}
+	b, err := json.Marshal(description)
+	if err != nil {
+		t.Fatalf("Marshal: %s", err)
+	}
+	var got Description
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	if err := got.CheckEqual(description); err != nil {
+		t.Errorf("Unmarshal got not expected: %s", err)
+	}
+}
+
+func TestNewCorrelationKind_panic(t *testing.T) {
+	kind := "invalid"
+	paniced := false
+	wantPanic := fmt.Sprintf("unsupported correlation kind: %s", kind)
+	defer func() {
+		if r := recover(); r != nil {
+			if r.(string) == wantPanic {
+				paniced = true
+			} else {
+				t.Errorf("NewCorrelationKind: got panic: %s, wanted: %s", r, wantPanic)
+			}
+		}
+	}()
+	got := NewCorrelationKind(kind)
+	if !paniced {
+		t.Errorf("NewCorrelationKind: got: %s, failed to panic with: %s",
+			got, wantPanic)
+	}
+}
+
+func TestCorrelationUnmarshalJSON_error(t *testing.T) {
+	b := []byte(`{"fieldA":"a","fieldB":"b","kind":"Spearman","value":0.5}`)
+	var got Correlation
+	err := json.Unmarshal(b, &got)
+	wantErr := InvalidCorrelationKindError("Spearman")
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Errorf("Unmarshal err: %v, wantErr: %s", err, wantErr)
+	}
+}
+
+func TestCorrelate_largeMean(t *testing.T) {
+	fieldNames := []string{"a", "b"}
+	records := [][]string{
+		{"1000000001", "4000000003"},
+		{"1000000002", "4000000001"},
+		{"1000000003", "4000000004"},
+		{"1000000004", "4000000002"},
+	}
+	dataset := testhelpers.NewLiteralDataset(fieldNames, records)
+	d, err := DescribeDataset(dataset)
+	if err != nil {
+		t.Fatalf("DescribeDataset: %s", err)
+	}
+	if err := d.Correlate(dataset); err != nil {
+		t.Fatalf("Correlate: %s", err)
+	}
+	got, ok := d.Correlation("a", "b")
+	if !ok {
+		t.Fatalf("Correlation(a, b) not found")
+	}
+	if got.Kind != Pearson || got.Value != 0 {
+		t.Errorf("Correlation(a, b) got: %s %f, want: Pearson 0",
+			got.Kind, got.Value)
+	}
+}
+
+func TestDescriptionCorrelation_replaced(t *testing.T) {
+	d := &Description{
+		Correlations: []*Correlation{
+			{FieldA: "a", FieldB: "b", Kind: Pearson, Value: 0.5},
+		},
+	}
+	if got, ok := d.Correlation("b", "a"); !ok || got.Value != 0.5 {
+		t.Errorf("Correlation(b, a) got: %v, %t, want: 0.5", got, ok)
+	}
+	d.Correlations = []*Correlation{
+		{FieldA: "a", FieldB: "c", Kind: Pearson, Value: 0.7},
+	}
+	if _, ok := d.Correlation("a", "b"); ok {
+		t.Errorf("Correlation(a, b) found after Correlations replaced")
+	}
+	if got, ok := d.Correlation("a", "c"); !ok || got.Value != 0.7 {
+		t.Errorf("Correlation(a, c) got: %v, %t, want: 0.7", got, ok)
+	}
+}
+
+func TestInvalidCorrelationKindErrorError(t *testing.T) {
+	err := InvalidCorrelationKindError("Spearman")
+	want := "unsupported correlation kind: Spearman"
+	got := err.Error()
+	if got != want {
+		t.Errorf("Error() got: %s, want: %s", got, want)
+	}
+}
@@ -15,7 +15,11 @@ import (
 
 // Description describes a Dataset
 type Description struct {
-	Fields map[string]*Field `json:"fields"`
+	Fields        map[string]*Field `json:"fields"`
+	Correlations  []*Correlation    `json:"correlations,omitempty"`
+	BooleanTokens *BooleanTokens    `json:"booleanTokens,omitempty"`
+	// correlationIndex is used by Correlation to look up Correlations
+	correlationIndex *correlationIndex
 }
 
 // Value describes a value in a field
@@ -32,6 +36,24 @@ func (e InvalidFieldError) Error() string {
 
 // DescribeDataset analyses a Dataset and returns a Description of it
 func DescribeDataset(dataset ddataset.Dataset) (*Description, error) {
+	return describeDataset(dataset, nil)
+}
+
+// DescribeDatasetWithTokens analyses a Dataset and returns a Description
+// of it.  Fields that just contain a true and a false value from the
+// supplied BooleanTokens, such as DefaultBooleanTokens, are described
+// as Boolean.
+func DescribeDatasetWithTokens(
+	dataset ddataset.Dataset,
+	tokens BooleanTokens,
+) (*Description, error) {
+	return describeDataset(dataset, &tokens)
+}
+
+func describeDataset(
+	dataset ddataset.Dataset,
+	tokens *BooleanTokens,
+) (*Description, error) {
 	if err := checkFieldsValid(dataset.Fields()); err != nil {
 		return nil, err
 	}
@@ -46,8 +68,14 @@ func DescribeDataset(dataset ddataset.Dataset) (*Description, error) {
 		record := conn.Read()
 		desc.nextRecord(record)
 	}
-
-	return desc, conn.Err()
+	if err := conn.Err(); err != nil {
+		return desc, err
+	}
+	if tokens != nil {
+		desc.BooleanTokens = tokens
+		desc.identifyBooleans()
+	}
+	return desc, nil
 }
 
 // Calculates the field number based on the string sorted order of
@@ -87,7 +115,10 @@ func (d *Description) CheckEqual(o *Description) error {
 			return fmt.Errorf("description for field: %s, %s", field, err)
 		}
 	}
-	return nil
+	if err := d.checkBooleanTokensEqual(o); err != nil {
+		return err
+	}
+	return d.checkCorrelationsEqual(o)
 }
 
 func (d *Description) FieldNames() []string {
@@ -103,7 +134,7 @@ func (d *Description) FieldNames() []string {
 // newDescription creates a new Description
 func newDescription() *Description {
 	fd := map[string]*Field{}
-	return &Description{fd}
+	return &Description{Fields: fd}
 }
 
 // nextRecord updates the description after analysing the supplied record
@@ -61,7 +61,7 @@ func TestDescribeDataset(t *testing.T) {
 	fieldNames :=
 		[]string{"band", "inputA", "inputB", "version", "flow", "score", "method"}
 	expected := &Description{
-		map[string]*Field{
+		Fields: map[string]*Field{
 			"band": {String, nil, nil, 0,
 				map[string]Value{
 					"a": {dlit.MustNew("a"), 2},
@@ -203,7 +203,7 @@ func TestDescribeDataset_field_errors(t *testing.T) {
 
 func TestDescriptionMarshalUnmarshalJSON(t *testing.T) {
 	description := &Description{
-		map[string]*Field{
+		Fields: map[string]*Field{
 			"band": {String, nil, nil, 0,
 				map[string]Value{
 					"a": {dlit.MustNew("a"), 2},
@@ -293,7 +293,7 @@ func TestDescriptionMarshalUnmarshalJSON(t *testing.T) {
 func TestDescriptionCheckEqual(t *testing.T) {
 	descriptions := []*Description{
 		{
-			map[string]*Field{
+			Fields: map[string]*Field{
 				"band": {String, nil, nil, 0,
 					map[string]Value{
 						"a": {dlit.MustNew("a"), 2},
@@ -336,7 +336,7 @@ func TestDescriptionCheckEqual(t *testing.T) {
 			},
 		},
 		{
-			map[string]*Field{
+			Fields: map[string]*Field{
 				"band": {String, nil, nil, 0,
 					map[string]Value{
 						"a": {dlit.MustNew("a"), 2},
@@ -365,7 +365,7 @@ func TestDescriptionCheckEqual(t *testing.T) {
 			},
 		},
 		{
-			map[string]*Field{
+			Fields: map[string]*Field{
 				"strata": {String, nil, nil, 0,
 					map[string]Value{
 						"a": {dlit.MustNew("a"), 2},
@@ -408,7 +408,7 @@ func TestDescriptionCheckEqual(t *testing.T) {
 			},
 		},
 		{
-			map[string]*Field{
+			Fields: map[string]*Field{
 				"band": {String, nil, nil, 0,
 					map[string]Value{
 						"a": {dlit.MustNew("a"), 2},
@@ -681,7 +681,7 @@ func TestFieldCheckEqual(t *testing.T) {
 
 func TestDescriptionCalcFieldNum(t *testing.T) {
 	description := &Description{
-		map[string]*Field{
+		Fields: map[string]*Field{
 			"inputA": {
 				Number,
 				dlit.MustNew(7),
@@ -741,7 +741,7 @@ func TestDescriptionCalcFieldNum(t *testing.T) {
 
 func TestDescriptionCalcFieldNum_panic(t *testing.T) {
 	description := &Description{
-		map[string]*Field{
+		Fields: map[string]*Field{
 			"inputA": {
 				Number,
 				dlit.MustNew(7),
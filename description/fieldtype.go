@@ -12,6 +12,7 @@ const (
 	Ignore
 	Number
 	String
+	Boolean
 )
 
 // NewFieldType creates a new FieldType and will panic if an unsupported type is given
@@ -25,6 +26,8 @@ func NewFieldType(s string) FieldType {
 		return Number
 	case "String":
 		return String
+	case "Boolean":
+		return Boolean
 	}
 	panic(fmt.Sprintf("unsupported type: %s", s))
 }
@@ -40,6 +43,8 @@ func (ft FieldType) String() string {
 		return "Number"
 	case String:
 		return "String"
+	case Boolean:
+		return "Boolean"
 	}
 	panic(fmt.Sprintf("unsupported type: %d", ft))
 }
@@ -14,6 +14,7 @@ func TestNewFieldType(t *testing.T) {
 		{"Ignore", Ignore},
 		{"Number", Number},
 		{"String", String},
+		{"Boolean", Boolean},
 	}
 
 	for _, c := range cases {
@@ -52,6 +53,7 @@ func TestFieldTypeString(t *testing.T) {
 		{Ignore, "Ignore"},
 		{Number, "Number"},
 		{String, "String"},
+		{Boolean, "Boolean"},
 	}
 
 	for _, c := range cases {
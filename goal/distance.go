@@ -0,0 +1,181 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package goal
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+)
+
+// distancer measures how far aggregators are from passing part of a goal
+type distancer interface {
+	// distance returns the distance and whether it could be measured
+	distance(aggregators map[string]*dlit.Literal) (float64, bool)
+}
+
+// compareDistancer measures the distance of a comparison such as:
+// precision > 0.7
+type compareDistancer struct {
+	op  token.Token
+	lhs *dexpr.Expr
+	rhs *dexpr.Expr
+}
+
+// logicalDistancer measures the distance of && and || expressions.  The
+// distance of && is the sum of the distances of each side and the
+// distance of || is the smallest distance of each side.
+type logicalDistancer struct {
+	isAnd bool
+	a     distancer
+	b     distancer
+}
+
+// Distance returns how far the aggregators are from passing the goal.
+// For comparison goals, such as precision > 0.7, this is how much the
+// aggregator would have to change to pass the goal, divided by the
+// scale of the comparison so that goals over large values, such as
+// numMatches, don't swamp those over small values, such as precision.
+// The scale is the largest absolute value of either side, or 1 if that
+// is smaller.  For != goals the change is the smallest step that would
+// pass: 1 if both sides are integers, otherwise 0.0001.  For && the
+// distances are added and for || the smallest distance is used.  If the
+// goal passes then the distance is 0 and if it fails but the distance
+// can't be measured then the distance is 1.
+func (g *Goal) Distance(aggregators map[string]*dlit.Literal) (float64, error) {
+	passed, err := g.Assess(aggregators)
+	if err != nil {
+		return 0, err
+	}
+	if passed {
+		return 0, nil
+	}
+	if g.distancer != nil {
+		if d, ok := g.distancer.distance(aggregators); ok {
+			return d, nil
+		}
+	}
+	return 1, nil
+}
+
+// Distance returns the sum of the distances of the goals
+func Distance(
+	goals []*Goal,
+	aggregators map[string]*dlit.Literal,
+) (float64, error) {
+	distance := 0.0
+	for _, g := range goals {
+		d, err := g.Distance(aggregators)
+		if err != nil {
+			return 0, err
+		}
+		distance += d
+	}
+	return distance, nil
+}
+
+// newDistancer returns a distancer for a goal expression or nil if
+// the expression isn't made up of comparisons
+func newDistancer(exprStr string) distancer {
+	node, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return nil
+	}
+	return makeDistancer(exprStr, node)
+}
+
+func makeDistancer(exprStr string, node ast.Expr) distancer {
+	switch x := node.(type) {
+	case *ast.ParenExpr:
+		return makeDistancer(exprStr, x.X)
+	case *ast.BinaryExpr:
+		switch x.Op {
+		case token.LAND, token.LOR:
+			a := makeDistancer(exprStr, x.X)
+			b := makeDistancer(exprStr, x.Y)
+			if a == nil || b == nil {
+				return nil
+			}
+			return &logicalDistancer{isAnd: x.Op == token.LAND, a: a, b: b}
+		case token.GTR, token.GEQ, token.LSS, token.LEQ, token.EQL, token.NEQ:
+			lhs, err := dexpr.New(nodeString(exprStr, x.X), dexprfuncs.CallFuncs)
+			if err != nil {
+				return nil
+			}
+			rhs, err := dexpr.New(nodeString(exprStr, x.Y), dexprfuncs.CallFuncs)
+			if err != nil {
+				return nil
+			}
+			return &compareDistancer{op: x.Op, lhs: lhs, rhs: rhs}
+		}
+	}
+	return nil
+}
+
+// nodeString returns the part of exprStr that node was parsed from
+func nodeString(exprStr string, node ast.Node) string {
+	return exprStr[node.Pos()-1 : node.End()-1]
+}
+
+// neqFloatStep is the change needed to pass a failed !=, > or < goal if
+// either side isn't an integer
+const neqFloatStep = 0.0001
+
+func (d *compareDistancer) distance(
+	aggregators map[string]*dlit.Literal,
+) (float64, bool) {
+	lhsL := d.lhs.Eval(aggregators)
+	rhsL := d.rhs.Eval(aggregators)
+	lhs, lhsIsFloat := lhsL.Float()
+	rhs, rhsIsFloat := rhsL.Float()
+	if !lhsIsFloat || !rhsIsFloat {
+		return 0, false
+	}
+	scale := math.Max(1, math.Max(math.Abs(lhs), math.Abs(rhs)))
+	switch d.op {
+	case token.GTR, token.LSS, token.NEQ:
+		if lhs == rhs {
+			return minStep(lhsL, rhsL) / scale, true
+		}
+	}
+	switch d.op {
+	case token.GTR, token.GEQ:
+		return math.Max(0, rhs-lhs) / scale, true
+	case token.LSS, token.LEQ:
+		return math.Max(0, lhs-rhs) / scale, true
+	case token.NEQ:
+		return 0, true
+	}
+	return math.Abs(lhs-rhs) / scale, true
+}
+
+// minStep returns the smallest step needed to move one value away from
+// another that it equals
+func minStep(lhs, rhs *dlit.Literal) float64 {
+	_, lhsIsInt := lhs.Int()
+	_, rhsIsInt := rhs.Int()
+	if lhsIsInt && rhsIsInt {
+		return 1
+	}
+	return neqFloatStep
+}
+
+func (d *logicalDistancer) distance(
+	aggregators map[string]*dlit.Literal,
+) (float64, bool) {
+	a, aOk := d.a.distance(aggregators)
+	b, bOk := d.b.distance(aggregators)
+	if !aOk || !bOk {
+		return 0, false
+	}
+	if d.isAnd {
+		return a + b, true
+	}
+	return math.Min(a, b), true
+}
@@ -0,0 +1,92 @@
+package goal
+
+import (
+	"github.com/lawrencewoodman/dexpr"
+	"github.com/lawrencewoodman/dlit"
+	"math"
+	"testing"
+)
+
+func TestGoalDistance(t *testing.T) {
+	aggregators := map[string]*dlit.Literal{
+		"precision":  dlit.MustNew(0.69),
+		"numMatches": dlit.MustNew(50),
+		"name":       dlit.MustNew("fred"),
+	}
+	cases := []struct {
+		goal string
+		want float64
+	}{
+		{goal: "precision > 0.7", want: 0.01},
+		{goal: "precision >= 0.6", want: 0},
+		{goal: "0.7 < precision", want: 0.01},
+		{goal: "precision < 0.5", want: 0.19},
+		{goal: "numMatches <= 20", want: 0.6},
+		{goal: "numMatches == 45", want: 0.1},
+		{goal: "numMatches * 2 >= 110", want: 10.0 / 110.0},
+		{goal: "(numMatches > 60)", want: 10.0 / 60.0},
+		{goal: "precision > 0.7 && numMatches > 60", want: 0.01 + 10.0/60.0},
+		{goal: "precision > 0.7 && numMatches > 40", want: 0.01},
+		{goal: "precision > 0.8 || numMatches > 55", want: 5.0 / 55.0},
+		{goal: "precision > 0.8 || numMatches > 40", want: 0},
+		{goal: "numMatches != 50", want: 0.02},
+		{goal: "numMatches != 40", want: 0},
+		{goal: "precision != 0.69", want: 0.0001},
+		{goal: "numMatches > 50", want: 0.02},
+		{goal: "numMatches < 50", want: 0.02},
+		{goal: "precision > 0.69", want: 0.0001},
+		{goal: "0.69 < precision", want: 0.0001},
+		{goal: "numMatches >= 50", want: 0},
+		{goal: "precision < -2", want: 2.69 / 2},
+		{goal: "name == \"bob\"", want: 1},
+		{goal: "precision > 0.7 && name == \"bob\"", want: 1},
+	}
+	for _, c := range cases {
+		g := MustNew(c.goal)
+		got, err := g.Distance(aggregators)
+		if err != nil {
+			t.Errorf("Distance(%s) err: %s", c.goal, err)
+			continue
+		}
+		if math.Abs(got-c.want) > 0.000001 {
+			t.Errorf("Distance(%s) got: %f, want: %f", c.goal, got, c.want)
+		}
+	}
+}
+
+func TestGoalDistance_errors(t *testing.T) {
+	aggregators := map[string]*dlit.Literal{
+		"precision": dlit.MustNew(0.69),
+	}
+	g := MustNew("recall > 0.7")
+	wantErr := dexpr.InvalidExprError{
+		Expr: "recall > 0.7",
+		Err:  dexpr.VarNotExistError("recall"),
+	}
+	_, err := g.Distance(aggregators)
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Errorf("Distance err: %v, want: %v", err, wantErr)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	aggregators := map[string]*dlit.Literal{
+		"precision":  dlit.MustNew(0.5),
+		"numMatches": dlit.MustNew(50),
+	}
+	goals, err := MakeGoals([]string{
+		"precision > 0.75",
+		"numMatches > 40",
+		"numMatches < 20",
+	})
+	if err != nil {
+		t.Fatalf("MakeGoals: %s", err)
+	}
+	got, err := Distance(goals, aggregators)
+	if err != nil {
+		t.Fatalf("Distance: %s", err)
+	}
+	if math.Abs(got-0.85) > 0.000001 {
+		t.Errorf("Distance got: %f, want: 0.85", got)
+	}
+}
@@ -5,6 +5,7 @@
 package goal
 
 import (
+	"errors"
 	"fmt"
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
@@ -12,9 +13,44 @@ import (
 )
 
 type Goal struct {
-	expr *dexpr.Expr
+	expr      *dexpr.Expr
+	weight    float64
+	mode      Mode
+	distancer distancer
 }
 
+// Mode describes how the goals are combined to give a goals score
+type Mode int
+
+const (
+	// Strict gives the weight of each goal passed until a goal fails and
+	// then a thousandth of the weight of each later goal passed, so that
+	// earlier goals take priority over later ones
+	Strict Mode = iota
+	// WeightedSum gives the sum of the weights of the goals passed
+	WeightedSum
+	// AllOrNothing gives the sum of the weights of the goals if they all
+	// pass, otherwise 0
+	AllOrNothing
+)
+
+// Options control how goals made by MakeGoals are scored
+type Options struct {
+	// Mode is how the goals are combined to give a goals score
+	Mode Mode
+	// Weights are the weights of each goal, if not supplied each goal
+	// has a weight of 1
+	Weights []float64
+}
+
+var (
+	ErrNumWeights     = errors.New("number of weights doesn't match number of goals")
+	ErrNegativeWeight = errors.New("weight can't be negative")
+	ErrInvalidMode    = errors.New("invalid mode")
+	ErrNumOptions     = errors.New("more than one Options passed")
+	ErrMixedModes     = errors.New("goals have different modes")
+)
+
 type InvalidGoalError string
 
 func (e InvalidGoalError) Error() string {
@@ -26,18 +62,46 @@ func New(exprStr string) (*Goal, error) {
 	if err != nil {
 		return nil, InvalidGoalError(exprStr)
 	}
-	return &Goal{expr}, nil
+	return &Goal{
+		expr:      expr,
+		weight:    1,
+		mode:      Strict,
+		distancer: newDistancer(exprStr),
+	}, nil
 }
 
-// MakeGoals creates a slice of goals from the supplied expressions
-func MakeGoals(exprs []string) ([]*Goal, error) {
+// MakeGoals creates a slice of goals from the supplied expressions.
+// A single Options may optionally be passed to set how the goals are
+// scored, if more are passed then ErrNumOptions is returned.
+func MakeGoals(exprs []string, opts ...Options) ([]*Goal, error) {
 	var err error
+	var o Options
+	switch len(opts) {
+	case 0:
+	case 1:
+		o = opts[0]
+	default:
+		return []*Goal{}, ErrNumOptions
+	}
+	if o.Mode < Strict || o.Mode > AllOrNothing {
+		return []*Goal{}, ErrInvalidMode
+	}
+	if o.Weights != nil && len(o.Weights) != len(exprs) {
+		return []*Goal{}, ErrNumWeights
+	}
 	r := make([]*Goal, len(exprs))
 	for i, expr := range exprs {
 		r[i], err = New(expr)
 		if err != nil {
 			return r, err
 		}
+		r[i].mode = o.Mode
+		if o.Weights != nil {
+			if o.Weights[i] < 0 {
+				return r, ErrNegativeWeight
+			}
+			r[i].weight = o.Weights[i]
+		}
 	}
 	return r, nil
 }
@@ -55,7 +119,63 @@ func (g *Goal) String() string {
 	return g.expr.String()
 }
 
+// Weight returns the weight of the goal
+func (g *Goal) Weight() float64 {
+	return g.weight
+}
+
+// Mode returns how the goal is combined with other goals to give a
+// goals score
+func (g *Goal) Mode() Mode {
+	return g.mode
+}
+
 func (g *Goal) Assess(aggregators map[string]*dlit.Literal) (bool, error) {
 	passed, err := g.expr.EvalBool(aggregators)
 	return passed, err
 }
+
+// Score assesses the goals against the aggregators and combines the
+// results using the Mode of the goals to give a goals score.  The goals
+// must all have the same Mode, otherwise ErrMixedModes is returned.
+func Score(
+	goals []*Goal,
+	aggregators map[string]*dlit.Literal,
+) (float64, error) {
+	if len(goals) == 0 {
+		return 0, nil
+	}
+	mode := goals[0].mode
+	for _, g := range goals[1:] {
+		if g.mode != mode {
+			return 0, ErrMixedModes
+		}
+	}
+	score := 0.0
+	increment := 1.0
+	allPassed := true
+	for _, g := range goals {
+		passed, err := g.Assess(aggregators)
+		if err != nil {
+			return 0, err
+		}
+		switch mode {
+		case Strict:
+			if passed {
+				score += increment * g.weight
+			} else {
+				increment = 0.001
+			}
+		case WeightedSum, AllOrNothing:
+			if passed {
+				score += g.weight
+			} else {
+				allPassed = false
+			}
+		}
+	}
+	if mode == AllOrNothing && !allPassed {
+		return 0, nil
+	}
+	return score, nil
+}
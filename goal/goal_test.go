@@ -171,3 +171,130 @@ func TestMakeGoals_errors(t *testing.T) {
 		t.Fatalf("MakeGoals err: %s, wantErr: %s", err, wantErr)
 	}
 }
+
+func TestMakeGoals_options(t *testing.T) {
+	exprs := []string{"profit > 27", "cost <= 37"}
+	cases := []struct {
+		opts        []Options
+		wantWeights []float64
+		wantMode    Mode
+	}{
+		{opts: []Options{},
+			wantWeights: []float64{1, 1},
+			wantMode:    Strict,
+		},
+		{opts: []Options{{Mode: WeightedSum}},
+			wantWeights: []float64{1, 1},
+			wantMode:    WeightedSum,
+		},
+		{opts: []Options{{Mode: AllOrNothing, Weights: []float64{2, 0.5}}},
+			wantWeights: []float64{2, 0.5},
+			wantMode:    AllOrNothing,
+		},
+	}
+	for i, c := range cases {
+		got, err := MakeGoals(exprs, c.opts...)
+		if err != nil {
+			t.Fatalf("(%d) MakeGoals: %s", i, err)
+		}
+		for j, g := range got {
+			if g.Weight() != c.wantWeights[j] {
+				t.Errorf("(%d) Weight() got: %f, want: %f",
+					i, g.Weight(), c.wantWeights[j])
+			}
+			if g.Mode() != c.wantMode {
+				t.Errorf("(%d) Mode() got: %d, want: %d", i, g.Mode(), c.wantMode)
+			}
+		}
+	}
+}
+
+func TestMakeGoals_options_errors(t *testing.T) {
+	exprs := []string{"profit > 27", "cost <= 37"}
+	cases := []struct {
+		opts    Options
+		wantErr error
+	}{
+		{opts: Options{Weights: []float64{1}}, wantErr: ErrNumWeights},
+		{opts: Options{Weights: []float64{1, -2}}, wantErr: ErrNegativeWeight},
+		{opts: Options{Mode: Mode(7)}, wantErr: ErrInvalidMode},
+	}
+	for i, c := range cases {
+		_, err := MakeGoals(exprs, c.opts)
+		if err != c.wantErr {
+			t.Errorf("(%d) MakeGoals err: %v, wantErr: %v", i, err, c.wantErr)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	aggregators := map[string]*dlit.Literal{
+		"profit": dlit.MustNew(30),
+		"cost":   dlit.MustNew(40),
+		"income": dlit.MustNew(70),
+	}
+	exprs := []string{"profit > 27", "cost <= 37", "income > 50"}
+	cases := []struct {
+		opts Options
+		want float64
+	}{
+		{opts: Options{}, want: 1.001},
+		{opts: Options{Weights: []float64{2, 1, 3}}, want: 2.003},
+		{opts: Options{Mode: WeightedSum}, want: 2},
+		{opts: Options{Mode: WeightedSum, Weights: []float64{2, 1, 0.5}},
+			want: 2.5,
+		},
+		{opts: Options{Mode: AllOrNothing}, want: 0},
+	}
+	for i, c := range cases {
+		goals, err := MakeGoals(exprs, c.opts)
+		if err != nil {
+			t.Fatalf("(%d) MakeGoals: %s", i, err)
+		}
+		got, err := Score(goals, aggregators)
+		if err != nil {
+			t.Fatalf("(%d) Score: %s", i, err)
+		}
+		if got != c.want {
+			t.Errorf("(%d) Score got: %f, want: %f", i, got, c.want)
+		}
+	}
+
+	goals, err := MakeGoals(
+		[]string{"profit > 27", "income > 50"},
+		Options{Mode: AllOrNothing, Weights: []float64{2, 0.5}},
+	)
+	if err != nil {
+		t.Fatalf("MakeGoals: %s", err)
+	}
+	got, err := Score(goals, aggregators)
+	if err != nil {
+		t.Fatalf("Score: %s", err)
+	}
+	if got != 2.5 {
+		t.Errorf("Score got: %f, want: 2.5", got)
+	}
+}
+
+func TestMakeGoals_numOptions(t *testing.T) {
+	_, err := MakeGoals([]string{"profit > 27"}, Options{}, Options{})
+	if err != ErrNumOptions {
+		t.Errorf("MakeGoals err: %v, wantErr: %v", err, ErrNumOptions)
+	}
+}
+
+func TestScore_mixedModes(t *testing.T) {
+	aggregators := map[string]*dlit.Literal{"profit": dlit.MustNew(30)}
+	weighted, err := MakeGoals(
+		[]string{"profit > 27"},
+		Options{Mode: WeightedSum},
+	)
+	if err != nil {
+		t.Fatalf("MakeGoals: %s", err)
+	}
+	goals := []*Goal{MustNew("profit > 20"), weighted[0]}
+	_, err = Score(goals, aggregators)
+	if err != ErrMixedModes {
+		t.Errorf("Score err: %v, wantErr: %v", err, ErrMixedModes)
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+// Package bitset implements a compressed bitset used to record which
+// records a rule matched.  The bitset is split into containers of 2^16
+// bits, each of which is stored as a sorted array of values when it is
+// sparse and as a bitmap when it is dense.
+package bitset
+
+import "sort"
+
+const (
+	// containerBits is the number of low bits of a value held in a container
+	containerBits = 16
+	// maxArraySize is the maximum number of values held in an array
+	// container before it is converted to a bitmap container
+	maxArraySize = 4096
+	bitmapWords  = (1 << containerBits) / 64
+)
+
+// Bitset is a compressed set of non-negative integers
+type Bitset struct {
+	keys       []uint64
+	containers []*container
+}
+
+type container struct {
+	// array is a sorted list of values, used when bitmap is nil
+	array  []uint16
+	bitmap []uint64
+	n      int
+}
+
+// New returns an empty Bitset
+func New() *Bitset {
+	return &Bitset{
+		keys:       []uint64{},
+		containers: []*container{},
+	}
+}
+
+// Add adds the value i to the bitset.  Adding values in ascending order
+// is the fastest way to build a bitset.
+func (b *Bitset) Add(i uint64) {
+	key, low := i>>containerBits, uint16(i)
+	numKeys := len(b.keys)
+	if numKeys > 0 && b.keys[numKeys-1] == key {
+		b.containers[numKeys-1].add(low)
+		return
+	}
+	pos := sort.Search(numKeys, func(j int) bool { return b.keys[j] >= key })
+	if pos < numKeys && b.keys[pos] == key {
+		b.containers[pos].add(low)
+		return
+	}
+	c := &container{array: []uint16{}}
+	c.add(low)
+	b.keys = append(b.keys, 0)
+	b.containers = append(b.containers, nil)
+	copy(b.keys[pos+1:], b.keys[pos:])
+	copy(b.containers[pos+1:], b.containers[pos:])
+	b.keys[pos] = key
+	b.containers[pos] = c
+}
+
+// Contains returns whether the value i is in the bitset
+func (b *Bitset) Contains(i uint64) bool {
+	key, low := i>>containerBits, uint16(i)
+	pos := sort.Search(len(b.keys), func(j int) bool { return b.keys[j] >= key })
+	if pos < len(b.keys) && b.keys[pos] == key {
+		return b.containers[pos].contains(low)
+	}
+	return false
+}
+
+// Count returns the number of values in the bitset
+func (b *Bitset) Count() int64 {
+	n := int64(0)
+	for _, c := range b.containers {
+		n += int64(c.n)
+	}
+	return n
+}
+
+// And returns a new bitset of the values that are in both bitsets
+func (b *Bitset) And(o *Bitset) *Bitset {
+	r := New()
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(o.keys) {
+		switch {
+		case b.keys[i] < o.keys[j]:
+			i++
+		case b.keys[i] > o.keys[j]:
+			j++
+		default:
+			if c := b.containers[i].and(o.containers[j]); c.n > 0 {
+				r.keys = append(r.keys, b.keys[i])
+				r.containers = append(r.containers, c)
+			}
+			i++
+			j++
+		}
+	}
+	return r
+}
+
+// Or returns a new bitset of the values that are in either bitset
+func (b *Bitset) Or(o *Bitset) *Bitset {
+	r := New()
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(o.keys) {
+		switch {
+		case j >= len(o.keys) || (i < len(b.keys) && b.keys[i] < o.keys[j]):
+			r.keys = append(r.keys, b.keys[i])
+			r.containers = append(r.containers, b.containers[i].clone())
+			i++
+		case i >= len(b.keys) || b.keys[i] > o.keys[j]:
+			r.keys = append(r.keys, o.keys[j])
+			r.containers = append(r.containers, o.containers[j].clone())
+			j++
+		default:
+			r.keys = append(r.keys, b.keys[i])
+			r.containers = append(r.containers, b.containers[i].or(o.containers[j]))
+			i++
+			j++
+		}
+	}
+	return r
+}
+
+// Equal returns whether both bitsets contain the same values
+func (b *Bitset) Equal(o *Bitset) bool {
+	if len(b.keys) != len(o.keys) {
+		return false
+	}
+	for i, k := range b.keys {
+		if k != o.keys[i] || !b.containers[i].equal(o.containers[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *container) add(v uint16) {
+	if c.bitmap != nil {
+		word, bit := v/64, uint64(1)<<(v%64)
+		if c.bitmap[word]&bit == 0 {
+			c.bitmap[word] |= bit
+			c.n++
+		}
+		return
+	}
+	if c.n > 0 && c.array[c.n-1] < v {
+		c.array = append(c.array, v)
+	} else {
+		pos := sort.Search(c.n, func(j int) bool { return c.array[j] >= v })
+		if pos < c.n && c.array[pos] == v {
+			return
+		}
+		c.array = append(c.array, 0)
+		copy(c.array[pos+1:], c.array[pos:])
+		c.array[pos] = v
+	}
+	c.n++
+	if c.n > maxArraySize {
+		c.toBitmap()
+	}
+}
+
+func (c *container) contains(v uint16) bool {
+	if c.bitmap != nil {
+		return c.bitmap[v/64]&(uint64(1)<<(v%64)) != 0
+	}
+	pos := sort.Search(c.n, func(j int) bool { return c.array[j] >= v })
+	return pos < c.n && c.array[pos] == v
+}
+
+func (c *container) and(o *container) *container {
+	if c.bitmap == nil || o.bitmap == nil {
+		small, large := c, o
+		if small.bitmap != nil {
+			small, large = o, c
+		}
+		r := &container{array: []uint16{}}
+		for _, v := range small.array {
+			if large.contains(v) {
+				r.array = append(r.array, v)
+			}
+		}
+		r.n = len(r.array)
+		return r
+	}
+	r := &container{bitmap: make([]uint64, bitmapWords)}
+	for i, w := range c.bitmap {
+		r.bitmap[i] = w & o.bitmap[i]
+		r.n += popcount(r.bitmap[i])
+	}
+	if r.n <= maxArraySize {
+		r.toArray()
+	}
+	return r
+}
+
+func (c *container) or(o *container) *container {
+	if c.bitmap == nil && o.bitmap == nil && c.n+o.n <= maxArraySize {
+		r := &container{array: make([]uint16, 0, c.n+o.n)}
+		i, j := 0, 0
+		for i < c.n || j < o.n {
+			switch {
+			case j >= o.n || (i < c.n && c.array[i] < o.array[j]):
+				r.array = append(r.array, c.array[i])
+				i++
+			case i >= c.n || c.array[i] > o.array[j]:
+				r.array = append(r.array, o.array[j])
+				j++
+			default:
+				r.array = append(r.array, c.array[i])
+				i++
+				j++
+			}
+		}
+		r.n = len(r.array)
+		return r
+	}
+	r := &container{bitmap: make([]uint64, bitmapWords)}
+	c.orInto(r.bitmap)
+	o.orInto(r.bitmap)
+	for _, w := range r.bitmap {
+		r.n += popcount(w)
+	}
+	if r.n <= maxArraySize {
+		r.toArray()
+	}
+	return r
+}
+
+func (c *container) orInto(bitmap []uint64) {
+	if c.bitmap != nil {
+		for i, w := range c.bitmap {
+			bitmap[i] |= w
+		}
+		return
+	}
+	for _, v := range c.array {
+		bitmap[v/64] |= uint64(1) << (v % 64)
+	}
+}
+
+func (c *container) equal(o *container) bool {
+	if c.n != o.n {
+		return false
+	}
+	if c.bitmap != nil && o.bitmap != nil {
+		for i, w := range c.bitmap {
+			if w != o.bitmap[i] {
+				return false
+			}
+		}
+		return true
+	}
+	small, large := c, o
+	if small.bitmap != nil {
+		small, large = o, c
+	}
+	for _, v := range small.array {
+		if !large.contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *container) clone() *container {
+	r := &container{n: c.n}
+	if c.bitmap != nil {
+		r.bitmap = make([]uint64, bitmapWords)
+		copy(r.bitmap, c.bitmap)
+	} else {
+		r.array = make([]uint16, c.n)
+		copy(r.array, c.array)
+	}
+	return r
+}
+
+func (c *container) toBitmap() {
+	c.bitmap = make([]uint64, bitmapWords)
+	for _, v := range c.array {
+		c.bitmap[v/64] |= uint64(1) << (v % 64)
+	}
+	c.array = nil
+}
+
+func (c *container) toArray() {
+	c.array = make([]uint16, 0, c.n)
+	for i, w := range c.bitmap {
+		for w != 0 {
+			t := w & -w
+			c.array = append(c.array, uint16(i*64+popcount(t-1)))
+			w ^= t
+		}
+	}
+	c.bitmap = nil
+}
+
+// popcount returns the number of bits set in x
+func popcount(x uint64) int {
+	x -= (x >> 1) & 0x5555555555555555
+	x = (x & 0x3333333333333333) + ((x >> 2) & 0x3333333333333333)
+	x = (x + (x >> 4)) & 0x0f0f0f0f0f0f0f0f
+	return int((x * 0x0101010101010101) >> 56)
+}
@@ -0,0 +1,107 @@
+package bitset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAddContains(t *testing.T) {
+	values := []uint64{0, 1, 5, 63, 64, 65535, 65536, 70000, 1 << 33}
+	b := New()
+	// Add in reverse order to test inserting
+	for i := len(values) - 1; i >= 0; i-- {
+		b.Add(values[i])
+	}
+	// Adding a value twice shouldn't change the bitset
+	b.Add(5)
+	for _, v := range values {
+		if !b.Contains(v) {
+			t.Errorf("Contains(%d) got: false, want: true", v)
+		}
+	}
+	for _, v := range []uint64{2, 62, 66, 65534, 65537, 1<<33 + 1} {
+		if b.Contains(v) {
+			t.Errorf("Contains(%d) got: true, want: false", v)
+		}
+	}
+	if got := b.Count(); got != int64(len(values)) {
+		t.Errorf("Count() got: %d, want: %d", got, len(values))
+	}
+}
+
+func TestAndOrEqual(t *testing.T) {
+	cases := []struct {
+		numValues int
+		max       uint64
+	}{
+		{numValues: 20, max: 100},
+		{numValues: 1000, max: 200000},
+		{numValues: 20000, max: 70000},
+		{numValues: 100000, max: 300000},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i, c := range cases {
+		a, b := New(), New()
+		mA, mB := map[uint64]bool{}, map[uint64]bool{}
+		for j := 0; j < c.numValues; j++ {
+			vA := uint64(rnd.Int63n(int64(c.max)))
+			vB := uint64(rnd.Int63n(int64(c.max)))
+			a.Add(vA)
+			b.Add(vB)
+			mA[vA] = true
+			mB[vB] = true
+		}
+		and := a.And(b)
+		or := a.Or(b)
+		wantAnd, wantOr := int64(0), int64(0)
+		for v := uint64(0); v < c.max; v++ {
+			if mA[v] && mB[v] {
+				wantAnd++
+			}
+			if mA[v] || mB[v] {
+				wantOr++
+			}
+			if and.Contains(v) != (mA[v] && mB[v]) {
+				t.Fatalf("(%d) And().Contains(%d) got: %t", i, v, and.Contains(v))
+			}
+			if or.Contains(v) != (mA[v] || mB[v]) {
+				t.Fatalf("(%d) Or().Contains(%d) got: %t", i, v, or.Contains(v))
+			}
+		}
+		if and.Count() != wantAnd {
+			t.Errorf("(%d) And().Count() got: %d, want: %d", i, and.Count(), wantAnd)
+		}
+		if or.Count() != wantOr {
+			t.Errorf("(%d) Or().Count() got: %d, want: %d", i, or.Count(), wantOr)
+		}
+		if !a.Equal(a.Or(a)) || !a.Equal(a.And(a)) {
+			t.Errorf("(%d) Equal() got: false, want: true", i)
+		}
+		if a.Equal(b) || and.Equal(or) {
+			t.Errorf("(%d) Equal() got: true, want: false", i)
+		}
+		// Or shouldn't share containers with its inputs
+		or.Add(c.max + 1)
+		if a.Contains(c.max+1) || b.Contains(c.max+1) {
+			t.Errorf("(%d) Or() result shares containers with inputs", i)
+		}
+	}
+}
+
+func TestPopcount(t *testing.T) {
+	cases := []struct {
+		in   uint64
+		want int
+	}{
+		{0, 0},
+		{1, 1},
+		{0xff, 8},
+		{0x8000000000000001, 2},
+		{0xffffffffffffffff, 64},
+	}
+	for _, c := range cases {
+		if got := popcount(c.in); got != c.want {
+			t.Errorf("popcount(%x) got: %d, want: %d", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+// Package colcache implements a Dataset that caches another Dataset in
+// memory as columns, so that repeated passes over it don't need to read
+// and convert the underlying Dataset again.  The cache is built the first
+// time the Dataset is read from start to finish.  If the cache would use
+// more memory than allowed then the Dataset is streamed from the
+// underlying Dataset instead.
+package colcache
+
+import (
+	"sync"
+
+	"github.com/lawrencewoodman/ddataset"
+	"github.com/lawrencewoodman/dlit"
+)
+
+// cacheState describes the state of a Dataset's cache
+type cacheState int
+
+const (
+	notCached cacheState = iota
+	filling
+	cached
+	// uncacheable indicates that the Dataset can't be cached, such as
+	// when the cache would exceed the memory ceiling
+	uncacheable
+)
+
+// Approximate sizes in bytes used to estimate the memory used by the cache
+const (
+	indexSize   = 4
+	literalSize = 96
+	mapKeySize  = 48
+)
+
+// Dataset caches another Dataset in memory
+type Dataset struct {
+	dataset  ddataset.Dataset
+	maxBytes int64
+	fields   []string
+	state    cacheState
+	columns  []*column
+	numRows  int
+	mux      sync.Mutex
+}
+
+// column holds the values of a field.  Each distinct value is only
+// held once, as a literal that has already been converted to its
+// number types, and rows refer to it by index.
+type column struct {
+	values  []*dlit.Literal
+	indices map[string]uint32
+	rows    []uint32
+}
+
+type cacheConn struct {
+	dataset  *Dataset
+	columns  []*column
+	numRows  int
+	position int
+	isClosed bool
+}
+
+type fillConn struct {
+	dataset  *Dataset
+	conn     ddataset.Conn
+	record   ddataset.Record
+	columns  []*column
+	numRows  int
+	numBytes int64
+	isDone   bool
+	isClosed bool
+}
+
+// New returns a Dataset that caches dataset in memory using no more
+// than roughly maxBytes
+func New(dataset ddataset.Dataset, maxBytes int64) *Dataset {
+	return &Dataset{
+		dataset:  dataset,
+		maxBytes: maxBytes,
+		fields:   dataset.Fields(),
+		state:    notCached,
+	}
+}
+
+// Open creates a connection to the Dataset.  If the Dataset has been
+// cached then the connection reads from the cache, otherwise it reads
+// from the underlying Dataset and, if no other connection is doing so,
+// fills the cache.
+func (d *Dataset) Open() (ddataset.Conn, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.state == cached {
+		return &cacheConn{
+			dataset:  d,
+			columns:  d.columns,
+			numRows:  d.numRows,
+			position: -1,
+		}, nil
+	}
+	conn, err := d.dataset.Open()
+	if err != nil {
+		return nil, err
+	}
+	if d.state != notCached {
+		return conn, nil
+	}
+	d.state = filling
+	return newFillConn(d, conn), nil
+}
+
+// Fields returns the field names used by the Dataset
+func (d *Dataset) Fields() []string {
+	return d.fields
+}
+
+// NumRecords returns the number of records in the Dataset
+func (d *Dataset) NumRecords() int64 {
+	d.mux.Lock()
+	if d.state == cached {
+		defer d.mux.Unlock()
+		return int64(d.numRows)
+	}
+	d.mux.Unlock()
+	return d.dataset.NumRecords()
+}
+
+// Release releases the cache and the underlying Dataset
+func (d *Dataset) Release() error {
+	d.mux.Lock()
+	d.columns = nil
+	d.numRows = 0
+	d.state = uncacheable
+	d.mux.Unlock()
+	return d.dataset.Release()
+}
+
+// IsCached returns whether the Dataset has been cached in memory
+func (d *Dataset) IsCached() bool {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.state == cached
+}
+
+// fillDone is called by a fillConn once it has finished
+func (d *Dataset) fillDone(columns []*column, numRows int, state cacheState) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.state != filling {
+		return
+	}
+	d.state = state
+	if state == cached {
+		for _, c := range columns {
+			c.indices = nil
+		}
+		d.columns = columns
+		d.numRows = numRows
+	}
+}
+
+func (cc *cacheConn) Next() bool {
+	if cc.isClosed || cc.position+1 >= cc.numRows {
+		return false
+	}
+	cc.position++
+	return true
+}
+
+func (cc *cacheConn) Read() ddataset.Record {
+	record := make(ddataset.Record, len(cc.columns))
+	for i, c := range cc.columns {
+		record[cc.dataset.fields[i]] = c.values[c.rows[cc.position]]
+	}
+	return record
+}
+
+func (cc *cacheConn) Err() error {
+	if cc.isClosed {
+		return ddataset.ErrConnClosed
+	}
+	return nil
+}
+
+func (cc *cacheConn) Close() error {
+	cc.isClosed = true
+	return nil
+}
+
+func newFillConn(d *Dataset, conn ddataset.Conn) *fillConn {
+	columns := make([]*column, len(d.fields))
+	for i := range columns {
+		columns[i] = &column{
+			values:  []*dlit.Literal{},
+			indices: map[string]uint32{},
+			rows:    []uint32{},
+		}
+	}
+	return &fillConn{
+		dataset: d,
+		conn:    conn,
+		columns: columns,
+	}
+}
+
+func (fc *fillConn) Next() bool {
+	if fc.isClosed {
+		return false
+	}
+	if !fc.conn.Next() {
+		if fc.conn.Err() == nil && !fc.isDone {
+			fc.dataset.fillDone(fc.columns, fc.numRows, cached)
+		} else {
+			fc.abandon(notCached)
+		}
+		fc.isDone = true
+		return false
+	}
+	fc.record = fc.conn.Read()
+	if !fc.isDone {
+		fc.add(fc.record)
+	}
+	return true
+}
+
+func (fc *fillConn) Read() ddataset.Record {
+	return fc.record
+}
+
+func (fc *fillConn) Err() error {
+	return fc.conn.Err()
+}
+
+func (fc *fillConn) Close() error {
+	if !fc.isClosed && !fc.isDone {
+		// The Dataset wasn't read to the end
+		fc.abandon(notCached)
+	}
+	fc.isClosed = true
+	return fc.conn.Close()
+}
+
+// add adds a record to the cache being built unless this would take
+// the cache above its memory ceiling
+func (fc *fillConn) add(record ddataset.Record) {
+	for i, field := range fc.dataset.fields {
+		c := fc.columns[i]
+		l, ok := record[field]
+		if !ok {
+			// Records with missing fields can't be held in the cache
+			fc.abandon(uncacheable)
+			return
+		}
+		key := literalKey(l)
+		index, ok := c.indices[key]
+		if !ok {
+			// Convert the value now so that this is done only once and
+			// the literal isn't changed when it is shared between records
+			l.Int()
+			l.Float()
+			l.Bool()
+			index = uint32(len(c.values))
+			c.values = append(c.values, l)
+			c.indices[key] = index
+			fc.numBytes += literalSize + mapKeySize + 2*int64(len(key))
+		}
+		c.rows = append(c.rows, index)
+		fc.numBytes += indexSize
+	}
+	fc.numRows++
+	if fc.numBytes > fc.dataset.maxBytes {
+		fc.abandon(uncacheable)
+	}
+}
+
+// abandon stops building the cache
+func (fc *fillConn) abandon(state cacheState) {
+	if fc.isDone {
+		return
+	}
+	fc.isDone = true
+	fc.columns = nil
+	fc.dataset.fillDone(nil, 0, state)
+}
+
+// literalKey returns a key to identify distinct values of a field
+func literalKey(l *dlit.Literal) string {
+	if err := l.Err(); err != nil {
+		return "\x00" + err.Error()
+	}
+	return l.String()
+}
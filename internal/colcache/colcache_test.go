@@ -0,0 +1,211 @@
+package colcache
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lawrencewoodman/ddataset"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+var fields = []string{"band", "team", "flow"}
+var records = [][]string{
+	{"4", "a", "2.5"},
+	{"7", "b", "1.2"},
+	{"4", "a", "0.5"},
+	{"9", "c", "2.5"},
+	{"2", "b", "3.1"},
+}
+
+func TestOpen(t *testing.T) {
+	cases := []struct {
+		maxBytes   int64
+		wantCached bool
+	}{
+		{maxBytes: 0, wantCached: false},
+		{maxBytes: 100, wantCached: false},
+		{maxBytes: 10000, wantCached: true},
+	}
+	for i, c := range cases {
+		counting := &countingDataset{
+			Dataset: testhelpers.NewLiteralDataset(fields, records),
+		}
+		d := New(counting, c.maxBytes)
+		for pass := 0; pass < 3; pass++ {
+			got, err := readAll(d)
+			if err != nil {
+				t.Fatalf("(%d) readAll: %s", i, err)
+			}
+			checkRecords(t, i, got)
+		}
+		if d.IsCached() != c.wantCached {
+			t.Errorf("(%d) IsCached got: %t, want: %t",
+				i, d.IsCached(), c.wantCached)
+		}
+		wantNumOpens := 3
+		if c.wantCached {
+			wantNumOpens = 1
+		}
+		if counting.numOpens != wantNumOpens {
+			t.Errorf("(%d) underlying dataset opened: %d times, want: %d",
+				i, counting.numOpens, wantNumOpens)
+		}
+		if n := d.NumRecords(); n != int64(len(records)) {
+			t.Errorf("(%d) NumRecords got: %d, want: %d", i, n, len(records))
+		}
+	}
+}
+
+func TestOpen_partialRead(t *testing.T) {
+	d := New(testhelpers.NewLiteralDataset(fields, records), 10000)
+	conn, err := d.Open()
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	conn.Next()
+	conn.Next()
+	conn.Close()
+	if d.IsCached() {
+		t.Fatalf("IsCached got: true, want: false")
+	}
+	got, err := readAll(d)
+	if err != nil {
+		t.Fatalf("readAll: %s", err)
+	}
+	checkRecords(t, 0, got)
+	if !d.IsCached() {
+		t.Errorf("IsCached got: false, want: true")
+	}
+}
+
+func TestOpen_concurrent(t *testing.T) {
+	d := New(testhelpers.NewLiteralDataset(fields, records), 10000)
+	connA, err := d.Open()
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	// connB shouldn't try to fill the cache as connA is doing so
+	connB, err := d.Open()
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	numRecordsB := 0
+	for connB.Next() {
+		numRecordsB++
+	}
+	connB.Close()
+	if d.IsCached() {
+		t.Fatalf("IsCached got: true, want: false")
+	}
+	for connA.Next() {
+	}
+	connA.Close()
+	if !d.IsCached() {
+		t.Errorf("IsCached got: false, want: true")
+	}
+	if numRecordsB != len(records) {
+		t.Errorf("numRecordsB got: %d, want: %d", numRecordsB, len(records))
+	}
+}
+
+func TestConnErr(t *testing.T) {
+	wantErr := errors.New("can't read record")
+	d := New(&failingDataset{
+		Dataset: testhelpers.NewLiteralDataset(fields, records),
+		err:     wantErr,
+	}, 10000)
+	_, err := readAll(d)
+	if err != wantErr {
+		t.Errorf("readAll got err: %v, want: %v", err, wantErr)
+	}
+	if d.IsCached() {
+		t.Errorf("IsCached got: true, want: false")
+	}
+}
+
+func TestRelease(t *testing.T) {
+	d := New(testhelpers.NewLiteralDataset(fields, records), 10000)
+	if _, err := readAll(d); err != nil {
+		t.Fatalf("readAll: %s", err)
+	}
+	if !d.IsCached() {
+		t.Fatalf("IsCached got: false, want: true")
+	}
+	if err := d.Release(); err != nil {
+		t.Fatalf("Release: %s", err)
+	}
+	if d.IsCached() {
+		t.Errorf("IsCached got: true, want: false")
+	}
+}
+
+func readAll(d ddataset.Dataset) ([]ddataset.Record, error) {
+	conn, err := d.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	records := []ddataset.Record{}
+	for conn.Next() {
+		records = append(records, conn.Read())
+	}
+	return records, conn.Err()
+}
+
+func checkRecords(t *testing.T, i int, got []ddataset.Record) {
+	if len(got) != len(records) {
+		t.Fatalf("(%d) got: %d records, want: %d", i, len(got), len(records))
+	}
+	for j, record := range records {
+		want := map[string]string{}
+		for k, f := range fields {
+			want[f] = record[k]
+		}
+		gotStrs := map[string]string{}
+		for f, l := range got[j] {
+			gotStrs[f] = l.String()
+		}
+		if !reflect.DeepEqual(gotStrs, want) {
+			t.Errorf("(%d) record: %d, got: %v, want: %v", i, j, gotStrs, want)
+		}
+	}
+}
+
+type countingDataset struct {
+	ddataset.Dataset
+	numOpens int
+}
+
+func (d *countingDataset) Open() (ddataset.Conn, error) {
+	d.numOpens++
+	return d.Dataset.Open()
+}
+
+type failingDataset struct {
+	ddataset.Dataset
+	err error
+}
+
+type failingConn struct {
+	ddataset.Conn
+	err error
+	n   int
+}
+
+func (d *failingDataset) Open() (ddataset.Conn, error) {
+	conn, err := d.Dataset.Open()
+	return &failingConn{Conn: conn, err: d.err}, err
+}
+
+func (c *failingConn) Next() bool {
+	c.n++
+	return c.n < 3 && c.Conn.Next()
+}
+
+func (c *failingConn) Err() error {
+	if c.n >= 3 {
+		return c.err
+	}
+	return nil
+}
@@ -4,6 +4,8 @@
 package internal
 
 import (
+	"math"
+
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/lawrencewoodman/dlit"
 	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
@@ -61,6 +63,12 @@ func RoundLit(l *dlit.Literal, dp int) *dlit.Literal {
 	return roundExpr.Eval(vars)
 }
 
+// RoundFloat rounds f to dp decimal places
+func RoundFloat(f float64, dp int) float64 {
+	p := math.Pow(10, float64(dp))
+	return math.Floor(f*p+0.5) / p
+}
+
 func MapLitNumsToSlice(nums map[string]*dlit.Literal) []*dlit.Literal {
 	r := make([]*dlit.Literal, len(nums))
 	i := 0
@@ -146,3 +146,23 @@ func checkPoints(got, want []*dlit.Literal) error {
 	}
 	return nil
 }
+
+func TestRoundFloat(t *testing.T) {
+	cases := []struct {
+		f    float64
+		dp   int
+		want float64
+	}{
+		{f: 0, dp: 2, want: 0},
+		{f: 1.2345, dp: 2, want: 1.23},
+		{f: 1.235, dp: 2, want: 1.24},
+		{f: 2.5, dp: 0, want: 3},
+		{f: 0.66666, dp: 4, want: 0.6667},
+	}
+	for _, c := range cases {
+		got := RoundFloat(c.f, c.dp)
+		if got != c.want {
+			t.Errorf("RoundFloat(%f, %d) got: %f, want: %f", c.f, c.dp, got, c.want)
+		}
+	}
+}
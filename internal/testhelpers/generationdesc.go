@@ -4,9 +4,14 @@
 package testhelpers
 
 type GenerationDesc struct {
-	DFields     []string
-	DArithmetic bool
-	DDeny       map[string][]string
+	DFields            []string
+	DArithmetic        bool
+	DAllow             map[string][]string
+	DDeny              map[string][]string
+	DDisabled          []string
+	DMaxGeneratorRules int
+	DMaxFieldRules     int
+	DMinSupport        int64
 }
 
 func (gd GenerationDesc) Fields() []string {
@@ -17,8 +22,51 @@ func (gd GenerationDesc) Arithmetic() bool {
 	return gd.DArithmetic
 }
 
+func (gd GenerationDesc) Allow(generatorName string, field string) bool {
+	if gd.DAllow == nil {
+		return true
+	}
+	for _, fields := range gd.DAllow {
+		for _, f := range fields {
+			if f == field {
+				return isFieldInMap(gd.DAllow, generatorName, field)
+			}
+		}
+	}
+	return true
+}
+
 func (gd GenerationDesc) Deny(generatorName string, field string) bool {
-	fields := gd.DDeny[generatorName]
+	return isFieldInMap(gd.DDeny, generatorName, field)
+}
+
+func (gd GenerationDesc) Disabled(generatorName string) bool {
+	for _, g := range gd.DDisabled {
+		if g == generatorName {
+			return true
+		}
+	}
+	return false
+}
+
+func (gd GenerationDesc) MaxGeneratorRules() int {
+	return gd.DMaxGeneratorRules
+}
+
+func (gd GenerationDesc) MaxFieldRules() int {
+	return gd.DMaxFieldRules
+}
+
+func (gd GenerationDesc) MinSupport() int64 {
+	return gd.DMinSupport
+}
+
+func isFieldInMap(
+	m map[string][]string,
+	generatorName string,
+	field string,
+) bool {
+	fields := m[generatorName]
 	for _, f := range fields {
 		if f == field {
 			return true
@@ -12,6 +12,7 @@ import (
 	"github.com/vlifesystems/rhkit/assessment"
 	"github.com/vlifesystems/rhkit/description"
 	"github.com/vlifesystems/rhkit/goal"
+	"github.com/vlifesystems/rhkit/internal/colcache"
 	"github.com/vlifesystems/rhkit/rule"
 )
 
@@ -49,7 +50,63 @@ type Options struct {
 	MaxNumRules             int
 	RuleFields              []string
 	GenerateArithmeticRules bool
-	DenyGeneratorFields     map[string][]string
+	// AllowGeneratorFields puts rule generation into allow-list mode if
+	// supplied, so that a field that is listed may only be used by the
+	// generators it is listed for.  Fields that aren't listed may be used
+	// by any generator.  See rule.GeneratorFields for the keys and patterns
+	// that may be used.
+	AllowGeneratorFields map[string][]string
+	// DenyGeneratorFields lists the fields that generators may not use.
+	// See rule.GeneratorFields for the keys and patterns that may be used.
+	DenyGeneratorFields map[string][]string
+	// DisabledGenerators are the names, groups or wildcard patterns of the
+	// rule generators that shouldn't be used to generate rules
+	DisabledGenerators []string
+	// MaxRulesPerGenerator is the maximum number of rules that each rule
+	// generator may generate, 0 means no limit
+	MaxRulesPerGenerator int
+	// MaxRulesPerField is the maximum number of rules that each rule
+	// generator may generate for a field, 0 means no limit
+	MaxRulesPerField int
+	// MinRuleSupport is the minimum number of records that a generated rule
+	// must be able to match, 0 means no limit
+	MinRuleSupport int64
+	// CorrelateFields indicates whether to make an extra pass over the
+	// Dataset to find correlated fields, so that rules aren't generated
+	// between fields that are near duplicates of each other
+	CorrelateFields bool
+	// BooleanTokens, if supplied, are the values used to identify Boolean
+	// fields, such as description.DefaultBooleanTokens.  If these aren't
+	// supplied then fields aren't described as Boolean.
+	BooleanTokens *description.BooleanTokens
+	// KeepRuleCoverage indicates whether to keep a compressed record of
+	// which records each rule matched.  This uses more memory but speeds
+	// up the assessment of combined rules.
+	KeepRuleCoverage bool
+	// MaxDatasetCacheBytes is the maximum amount of memory in bytes that
+	// may be used to cache the Dataset, so that it doesn't have to be
+	// read again for each pass.  If the Dataset won't fit then it is read
+	// from the Dataset for each pass.  0 means the Dataset isn't cached.
+	MaxDatasetCacheBytes int64
+	// EarlyStopRules indicates whether rules that provably can't reach the
+	// MinPercentMatches of RefineOptions should be dropped part way
+	// through a pass of the Dataset.  On the final pass, rules that
+	// provably can't be among the best rules found so far are also
+	// dropped.  This only has an effect if the first sort order aggregator
+	// is monotone, such as count, numMatches or precision.
+	EarlyStopRules bool
+	// RefineOptions control which rules are excluded each time that the
+	// rules are refined, if nil then assessment.DefaultRefineOptions is used
+	RefineOptions *assessment.RefineOptions
+	// DiversityOptions, if supplied, select the final rules so that they
+	// differ from each other as well as being the best rules, such as
+	// those returned by assessment.DefaultDiversityOptions
+	DiversityOptions *assessment.DiversityOptions
+	// ParetoSort indicates whether to rank rules by Pareto front using the
+	// sort order as the objectives, rather than sorting them by each
+	// aggregator in turn.  EarlyStopRules then only drops rules that
+	// can't reach the MinPercentMatches of RefineOptions.
+	ParetoSort bool
 }
 
 func (o Options) Fields() []string {
@@ -60,16 +117,40 @@ func (o Options) Arithmetic() bool {
 	return o.GenerateArithmeticRules
 }
 
+func (o Options) Allow(generatorName string, field string) bool {
+	if o.AllowGeneratorFields == nil {
+		return true
+	}
+	allow := rule.GeneratorFields(o.AllowGeneratorFields)
+	return !allow.MatchField(field) || allow.Match(generatorName, field)
+}
+
 func (o Options) Deny(generatorName string, field string) bool {
-	fields := o.DenyGeneratorFields[generatorName]
-	for _, f := range fields {
-		if f == field {
+	deny := rule.GeneratorFields(o.DenyGeneratorFields)
+	return deny.Match(generatorName, field)
+}
+
+func (o Options) Disabled(generatorName string) bool {
+	for _, g := range o.DisabledGenerators {
+		if rule.MatchGenerator(g, generatorName) {
 			return true
 		}
 	}
 	return false
 }
 
+func (o Options) MaxGeneratorRules() int {
+	return o.MaxRulesPerGenerator
+}
+
+func (o Options) MaxFieldRules() int {
+	return o.MaxRulesPerField
+}
+
+func (o Options) MinSupport() int64 {
+	return o.MinRuleSupport
+}
+
 // Process processes a Dataset to find Rules to meet the supplied requirements
 func Process(
 	dataset ddataset.Dataset,
@@ -79,14 +160,40 @@ func Process(
 	rules []rule.Rule,
 	opts Options,
 ) (*assessment.Assessment, error) {
-	fieldDescriptions, err := description.DescribeDataset(dataset)
+	if opts.MaxDatasetCacheBytes > 0 {
+		// The cache is filled while describing the Dataset
+		dataset = colcache.New(dataset, opts.MaxDatasetCacheBytes)
+	}
+	var fieldDescriptions *description.Description
+	var err error
+	if opts.BooleanTokens != nil {
+		fieldDescriptions, err =
+			description.DescribeDatasetWithTokens(dataset, *opts.BooleanTokens)
+	} else {
+		fieldDescriptions, err = description.DescribeDataset(dataset)
+	}
 	if err != nil {
 		return nil, DescribeError{Err: err}
 	}
+	if opts.CorrelateFields {
+		if err := fieldDescriptions.Correlate(dataset); err != nil {
+			return nil, DescribeError{Err: err}
+		}
+	}
 	if len(opts.RuleFields) == 0 {
 		rules = append(rules, rule.NewTrue())
 	}
 	ass := assessment.New(aggregators, goals)
+	ass.KeepCoverage(opts.KeepRuleCoverage)
+	if opts.RefineOptions != nil {
+		ass.SetRefineOptions(*opts.RefineOptions)
+	}
+	if opts.EarlyStopRules {
+		ass.EarlyStop([]assessment.SortOrder{}, 0)
+		if len(opts.RuleFields) == 0 {
+			earlyStopFinalPass(ass, sortOrder, opts)
+		}
+	}
 	if err := ass.AssessRules(dataset, rules); err != nil {
 		return nil, AssessError{Err: err}
 	}
@@ -104,13 +211,20 @@ func Process(
 			return nil, err
 		}
 	}
-	ass.Sort(sortOrder)
+	sortAssessment(ass, sortOrder, opts)
 	ass.Refine()
 
-	if opts.MaxNumRules-len(rules) < 1 {
-		return ass.TruncateRuleAssessments(1), nil
+	maxNumRules := opts.MaxNumRules - len(rules)
+	if maxNumRules < 1 {
+		maxNumRules = 1
+	}
+	if opts.DiversityOptions != nil {
+		return ass.TruncateRuleAssessmentsDiverse(
+			maxNumRules,
+			*opts.DiversityOptions,
+		), nil
 	}
-	return ass.TruncateRuleAssessments(opts.MaxNumRules - len(rules)), nil
+	return ass.TruncateRuleAssessments(maxNumRules), nil
 }
 
 func processGenerate(
@@ -133,7 +247,7 @@ func processGenerate(
 		return AssessError{Err: err}
 	}
 
-	ass.Sort(sortOrder)
+	sortAssessment(ass, sortOrder, opts)
 	ass.Refine()
 
 	if len(opts.Fields()) == 2 {
@@ -141,7 +255,7 @@ func processGenerate(
 		if err := ass.AssessRules(dataset, cRules); err != nil {
 			return AssessError{Err: err}
 		}
-		ass.Sort(sortOrder)
+		sortAssessment(ass, sortOrder, opts)
 		ass.Refine()
 	}
 
@@ -151,7 +265,7 @@ func processGenerate(
 	if err := ass.AssessRules(dataset, tweakableRules); err != nil {
 		return AssessError{Err: err}
 	}
-	ass.Sort(sortOrder)
+	sortAssessment(ass, sortOrder, opts)
 	ass.Refine()
 
 	bestRules = ass.Rules()
@@ -160,13 +274,42 @@ func processGenerate(
 	if err := ass.AssessRules(dataset, reducedDPRules); err != nil {
 		return AssessError{Err: err}
 	}
-	ass.Sort(sortOrder)
+	sortAssessment(ass, sortOrder, opts)
 	ass.Refine()
 
 	combinedRules := rule.Combine(ass.Rules(), 2000)
 
+	earlyStopFinalPass(ass, sortOrder, opts)
 	if err := ass.AssessRules(dataset, combinedRules); err != nil {
 		return AssessError{Err: err}
 	}
 	return nil
 }
+
+// earlyStopFinalPass lets the final pass of the Dataset drop rules that
+// can't be among the best opts.MaxNumRules rules.  Earlier passes only
+// drop rules that can't reach the minimum percentMatches, because their
+// best rules are used to make the rules for the passes that follow.
+func earlyStopFinalPass(
+	ass *assessment.Assessment,
+	sortOrder []assessment.SortOrder,
+	opts Options,
+) {
+	if opts.EarlyStopRules && !opts.ParetoSort {
+		ass.EarlyStop(sortOrder, opts.MaxNumRules)
+	}
+}
+
+// sortAssessment sorts the Assessment using the sort order either by
+// each aggregator in turn or by Pareto front
+func sortAssessment(
+	ass *assessment.Assessment,
+	sortOrder []assessment.SortOrder,
+	opts Options,
+) {
+	if opts.ParetoSort {
+		ass.Sort([]assessment.SortOrder{assessment.NewParetoSortOrder(sortOrder)})
+	} else {
+		ass.Sort(sortOrder)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/lawrencewoodman/ddataset/dcsv"
 	"github.com/vlifesystems/rhkit/aggregator"
 	"github.com/vlifesystems/rhkit/assessment"
+	"github.com/vlifesystems/rhkit/description"
 	"github.com/vlifesystems/rhkit/goal"
 	"github.com/vlifesystems/rhkit/rule"
 	"path/filepath"
@@ -26,16 +27,16 @@ func TestProcess(t *testing.T) {
 		"campaign", "pdays", "previous", "poutcome", "y",
 	}
 	aggregatorDescs := []*aggregator.Desc{
-		{"numSignedUp", "count", "y == \"yes\""},
-		{"cost", "calc", "numMatches * 4.5"},
-		{"income", "calc", "numSignedUp * 24"},
-		{"profit", "calc", "income - cost"},
-		{"oddFigure", "sum", "balance - age"},
-		{
+		aggregator.NewDesc("numSignedUp", "count", "y == \"yes\""),
+		aggregator.NewDesc("cost", "calc", "numMatches * 4.5"),
+		aggregator.NewDesc("income", "calc", "numSignedUp * 24"),
+		aggregator.NewDesc("profit", "calc", "income - cost"),
+		aggregator.NewDesc("oddFigure", "sum", "balance - age"),
+		aggregator.NewDesc(
 			"percentMarried",
 			"precision",
 			"marital == \"married\"",
-		},
+		),
 	}
 	goalExprs := []string{"profit > 0"}
 	sortOrderDescs := []assessment.SortDesc{
@@ -90,6 +91,90 @@ func TestProcess(t *testing.T) {
 			wantMinNumRules: 500,
 			wantMaxNumRules: 500,
 		},
+		{opts: Options{
+			MaxNumRules:   500,
+			RuleFields:    ruleFields,
+			BooleanTokens: &description.DefaultBooleanTokens,
+		},
+			wantMinNumRules: 500,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules:      500,
+			RuleFields:       ruleFields,
+			KeepRuleCoverage: true,
+		},
+			wantMinNumRules: 500,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules:          500,
+			RuleFields:           ruleFields,
+			MaxDatasetCacheBytes: 50 * 1024 * 1024,
+		},
+			wantMinNumRules: 500,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules:          500,
+			RuleFields:           ruleFields,
+			MaxDatasetCacheBytes: 1024,
+		},
+			wantMinNumRules: 500,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules:    500,
+			RuleFields:     ruleFields,
+			EarlyStopRules: true,
+		},
+			wantMinNumRules: 500,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules: 500,
+			RuleFields:  ruleFields,
+			RefineOptions: &assessment.RefineOptions{
+				MinPercentMatches: 5,
+			},
+			EarlyStopRules: true,
+		},
+			wantMinNumRules: 100,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules: 500,
+			RuleFields:  ruleFields,
+			RefineOptions: &assessment.RefineOptions{
+				MinPercentMatches: 1,
+				MaxPercentMatches: 90,
+				MaxRulesPerField:  20,
+			},
+		},
+			wantMinNumRules: 20,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules:      500,
+			RuleFields:       ruleFields,
+			KeepRuleCoverage: true,
+			DiversityOptions: &assessment.DiversityOptions{
+				Relevance:  0.7,
+				Similarity: assessment.CoverageSimilarity,
+			},
+		},
+			wantMinNumRules: 500,
+			wantMaxNumRules: 500,
+		},
+		{opts: Options{
+			MaxNumRules:    500,
+			RuleFields:     ruleFields,
+			ParetoSort:     true,
+			EarlyStopRules: true,
+		},
+			wantMinNumRules: 500,
+			wantMaxNumRules: 500,
+		},
 		{opts: Options{MaxNumRules: 3000, RuleFields: ruleFields},
 			wantMinNumRules: 1400,
 			wantMaxNumRules: 1600,
@@ -134,16 +219,16 @@ func TestProcess_user_rules(t *testing.T) {
 		fields,
 	)
 	aggregatorDescs := []*aggregator.Desc{
-		{"numSignedUp", "count", "y == \"yes\""},
-		{"cost", "calc", "numMatches * 4.5"},
-		{"income", "calc", "numSignedUp * 24"},
-		{"profit", "calc", "income - cost"},
-		{"oddFigure", "sum", "balance - age"},
-		{
+		aggregator.NewDesc("numSignedUp", "count", "y == \"yes\""),
+		aggregator.NewDesc("cost", "calc", "numMatches * 4.5"),
+		aggregator.NewDesc("income", "calc", "numSignedUp * 24"),
+		aggregator.NewDesc("profit", "calc", "income - cost"),
+		aggregator.NewDesc("oddFigure", "sum", "balance - age"),
+		aggregator.NewDesc(
 			"percentMarried",
 			"precision",
 			"marital == \"married\"",
-		},
+		),
 	}
 	goalExprs := []string{"profit > 0"}
 	sortOrderDescs := []assessment.SortDesc{
@@ -211,3 +296,131 @@ func TestProcess_user_rules(t *testing.T) {
 		}
 	}
 }
+
+func TestOptionsDisabled(t *testing.T) {
+	opts := Options{DisabledGenerators: []string{"EQFV", "count", "Add*"}}
+	cases := []struct {
+		generatorName string
+		want          bool
+	}{
+		{generatorName: "EQFV", want: true},
+		{generatorName: "CountEQVF", want: true},
+		{generatorName: "AddGEF", want: true},
+		{generatorName: "InFV", want: false},
+		{generatorName: "NEFV", want: false},
+	}
+	for _, c := range cases {
+		got := opts.Disabled(c.generatorName)
+		if got != c.want {
+			t.Errorf("Disabled(%s) got: %t, want: %t", c.generatorName, got, c.want)
+		}
+	}
+}
+
+func TestOptionsAllowDeny(t *testing.T) {
+	cases := []struct {
+		opts          Options
+		generatorName string
+		field         string
+		wantAllow     bool
+		wantDeny      bool
+	}{
+		{opts: Options{},
+			generatorName: "EQFV",
+			field:         "job",
+			wantAllow:     true,
+			wantDeny:      false,
+		},
+		{opts: Options{
+			AllowGeneratorFields: map[string][]string{
+				"InFV": []string{"job"},
+				"EQFV": []string{"job"},
+			},
+		},
+			generatorName: "EQFV",
+			field:         "job",
+			wantAllow:     true,
+			wantDeny:      false,
+		},
+		{opts: Options{
+			AllowGeneratorFields: map[string][]string{
+				"InFV": []string{"job"},
+				"EQFV": []string{"job"},
+			},
+		},
+			generatorName: "NEFV",
+			field:         "job",
+			wantAllow:     false,
+			wantDeny:      false,
+		},
+		{opts: Options{
+			AllowGeneratorFields: map[string][]string{
+				"InFV": []string{"job"},
+				"EQFV": []string{"job"},
+			},
+		},
+			generatorName: "NEFV",
+			field:         "town",
+			wantAllow:     true,
+			wantDeny:      false,
+		},
+		{opts: Options{
+			DenyGeneratorFields: map[string][]string{
+				"CountEQFV": []string{"job"},
+			},
+		},
+			generatorName: "CountEQVF",
+			field:         "job",
+			wantAllow:     true,
+			wantDeny:      true,
+		},
+		{opts: Options{
+			AllowGeneratorFields: map[string][]string{
+				"range": []string{"income*"},
+			},
+			DenyGeneratorFields: map[string][]string{
+				"BetweenFV": []string{"incomeB"},
+			},
+		},
+			generatorName: "BetweenFV",
+			field:         "incomeA",
+			wantAllow:     true,
+			wantDeny:      false,
+		},
+		{opts: Options{
+			AllowGeneratorFields: map[string][]string{
+				"range": []string{"income*"},
+			},
+			DenyGeneratorFields: map[string][]string{
+				"BetweenFV": []string{"incomeB"},
+			},
+		},
+			generatorName: "BetweenFV",
+			field:         "incomeB",
+			wantAllow:     true,
+			wantDeny:      true,
+		},
+		{opts: Options{
+			DenyGeneratorFields: map[string][]string{
+				"*": []string{"id"},
+			},
+		},
+			generatorName: "MulLEF",
+			field:         "id",
+			wantAllow:     true,
+			wantDeny:      true,
+		},
+	}
+	for i, c := range cases {
+		gotAllow := c.opts.Allow(c.generatorName, c.field)
+		gotDeny := c.opts.Deny(c.generatorName, c.field)
+		if gotAllow != c.wantAllow {
+			t.Errorf("(%d) Allow(%s, %s) got: %t, want: %t",
+				i, c.generatorName, c.field, gotAllow, c.wantAllow)
+		}
+		if gotDeny != c.wantDeny {
+			t.Errorf("(%d) Deny(%s, %s) got: %t, want: %t",
+				i, c.generatorName, c.field, gotDeny, c.wantDeny)
+		}
+	}
+}
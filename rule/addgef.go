@@ -20,7 +20,7 @@ type AddGEF struct {
 }
 
 func init() {
-	registerGenerator("AddGEF", generateAddGEF)
+	RegisterGenerator("AddGEF", generateAddGEF)
 }
 
 func NewAddGEF(fieldA string, fieldB string, value *dlit.Literal) *AddGEF {
@@ -39,6 +39,15 @@ func (r *AddGEF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *AddGEF) Complexity() int {
+	return 3
+}
+
+func (r *AddGEF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileArithmeticFF(r, decoder, r.fieldA, r.fieldB, r.value,
+		addInt, addFloat, geInt, geFloat)
+}
+
 // IsTrue returns whether the rule is true for this record.
 // This rule relies on making sure that the two fields when
 // added will not overflow, so this must have been checked
@@ -122,9 +131,15 @@ func generateAddGEF(
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
+	numFieldPairs :=
+		numPairs(numNumberFields(inputDescription, generationDesc, "AddGEF"))
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("AddGEF", field) ||
+		if !useField(generationDesc, "AddGEF", field) ||
 			!generationDesc.Arithmetic() ||
 			fd.Kind != description.Number {
 			continue
@@ -132,9 +147,12 @@ func generateAddGEF(
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 
 		for _, oField := range generationDesc.Fields() {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
 			oFd := inputDescription.Fields[oField]
 			oFieldNum := description.CalcFieldNum(inputDescription.Fields, oField)
-			if !generationDesc.Deny("AddGEF", oField) &&
+			if useField(generationDesc, "AddGEF", oField) &&
 				fieldNum < oFieldNum &&
 				oFd.Kind == description.Number {
 				vars := map[string]*dlit.Literal{
@@ -149,10 +167,14 @@ func generateAddGEF(
 				if oFd.MaxDP > maxDP {
 					maxDP = oFd.MaxDP
 				}
-				points := internal.GeneratePoints(min, max, maxDP)
+				points := budget.limitPoints(
+					internal.GeneratePoints(min, max, maxDP),
+					numFieldPairs,
+					onePerPoint,
+				)
 				for _, p := range points {
 					r := NewAddGEF(field, oField, p)
-					rules = append(rules, r)
+					rules = budget.add(rules, r)
 				}
 			}
 		}
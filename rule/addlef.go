@@ -20,7 +20,7 @@ type AddLEF struct {
 }
 
 func init() {
-	registerGenerator("AddLEF", generateAddLEF)
+	RegisterGenerator("AddLEF", generateAddLEF)
 }
 
 func NewAddLEF(fieldA string, fieldB string, value *dlit.Literal) *AddLEF {
@@ -39,6 +39,15 @@ func (r *AddLEF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *AddLEF) Complexity() int {
+	return 3
+}
+
+func (r *AddLEF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileArithmeticFF(r, decoder, r.fieldA, r.fieldB, r.value,
+		addInt, addFloat, leInt, leFloat)
+}
+
 // IsTrue returns whether the rule is true for this record.
 // This rule relies on making sure that the two fields when
 // added will not overflow, so this must have been checked
@@ -120,9 +129,15 @@ func generateAddLEF(
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
+	numFieldPairs :=
+		numPairs(numNumberFields(inputDescription, generationDesc, "AddLEF"))
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("AddLEF", field) ||
+		if !useField(generationDesc, "AddLEF", field) ||
 			!generationDesc.Arithmetic() ||
 			fd.Kind != description.Number {
 			continue
@@ -130,9 +145,12 @@ func generateAddLEF(
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 
 		for _, oField := range generationDesc.Fields() {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
 			oFd := inputDescription.Fields[oField]
 			oFieldNum := description.CalcFieldNum(inputDescription.Fields, oField)
-			if !generationDesc.Deny("AddLEF", oField) &&
+			if useField(generationDesc, "AddLEF", oField) &&
 				fieldNum < oFieldNum &&
 				oFd.Kind == description.Number {
 				vars := map[string]*dlit.Literal{
@@ -147,10 +165,14 @@ func generateAddLEF(
 				if oFd.MaxDP > maxDP {
 					maxDP = oFd.MaxDP
 				}
-				points := internal.GeneratePoints(min, max, maxDP)
+				points := budget.limitPoints(
+					internal.GeneratePoints(min, max, maxDP),
+					numFieldPairs,
+					onePerPoint,
+				)
 				for _, p := range points {
 					r := NewAddLEF(field, oField, p)
-					rules = append(rules, r)
+					rules = budget.add(rules, r)
 				}
 			}
 		}
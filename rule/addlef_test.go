@@ -170,7 +170,7 @@ func TestAddLEFTweak(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -192,7 +192,7 @@ func TestAddLEFTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -214,7 +214,7 @@ func TestAddLEFTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -236,7 +236,7 @@ func TestAddLEFTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -258,7 +258,7 @@ func TestAddLEFTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(200),
@@ -282,7 +282,7 @@ func TestAddLEFTweak(t *testing.T) {
 			maxDP:       3,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -345,7 +345,7 @@ func TestGenerateAddLEF(t *testing.T) {
 		maxDP          int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -370,7 +370,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -395,7 +395,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -420,7 +420,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -445,7 +445,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(200),
@@ -472,7 +472,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       3,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -497,7 +497,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(200),
@@ -524,7 +524,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -550,7 +550,7 @@ func TestGenerateAddLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -606,7 +606,7 @@ func TestGenerateAddLEF(t *testing.T) {
 
 func TestGenerateAddLEF_multiple_fields(t *testing.T) {
 	description := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"balance": {
 				Kind: description.Number,
 				Min:  dlit.MustNew(250),
@@ -6,9 +6,7 @@ package rule
 import (
 	"fmt"
 	"github.com/lawrencewoodman/ddataset"
-	"github.com/lawrencewoodman/dexpr"
-	"github.com/lawrencewoodman/dlit"
-	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
+	"reflect"
 )
 
 // And represents a rule determining if ruleA AND ruleB
@@ -17,176 +15,46 @@ type And struct {
 	ruleB Rule
 }
 
+// NewAnd returns a rule that is true if both ruleA and ruleB are true.
+// If the rules use the same single field and can be joined into a single
+// rule, then that rule is returned.  An error is returned if either rule
+// is True, if the rules can never be true together, if one of the rules
+// makes the other redundant or if they use the same field and can't be
+// joined, but are of the same kind or are intervals that only share a
+// single value.
 func NewAnd(ruleA Rule, ruleB Rule) (Rule, error) {
 	_, ruleAIsTrue := ruleA.(True)
 	_, ruleBIsTrue := ruleB.(True)
 	if ruleAIsTrue || ruleBIsTrue {
 		return nil, fmt.Errorf("can't And rule: %s, with: %s", ruleA, ruleB)
 	}
-
-	if skip, r := tryJoinRulesWithBetween(ruleA, ruleB); !skip {
-		if r != nil {
+	if isSameSingleField(ruleA, ruleB) {
+		r, result := mergeAndClauses(ruleA, ruleB)
+		if result != notMerged {
+			if result != merged ||
+				r.String() == ruleA.String() || r.String() == ruleB.String() {
+				return nil, fmt.Errorf("can't And rule: %s, with: %s", ruleA, ruleB)
+			}
 			return r, nil
 		}
-		return nil, fmt.Errorf("can't And rule: %s, with: %s", ruleA, ruleB)
-	}
-	if skip, r := tryInRule(ruleA, ruleB); !skip {
-		if r != nil {
-			return r, nil
+		if !canAndUnmerged(ruleA, ruleB) {
+			return nil, fmt.Errorf("can't And rule: %s, with: %s", ruleA, ruleB)
 		}
-		return nil, fmt.Errorf("can't And rule: %s, with: %s", ruleA, ruleB)
-	}
-	if skip, r := tryEqNeRule(ruleA, ruleB); !skip {
-		if r != nil {
-			return r, nil
-		}
-		return nil, fmt.Errorf("can't And rule: %s, with: %s", ruleA, ruleB)
 	}
 	return &And{ruleA: ruleA, ruleB: ruleB}, nil
 }
 
-func tryInRule(ruleA, ruleB Rule) (skip bool, newRule Rule) {
-	_, ruleAIsIn := ruleA.(*InFV)
-	_, ruleBIsIn := ruleB.(*InFV)
-	if !ruleAIsIn && !ruleBIsIn {
-		return true, nil
-	}
-	fieldsA := ruleA.Fields()
-	fieldsB := ruleB.Fields()
-	if len(fieldsA) == 1 && len(fieldsB) == 1 && fieldsA[0] == fieldsB[0] {
-		return false, nil
-	}
-	return false, &And{ruleA: ruleA, ruleB: ruleB}
-}
-
-func tryEqNeRule(ruleA, ruleB Rule) (skip bool, newRule Rule) {
-	ruleAFields := ruleA.Fields()
-	ruleBFields := ruleB.Fields()
-	if len(ruleAFields) != 1 || len(ruleBFields) != 1 {
-		return true, nil
-	}
-	fieldA := ruleA.Fields()[0]
-	fieldB := ruleB.Fields()[0]
-
-	if fieldA != fieldB {
-		return true, nil
-	}
-	switch ruleA.(type) {
-	case *EQFV:
-	case *NEFV:
-	default:
-		return true, nil
-	}
-	switch ruleB.(type) {
-	case *EQFV:
-	case *NEFV:
-	default:
-		return false, &And{ruleA: ruleA, ruleB: ruleB}
-	}
-	return false, nil
-}
-
-func tryJoinRulesWithBetween(
-	ruleA Rule,
-	ruleB Rule,
-) (skip bool, newRule Rule) {
-	var r Rule
-	var err error
-
-	if len(ruleA.Fields()) != 1 || len(ruleB.Fields()) != 1 {
-		return true, nil
-	}
-	fieldA := ruleA.Fields()[0]
-	fieldB := ruleB.Fields()[0]
-
-	if fieldA != fieldB {
-		return true, nil
-	}
-	_, ruleAIsBetweenFV := ruleA.(*BetweenFV)
-	_, ruleBIsBetweenFV := ruleB.(*BetweenFV)
-	OutsideFVRuleA, ruleAIsOutsideFV := ruleA.(*OutsideFV)
-	OutsideFVRuleB, ruleBIsOutsideFV := ruleB.(*OutsideFV)
-
-	if (ruleAIsBetweenFV && !ruleBIsOutsideFV) ||
-		(!ruleAIsOutsideFV && ruleBIsBetweenFV) ||
-		(ruleAIsOutsideFV && ruleBIsOutsideFV) {
-		return false, nil
-	}
-
-	GEFVRuleA, ruleAIsGEFV := ruleA.(*GEFV)
-	LEFVRuleA, ruleAIsLEFV := ruleA.(*LEFV)
-	GEFVRuleB, ruleBIsGEFV := ruleB.(*GEFV)
-	LEFVRuleB, ruleBIsLEFV := ruleB.(*LEFV)
-
-	if (ruleAIsGEFV && ruleBIsGEFV) ||
-		(ruleAIsLEFV && ruleBIsLEFV) {
-		return false, nil
-	}
-
-	vars := map[string]*dlit.Literal{
-		"ruleAIsLEFV":        dlit.MustNew(ruleAIsLEFV),
-		"ruleBIsLEFV":        dlit.MustNew(ruleBIsLEFV),
-		"ruleAIsGEFV":        dlit.MustNew(ruleAIsGEFV),
-		"ruleBIsGEFV":        dlit.MustNew(ruleBIsGEFV),
-		"ruleAIsOutsideFV":   dlit.MustNew(ruleAIsOutsideFV),
-		"ruleBIsOutsideFV":   dlit.MustNew(ruleBIsOutsideFV),
-		"LEFVRuleAValue":     dlit.MustNew(0),
-		"LEFVRuleBValue":     dlit.MustNew(0),
-		"GEFVRuleAValue":     dlit.MustNew(0),
-		"GEFVRuleBValue":     dlit.MustNew(0),
-		"OutsideFVRuleALow":  dlit.MustNew(0),
-		"OutsideFVRuleAHigh": dlit.MustNew(0),
-		"OutsideFVRuleBLow":  dlit.MustNew(0),
-		"OutsideFVRuleBHigh": dlit.MustNew(0),
-	}
-
-	if ruleAIsLEFV {
-		vars["LEFVRuleAValue"] = LEFVRuleA.Value()
-	}
-	if ruleBIsLEFV {
-		vars["LEFVRuleBValue"] = LEFVRuleB.Value()
-	}
-	if ruleAIsGEFV {
-		vars["GEFVRuleAValue"] = GEFVRuleA.Value()
-	}
-	if ruleBIsGEFV {
-		vars["GEFVRuleBValue"] = GEFVRuleB.Value()
-	}
-	if ruleAIsOutsideFV {
-		vars["OutsideFVRuleALow"] = OutsideFVRuleA.Low()
-		vars["OutsideFVRuleAHigh"] = OutsideFVRuleA.High()
-	}
-	if ruleBIsOutsideFV {
-		vars["OutsideFVRuleBLow"] = OutsideFVRuleB.Low()
-		vars["OutsideFVRuleBHigh"] = OutsideFVRuleB.High()
-	}
-	invalidExpr, err := dexpr.EvalBool(
-		"(ruleAIsOutsideFV && ruleBIsLEFV && "+
-			" OutsideFVRuleAHigh >= LEFVRuleBValue) || "+
-			"(ruleAIsOutsideFV && ruleBIsGEFV && "+
-			" OutsideFVRuleALow <= GEFVRuleBValue) || "+
-			"(ruleBIsOutsideFV && ruleAIsLEFV && "+
-			" OutsideFVRuleBHigh >= LEFVRuleAValue) || "+
-			"(ruleBIsOutsideFV && ruleAIsGEFV && "+
-			"	OutsideFVRuleBLow <= GEFVRuleAValue)",
-		dexprfuncs.CallFuncs,
-		vars,
-	)
-	if invalidExpr || err != nil {
-		return false, nil
-	}
-
-	if ruleAIsGEFV && ruleBIsLEFV {
-		r, err = NewBetweenFV(fieldA, GEFVRuleA.Value(), LEFVRuleB.Value())
-	} else if ruleAIsLEFV && ruleBIsGEFV {
-		r, err = NewBetweenFV(fieldA, GEFVRuleB.Value(), LEFVRuleA.Value())
-	} else {
-		return true, nil
-	}
-	if err != nil {
-		return false, nil
-	}
-	return false, r
+// canAndUnmerged returns whether two rules for the same field that
+// couldn't be merged may still be Anded.  They can't if they are of the
+// same kind, such as two OutsideFV rules, or if they are intervals that
+// only share a single value, such as: flow >= 2.1 && flow <= 2.1
+func canAndUnmerged(ruleA, ruleB Rule) bool {
+	if reflect.TypeOf(ruleA) == reflect.TypeOf(ruleB) {
+		return false
+	}
+	_, ruleAIsInterval := toInterval(ruleA)
+	_, ruleBIsInterval := toInterval(ruleB)
+	return !ruleAIsInterval || !ruleBIsInterval
 }
 
 func MustNewAnd(ruleA Rule, ruleB Rule) Rule {
@@ -197,6 +65,16 @@ func MustNewAnd(ruleA Rule, ruleB Rule) Rule {
 	return r
 }
 
+// RuleA returns the first rule of the And
+func (r *And) RuleA() Rule {
+	return r.ruleA
+}
+
+// RuleB returns the second rule of the And
+func (r *And) RuleB() Rule {
+	return r.ruleB
+}
+
 func (r *And) String() string {
 	// TODO: Consider making this AND rather than &&
 	aStr := r.ruleA.String()
@@ -253,3 +131,11 @@ func (r *And) Fields() []string {
 	}
 	return results
 }
+
+func (r *And) Complexity() int {
+	return Complexity(r.ruleA) + Complexity(r.ruleB) + 1
+}
+
+func (r *And) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileLogical(r, decoder, r.ruleA, r.ruleB, true)
+}
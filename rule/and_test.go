@@ -183,18 +183,6 @@ func TestNewAnd_errors(t *testing.T) {
 			}),
 			wantErrStr: "can't And rule: group == \"norris\", with: in(group,\"bob\",\"fred\",\"albert\")",
 		},
-		{ruleA: NewInFV("group", []*dlit.Literal{
-			dlit.NewString("bob"),
-			dlit.NewString("fred"),
-			dlit.NewString("albert"),
-		}),
-			ruleB: NewInFV("group", []*dlit.Literal{
-				dlit.NewString("harry"),
-				dlit.NewString("fred"),
-				dlit.NewString("albert"),
-			}),
-			wantErrStr: "can't And rule: in(group,\"bob\",\"fred\",\"albert\"), with: in(group,\"harry\",\"fred\",\"albert\")",
-		},
 		{ruleA: NewTrue(),
 			ruleB:      NewEQFF("flow", "rate"),
 			wantErrStr: "can't And rule: true(), with: flow == rate",
@@ -251,74 +239,26 @@ func TestNewAnd_errors(t *testing.T) {
 			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(7.3), dlit.MustNew(8.9)),
 			wantErrStr: "can't And rule: flow <= 1.7 || flow >= 5.2, with: flow <= 7.3 || flow >= 8.9",
 		},
-		{ruleA: NewGEFV("flow", dlit.MustNew(1.05)),
-			ruleB:      MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			wantErrStr: "can't And rule: flow >= 1.05, with: flow >= 0.7 && flow <= 2.1",
-		},
-		{ruleA: MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			ruleB:      NewGEFV("flow", dlit.MustNew(1.05)),
-			wantErrStr: "can't And rule: flow >= 0.7 && flow <= 2.1, with: flow >= 1.05",
-		},
-		{ruleA: MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
-			ruleB:      NewGEFV("rate", dlit.MustNew(1)),
-			wantErrStr: "can't And rule: rate <= 0 || rate >= 2, with: rate >= 1",
-		},
 		{ruleA: MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
 			ruleB:      NewGEFV("rate", dlit.MustNew(3)),
 			wantErrStr: "can't And rule: rate <= 0 || rate >= 2, with: rate >= 3",
 		},
-		{ruleA: NewGEFV("rate", dlit.MustNew(1)),
-			ruleB:      MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
-			wantErrStr: "can't And rule: rate >= 1, with: rate <= 0 || rate >= 2",
-		},
 		{ruleA: NewGEFV("rate", dlit.MustNew(3)),
 			ruleB:      MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
 			wantErrStr: "can't And rule: rate >= 3, with: rate <= 0 || rate >= 2",
 		},
-		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			ruleB:      NewGEFV("flow", dlit.MustNew(1.05)),
-			wantErrStr: "can't And rule: flow <= 0.7 || flow >= 2.1, with: flow >= 1.05",
-		},
-		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			ruleB:      NewGEFV("flow", dlit.MustNew(2.05)),
-			wantErrStr: "can't And rule: flow <= 0.7 || flow >= 2.1, with: flow >= 2.05",
-		},
-		{ruleA: NewGEFV("flow", dlit.MustNew(1.05)),
-			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			wantErrStr: "can't And rule: flow >= 1.05, with: flow <= 0.7 || flow >= 2.1",
-		},
-		{ruleA: NewGEFV("flow", dlit.MustNew(2.05)),
-			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			wantErrStr: "can't And rule: flow >= 2.05, with: flow <= 0.7 || flow >= 2.1",
-		},
-		{ruleA: MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
-			ruleB:      NewLEFV("rate", dlit.MustNew(1)),
-			wantErrStr: "can't And rule: rate <= 0 || rate >= 2, with: rate <= 1",
-		},
 		{ruleA: MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
 			ruleB:      NewLEFV("rate", dlit.MustNew(-1)),
 			wantErrStr: "can't And rule: rate <= 0 || rate >= 2, with: rate <= -1",
 		},
-		{ruleA: NewLEFV("rate", dlit.MustNew(1)),
-			ruleB:      MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
-			wantErrStr: "can't And rule: rate <= 1, with: rate <= 0 || rate >= 2",
-		},
 		{ruleA: NewLEFV("rate", dlit.MustNew(-1)),
 			ruleB:      MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
 			wantErrStr: "can't And rule: rate <= -1, with: rate <= 0 || rate >= 2",
 		},
-		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			ruleB:      NewLEFV("flow", dlit.MustNew(1.05)),
-			wantErrStr: "can't And rule: flow <= 0.7 || flow >= 2.1, with: flow <= 1.05",
-		},
 		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
 			ruleB:      NewLEFV("flow", dlit.MustNew(0.5)),
 			wantErrStr: "can't And rule: flow <= 0.7 || flow >= 2.1, with: flow <= 0.5",
 		},
-		{ruleA: NewLEFV("flow", dlit.MustNew(1.05)),
-			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			wantErrStr: "can't And rule: flow <= 1.05, with: flow <= 0.7 || flow >= 2.1",
-		},
 		{ruleA: NewLEFV("flow", dlit.MustNew(0.5)),
 			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
 			wantErrStr: "can't And rule: flow <= 0.5, with: flow <= 0.7 || flow >= 2.1",
@@ -336,6 +276,79 @@ func TestNewAnd_errors(t *testing.T) {
 	}
 }
 
+func TestNewAnd_joined(t *testing.T) {
+	cases := []struct {
+		ruleA Rule
+		ruleB Rule
+		want  string
+	}{
+		{ruleA: NewInFV("group", []*dlit.Literal{
+			dlit.NewString("bob"),
+			dlit.NewString("fred"),
+			dlit.NewString("albert"),
+		}),
+			ruleB: NewInFV("group", []*dlit.Literal{
+				dlit.NewString("harry"),
+				dlit.NewString("fred"),
+				dlit.NewString("albert"),
+			}),
+			want: "in(group,\"fred\",\"albert\")",
+		},
+		{ruleA: NewInFV("group", []*dlit.Literal{
+			dlit.NewString("1"),
+			dlit.NewString("2"),
+		}),
+			ruleB: NewInFV("group", []*dlit.Literal{
+				dlit.NewString("2"),
+				dlit.NewString("3"),
+			}),
+			want: "in(group,\"2\")",
+		},
+		{ruleA: NewEQFV("group", dlit.MustNew(2)),
+			ruleB: NewInFV("group", []*dlit.Literal{
+				dlit.NewString("2"),
+				dlit.NewString("3"),
+			}),
+			want: "in(group,\"2\")",
+		},
+		{ruleA: NewGEFV("flow", dlit.MustNew(1.05)),
+			ruleB: MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
+			want:  "flow >= 1.05 && flow <= 2.1",
+		},
+		{ruleA: MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
+			ruleB: NewGEFV("flow", dlit.MustNew(1.05)),
+			want:  "flow >= 1.05 && flow <= 2.1",
+		},
+		{ruleA: MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
+			ruleB: NewGEFV("rate", dlit.MustNew(1)),
+			want:  "rate >= 2",
+		},
+		{ruleA: NewGEFV("flow", dlit.MustNew(2.05)),
+			ruleB: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
+			want:  "flow >= 2.1",
+		},
+		{ruleA: MustNewOutsideFV("rate", dlit.MustNew(0), dlit.MustNew(2)),
+			ruleB: NewLEFV("rate", dlit.MustNew(1)),
+			want:  "rate <= 0",
+		},
+		{ruleA: NewLEFV("flow", dlit.MustNew(1.05)),
+			ruleB: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
+			want:  "flow <= 0.7",
+		},
+	}
+	for _, c := range cases {
+		r, err := NewAnd(c.ruleA, c.ruleB)
+		if err != nil {
+			t.Errorf("NewAnd(%s, %s) got err: %s", c.ruleA, c.ruleB, err)
+			continue
+		}
+		if r.String() != c.want {
+			t.Errorf("NewAnd(%s, %s) got: %s, want: %s",
+				c.ruleA, c.ruleB, r, c.want)
+		}
+	}
+}
+
 func TestMustNewAnd(t *testing.T) {
 	ruleA := NewEQFF("flow", "rate")
 	ruleB := NewEQFF("income", "cost")
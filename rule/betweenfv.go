@@ -22,9 +22,14 @@ type BetweenFV struct {
 }
 
 func init() {
-	registerGenerator("BetweenFV", generateBetweenFV)
+	RegisterGenerator("BetweenFV", generateBetweenFV)
 }
 
+var rangeOverlapsExpr = dexpr.MustNew(
+	"((oMin >= min && oMin <= max) || (oMax >= min && oMax <= max))",
+	dexprfuncs.CallFuncs,
+)
+
 func NewBetweenFV(
 	field string,
 	min *dlit.Literal,
@@ -97,6 +102,34 @@ func (r *BetweenFV) Fields() []string {
 	return []string{r.field}
 }
 
+func (r *BetweenFV) Complexity() int {
+	return 2
+}
+
+func (r *BetweenFV) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	i, ok := decoder.index[r.field]
+	if !ok {
+		return nil, false
+	}
+	minInt, minIsInt := r.min.Int()
+	maxInt, maxIsInt := r.max.Int()
+	minFloat, minIsFloat := r.min.Float()
+	maxFloat, maxIsFloat := r.max.Float()
+	return func(record *DecodedRecord) (bool, error) {
+		v := &record.values[i]
+		if !v.exists {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if v.isInt && minIsInt && maxIsInt {
+			return v.int >= minInt && v.int <= maxInt, nil
+		}
+		if v.isFloat && minIsFloat && maxIsFloat {
+			return v.float >= minFloat && v.float <= maxFloat, nil
+		}
+		return false, IncompatibleTypesRuleError{Rule: r}
+	}, true
+}
+
 func (r *BetweenFV) Tweak(
 	inputDescription *description.Description,
 	stage int,
@@ -116,14 +149,13 @@ func (r *BetweenFV) Tweak(
 		inputDescription.Fields[r.field].MaxDP,
 		stage,
 	)
-	isValidExpr := dexpr.MustNew("pH > pL", dexprfuncs.CallFuncs)
 	for _, pL := range pointsL {
 		for _, pH := range pointsH {
 			vars := map[string]*dlit.Literal{
 				"pL": pL,
 				"pH": pH,
 			}
-			if ok, err := isValidExpr.EvalBool(vars); ok && err == nil {
+			if ok, err := isValidRangeExpr.EvalBool(vars); ok && err == nil {
 				r := MustNewBetweenFV(r.field, pL, pH)
 				rules = append(rules, r)
 			}
@@ -133,10 +165,6 @@ func (r *BetweenFV) Tweak(
 }
 
 func (r *BetweenFV) Overlaps(o Rule) bool {
-	rangeOverlaps := dexpr.MustNew(
-		"((oMin >= min && oMin <= max) || (oMax >= min && oMax <= max))",
-		dexprfuncs.CallFuncs,
-	)
 	switch x := o.(type) {
 	case *BetweenFV:
 		vars := map[string]*dlit.Literal{
@@ -146,7 +174,7 @@ func (r *BetweenFV) Overlaps(o Rule) bool {
 			"max":  r.max,
 		}
 		oField := x.Fields()[0]
-		overlap, err := rangeOverlaps.EvalBool(vars)
+		overlap, err := rangeOverlapsExpr.EvalBool(vars)
 		if err != nil {
 			panic(err)
 		}
@@ -159,16 +187,24 @@ func generateBetweenFV(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
-	rules := make([]Rule, 0)
+	fields := []string{}
 	for _, field := range generationDesc.Fields() {
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("BetweenFV", field) ||
-			fd.Kind != description.Number {
-			continue
+		if useField(generationDesc, "BetweenFV", field) &&
+			fd.Kind == description.Number {
+			fields = append(fields, field)
 		}
+	}
+	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
+	for _, field := range fields {
+		fd := inputDescription.Fields[field]
 		rulesMap := make(map[string]Rule)
-		points := internal.GeneratePoints(fd.Min, fd.Max, fd.MaxDP)
-		isValidExpr := dexpr.MustNew("pH > pL", dexprfuncs.CallFuncs)
+		points := budget.limitPoints(
+			internal.GeneratePoints(fd.Min, fd.Max, fd.MaxDP),
+			len(fields),
+			numPairs,
+		)
 
 		for _, pL := range points {
 			for _, pH := range points {
@@ -176,11 +212,11 @@ func generateBetweenFV(
 					"pL": pL,
 					"pH": pH,
 				}
-				if ok, err := isValidExpr.EvalBool(vars); ok && err == nil {
+				if ok, err := isValidRangeExpr.EvalBool(vars); ok && err == nil {
 					if r, err := NewBetweenFV(field, pL, pH); err == nil {
 						if _, dup := rulesMap[r.String()]; !dup {
 							rulesMap[r.String()] = r
-							rules = append(rules, r)
+							rules = budget.add(rules, r)
 						}
 					}
 				}
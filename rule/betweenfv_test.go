@@ -198,7 +198,7 @@ func TestBetweenFVTweak(t *testing.T) {
 	)
 	for _, c := range cases {
 		description := &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   c.fdMin,
@@ -300,7 +300,7 @@ func TestGenerateBetweenFV(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -318,7 +318,7 @@ func TestGenerateBetweenFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790.73),
@@ -336,7 +336,7 @@ func TestGenerateBetweenFV(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -354,7 +354,7 @@ func TestGenerateBetweenFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(700),
@@ -375,7 +375,7 @@ func TestGenerateBetweenFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790.73),
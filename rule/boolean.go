@@ -0,0 +1,52 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package rule
+
+import (
+	"github.com/vlifesystems/rhkit/description"
+)
+
+func init() {
+	RegisterGenerator("Boolean", generateBoolean)
+}
+
+// generateBoolean generates a single canonical rule for each Boolean
+// field of the form: field == trueValue.  A rule for the false value
+// isn't generated as it would just match the complement of the records.
+func generateBoolean(
+	inputDescription *description.Description,
+	generationDesc GenerationDescriber,
+) []Rule {
+	rules := make([]Rule, 0)
+	for _, field := range generationDesc.Fields() {
+		fd := inputDescription.Fields[field]
+		if !useField(generationDesc, "Boolean", field) ||
+			fd.Kind != description.Boolean {
+			continue
+		}
+		if v, ok := inputDescription.TrueValue(field); ok {
+			rules = append(rules, NewEQFV(field, v))
+		}
+	}
+	return rules
+}
+
+// haveSameKind returns whether two fields are of the same Kind and so can
+// be compared for equality.  Boolean fields must also use the same values
+// for true and false.
+func haveSameKind(
+	inputDescription *description.Description,
+	fieldA string,
+	fieldB string,
+) bool {
+	fdA := inputDescription.Fields[fieldA]
+	fdB := inputDescription.Fields[fieldB]
+	if fdA.Kind != fdB.Kind {
+		return false
+	}
+	if fdA.Kind == description.Boolean {
+		return inputDescription.HaveSameBooleanValues(fieldA, fieldB)
+	}
+	return true
+}
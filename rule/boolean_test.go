@@ -0,0 +1,126 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/description"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+func TestGenerateBoolean(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"default": {
+				Kind: description.Boolean,
+				Values: map[string]description.Value{
+					"no":  {dlit.NewString("no"), 3},
+					"yes": {dlit.NewString("yes"), 2},
+				},
+				NumValues: 2,
+			},
+			"flag": {
+				Kind: description.Boolean,
+				Values: map[string]description.Value{
+					"0": {dlit.NewString("0"), 3},
+					"1": {dlit.NewString("1"), 2},
+				},
+				NumValues: 2,
+			},
+			"loan": {
+				Kind: description.Boolean,
+				Values: map[string]description.Value{
+					"false": {dlit.NewString("false"), 3},
+					"true":  {dlit.NewString("true"), 2},
+				},
+				NumValues: 2,
+			},
+			"group": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"yes": {dlit.NewString("yes"), 3},
+					"no":  {dlit.NewString("no"), 2},
+				},
+				NumValues: 2,
+			},
+		},
+	}
+	want := []Rule{
+		NewEQFV("default", dlit.NewString("yes")),
+		NewEQFV("flag", dlit.NewString("1")),
+	}
+	generationDesc := testhelpers.GenerationDesc{
+		DFields:     []string{"default", "flag", "loan", "group"},
+		DArithmetic: false,
+		DDeny:       map[string][]string{"Boolean": []string{"loan"}},
+	}
+	got := generateBoolean(inputDescription, generationDesc)
+	if err := matchRulesUnordered(got, want); err != nil {
+		t.Errorf("matchRulesUnordered() rules don't match: %s\ngot: %s\nwant: %s\n",
+			err, got, want)
+	}
+}
+
+func TestHaveSameKind(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"default": {
+				Kind: description.Boolean,
+				Values: map[string]description.Value{
+					"no":  {dlit.NewString("no"), 3},
+					"yes": {dlit.NewString("yes"), 2},
+				},
+				NumValues: 2,
+			},
+			"housing": {
+				Kind: description.Boolean,
+				Values: map[string]description.Value{
+					"no":  {dlit.NewString("no"), 1},
+					"yes": {dlit.NewString("yes"), 4},
+				},
+				NumValues: 2,
+			},
+			"flag": {
+				Kind: description.Boolean,
+				Values: map[string]description.Value{
+					"0": {dlit.NewString("0"), 3},
+					"1": {dlit.NewString("1"), 2},
+				},
+				NumValues: 2,
+			},
+			"group": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"yes": {dlit.NewString("yes"), 3},
+					"no":  {dlit.NewString("no"), 2},
+				},
+				NumValues: 2,
+			},
+			"team": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"a": {dlit.NewString("a"), 3},
+					"b": {dlit.NewString("b"), 2},
+				},
+				NumValues: 2,
+			},
+		},
+	}
+	cases := []struct {
+		fieldA string
+		fieldB string
+		want   bool
+	}{
+		{fieldA: "default", fieldB: "housing", want: true},
+		{fieldA: "default", fieldB: "flag", want: false},
+		{fieldA: "default", fieldB: "group", want: false},
+		{fieldA: "group", fieldB: "team", want: true},
+	}
+	for _, c := range cases {
+		got := haveSameKind(inputDescription, c.fieldA, c.fieldB)
+		if got != c.want {
+			t.Errorf("haveSameKind(%s, %s) got: %t, want: %t",
+				c.fieldA, c.fieldB, got, c.want)
+		}
+	}
+}
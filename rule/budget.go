@@ -0,0 +1,258 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package rule
+
+import (
+	"strings"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/description"
+)
+
+// applyBudget removes the rules that can't reach the minimum support and
+// then limits the number of rules per field and in total for a generator,
+// as described by the GenerationDescriber.  Where rules have to be
+// removed, those kept are spread evenly through the rules once sorted.
+// The built-in generators use a ruleBudget to keep within these limits
+// while they enumerate rules, so this mainly affects other generators.
+func applyBudget(
+	inputDescription *description.Description,
+	generationDesc GenerationDescriber,
+	rules []Rule,
+) []Rule {
+	budget, ok := generationDesc.(RuleBudgeter)
+	if !ok {
+		return rules
+	}
+	if minSupport := budget.MinSupport(); minSupport > 0 {
+		supportedRules := make([]Rule, 0, len(rules))
+		for _, r := range rules {
+			n, ok := maxSupport(inputDescription, r)
+			if !ok || n >= minSupport {
+				supportedRules = append(supportedRules, r)
+			}
+		}
+		rules = supportedRules
+	}
+
+	if budget.MaxFieldRules() <= 0 && budget.MaxGeneratorRules() <= 0 {
+		return rules
+	}
+	Sort(rules)
+
+	if maxFieldRules := budget.MaxFieldRules(); maxFieldRules > 0 {
+		fieldsOrder := []string{}
+		fieldsRules := map[string][]Rule{}
+		for _, r := range rules {
+			fields := strings.Join(r.Fields(), ",")
+			if _, ok := fieldsRules[fields]; !ok {
+				fieldsOrder = append(fieldsOrder, fields)
+			}
+			fieldsRules[fields] = append(fieldsRules[fields], r)
+		}
+		rules = make([]Rule, 0, len(rules))
+		for _, fields := range fieldsOrder {
+			fRules := spreadRules(fieldsRules[fields], maxFieldRules)
+			rules = append(rules, fRules...)
+		}
+	}
+
+	if maxRules := budget.MaxGeneratorRules(); maxRules > 0 {
+		rules = spreadRules(rules, maxRules)
+	}
+	return rules
+}
+
+// ruleBudget is used by generators while they enumerate rules so that
+// they don't generate more rules than applyBudget would keep
+type ruleBudget struct {
+	inputDescription  *description.Description
+	maxGeneratorRules int
+	maxFieldRules     int
+	minSupport        int64
+	numRules          int
+}
+
+func newRuleBudget(
+	inputDescription *description.Description,
+	generationDesc GenerationDescriber,
+) *ruleBudget {
+	b := &ruleBudget{inputDescription: inputDescription}
+	if budget, ok := generationDesc.(RuleBudgeter); ok {
+		b.maxGeneratorRules = budget.MaxGeneratorRules()
+		b.maxFieldRules = budget.MaxFieldRules()
+		b.minSupport = budget.MinSupport()
+	}
+	return b
+}
+
+// isSpent returns whether the generator has generated as many rules as
+// it may
+func (b *ruleBudget) isSpent() bool {
+	return b.maxGeneratorRules > 0 && b.numRules >= b.maxGeneratorRules
+}
+
+// add appends r to rules if the budget isn't spent and r can reach the
+// minimum support
+func (b *ruleBudget) add(rules []Rule, r Rule) []Rule {
+	if b.isSpent() {
+		return rules
+	}
+	if b.minSupport > 0 {
+		if n, ok := maxSupport(b.inputDescription, r); ok && n < b.minSupport {
+			return rules
+		}
+	}
+	b.numRules++
+	return append(rules, r)
+}
+
+// fieldLimit returns the maximum number of rules that may be generated
+// for each of numFields fields or combinations of fields, so that the
+// generator's budget is shared between them.  0 means no limit.
+func (b *ruleBudget) fieldLimit(numFields int) int {
+	limit := b.maxFieldRules
+	if b.maxGeneratorRules > 0 && numFields > 0 {
+		share := (b.maxGeneratorRules + numFields - 1) / numFields
+		if limit <= 0 || share < limit {
+			limit = share
+		}
+	}
+	return limit
+}
+
+// limitPoints returns the points spread evenly through points so that
+// the number of rules, given by numRules, that are generated from them for
+// each of numFields fields or combinations of fields stays within the
+// budget.  The points must be sorted.
+func (b *ruleBudget) limitPoints(
+	points []*dlit.Literal,
+	numFields int,
+	numRules func(numPoints int) int,
+) []*dlit.Literal {
+	limit := b.fieldLimit(numFields)
+	if limit <= 0 {
+		return points
+	}
+	n := len(points)
+	for n > 1 && numRules(n) > limit {
+		n--
+	}
+	if n == len(points) {
+		return points
+	}
+	r := make([]*dlit.Literal, n)
+	for i := 0; i < n; i++ {
+		r[i] = points[i*len(points)/n]
+	}
+	return r
+}
+
+// numPairs returns the number of pairs that can be made from n things
+func numPairs(n int) int {
+	return n * (n - 1) / 2
+}
+
+// onePerPoint returns the number of rules generated from numPoints points
+// where one rule is generated for each point
+func onePerPoint(numPoints int) int {
+	return numPoints
+}
+
+// numNumberFields returns the number of Number fields that a generator
+// may use
+func numNumberFields(
+	inputDescription *description.Description,
+	generationDesc GenerationDescriber,
+	generatorName string,
+) int {
+	n := 0
+	for _, field := range generationDesc.Fields() {
+		fd := inputDescription.Fields[field]
+		if useField(generationDesc, generatorName, field) &&
+			fd.Kind == description.Number {
+			n++
+		}
+	}
+	return n
+}
+
+// spreadRules returns up to maxNumRules rules spread evenly through rules
+func spreadRules(rules []Rule, maxNumRules int) []Rule {
+	if len(rules) <= maxNumRules {
+		return rules
+	}
+	r := make([]Rule, maxNumRules)
+	for i := 0; i < maxNumRules; i++ {
+		r[i] = rules[i*len(rules)/maxNumRules]
+	}
+	return r
+}
+
+// maxSupport returns the maximum number of records that a rule could
+// match according to the Description.  This is only known for rules
+// that use a single field whose values have all been counted.
+func maxSupport(
+	inputDescription *description.Description,
+	r Rule,
+) (int64, bool) {
+	fields := r.Fields()
+	if len(fields) != 1 {
+		return 0, false
+	}
+	fd, ok := inputDescription.Fields[fields[0]]
+	if !ok || fd.NumValues == -1 || len(fd.Values) == 0 {
+		return 0, false
+	}
+	support := int64(0)
+	for _, v := range fd.Values {
+		isTrue, err := r.IsTrue(map[string]*dlit.Literal{fields[0]: v.Value})
+		if err != nil {
+			return 0, false
+		}
+		if isTrue {
+			support += int64(v.Num)
+		}
+	}
+	return support, true
+}
+
+// countMaxNumFields returns the maximum number of fields that a count
+// rule may use, so that the number of combinations of fields remains
+// within the generator's budget.  If this is less than 2 then no count
+// rules may be generated.
+func countMaxNumFields(
+	generationDesc GenerationDescriber,
+	numFields int,
+) int {
+	maxRules := 0
+	if budget, ok := generationDesc.(RuleBudgeter); ok {
+		maxRules = budget.MaxGeneratorRules()
+	}
+	if maxRules <= 0 {
+		return 40 / numFields
+	}
+	numCombinations := 0
+	for k := 2; k <= numFields; k++ {
+		numCombinations += numCombinationsOf(numFields, k, maxRules)
+		if numCombinations > maxRules {
+			// If k == 2 then no combinations of fields are within budget
+			return k - 1
+		}
+	}
+	return numFields
+}
+
+// numCombinationsOf returns the number of ways of choosing k from n,
+// stopping early once the result is greater than limit
+func numCombinationsOf(n, k, limit int) int {
+	c := 1
+	for i := 0; i < k; i++ {
+		c = c * (n - i) / (i + 1)
+		if c > limit {
+			return c
+		}
+	}
+	return c
+}
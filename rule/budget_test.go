@@ -0,0 +1,300 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/description"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+var budgetDescription = &description.Description{
+	Fields: map[string]*description.Field{
+		"team": {
+			Kind: description.String,
+			Values: map[string]description.Value{
+				"a": {dlit.NewString("a"), 30},
+				"b": {dlit.NewString("b"), 3},
+				"c": {dlit.NewString("c"), 7},
+			},
+			NumValues: 3,
+		},
+		"level": {
+			Kind:  description.Number,
+			Min:   dlit.MustNew(0),
+			Max:   dlit.MustNew(5),
+			MaxDP: 0,
+			Values: map[string]description.Value{
+				"0": {dlit.NewString("0"), 10},
+				"1": {dlit.NewString("1"), 10},
+				"2": {dlit.NewString("2"), 5},
+				"3": {dlit.NewString("3"), 5},
+				"4": {dlit.NewString("4"), 5},
+				"5": {dlit.NewString("5"), 5},
+			},
+			NumValues: 6,
+		},
+		"income": {
+			Kind:      description.Number,
+			Min:       dlit.MustNew(0),
+			Max:       dlit.MustNew(1000),
+			MaxDP:     0,
+			Values:    map[string]description.Value{},
+			NumValues: -1,
+		},
+	},
+}
+
+func TestMaxSupport(t *testing.T) {
+	cases := []struct {
+		rule   Rule
+		want   int64
+		wantOK bool
+	}{
+		{rule: NewEQFV("team", dlit.MustNew("a")), want: 30, wantOK: true},
+		{rule: NewEQFV("team", dlit.MustNew("z")), want: 0, wantOK: true},
+		{rule: NewNEFV("team", dlit.MustNew("a")), want: 10, wantOK: true},
+		{rule: NewInFV("team", testhelpers.MakeStringsDlitSlice("b", "c")),
+			want: 10, wantOK: true},
+		{rule: NewGEFV("level", dlit.MustNew(2)), want: 20, wantOK: true},
+		{rule: NewLEFV("level", dlit.MustNew(2)), want: 25, wantOK: true},
+		{rule: MustNewBetweenFV("level", dlit.MustNew(1), dlit.MustNew(3)),
+			want: 20, wantOK: true},
+		{rule: NewGEFV("income", dlit.MustNew(2)), wantOK: false},
+		{rule: NewEQFF("team", "level"), wantOK: false},
+		{rule: NewTrue(), wantOK: false},
+	}
+	for _, c := range cases {
+		got, ok := maxSupport(budgetDescription, c.rule)
+		if ok != c.wantOK || got != c.want {
+			t.Errorf("maxSupport(%s) got: %d, %t, want: %d, %t",
+				c.rule, got, ok, c.want, c.wantOK)
+		}
+	}
+}
+
+func TestApplyBudget(t *testing.T) {
+	rules := []Rule{
+		NewEQFV("team", dlit.MustNew("a")),
+		NewEQFV("team", dlit.MustNew("b")),
+		NewEQFV("team", dlit.MustNew("c")),
+		NewGEFV("level", dlit.MustNew(1)),
+		NewGEFV("level", dlit.MustNew(2)),
+		NewGEFV("level", dlit.MustNew(3)),
+		NewGEFV("level", dlit.MustNew(4)),
+		NewGEFV("level", dlit.MustNew(5)),
+		NewGEFV("income", dlit.MustNew(500)),
+	}
+	cases := []struct {
+		generationDesc GenerationDescriber
+		want           []Rule
+	}{
+		{generationDesc: testhelpers.GenerationDesc{},
+			want: rules,
+		},
+		{generationDesc: testhelpers.GenerationDesc{DMinSupport: 8},
+			want: []Rule{
+				NewEQFV("team", dlit.MustNew("a")),
+				NewGEFV("level", dlit.MustNew(1)),
+				NewGEFV("level", dlit.MustNew(2)),
+				NewGEFV("level", dlit.MustNew(3)),
+				NewGEFV("level", dlit.MustNew(4)),
+				NewGEFV("income", dlit.MustNew(500)),
+			},
+		},
+		{generationDesc: testhelpers.GenerationDesc{DMaxFieldRules: 2},
+			want: []Rule{
+				NewEQFV("team", dlit.MustNew("a")),
+				NewEQFV("team", dlit.MustNew("b")),
+				NewGEFV("level", dlit.MustNew(1)),
+				NewGEFV("level", dlit.MustNew(3)),
+				NewGEFV("income", dlit.MustNew(500)),
+			},
+		},
+		{generationDesc: testhelpers.GenerationDesc{DMaxGeneratorRules: 3},
+			want: []Rule{
+				NewGEFV("income", dlit.MustNew(500)),
+				NewGEFV("level", dlit.MustNew(3)),
+				NewEQFV("team", dlit.MustNew("a")),
+			},
+		},
+		{generationDesc: testhelpers.GenerationDesc{
+			DMinSupport:        10,
+			DMaxFieldRules:     1,
+			DMaxGeneratorRules: 2,
+		},
+			want: []Rule{
+				NewGEFV("income", dlit.MustNew(500)),
+				NewGEFV("level", dlit.MustNew(1)),
+			},
+		},
+	}
+	for i, c := range cases {
+		rulesCopy := append([]Rule{}, rules...)
+		got := applyBudget(budgetDescription, c.generationDesc, rulesCopy)
+		if err := matchRulesUnordered(got, c.want); err != nil {
+			t.Errorf("(%d) applyBudget: %s\ngot: %s\nwant: %s",
+				i, err, got, c.want)
+		}
+	}
+}
+
+func TestCountMaxNumFields(t *testing.T) {
+	cases := []struct {
+		maxGeneratorRules int
+		numFields         int
+		want              int
+	}{
+		{maxGeneratorRules: 0, numFields: 4, want: 10},
+		{maxGeneratorRules: 0, numFields: 20, want: 2},
+		{maxGeneratorRules: 1, numFields: 4, want: 1},
+		{maxGeneratorRules: 5, numFields: 4, want: 1},
+		{maxGeneratorRules: 6, numFields: 4, want: 2},
+		{maxGeneratorRules: 10, numFields: 4, want: 3},
+		{maxGeneratorRules: 11, numFields: 4, want: 4},
+		{maxGeneratorRules: 1000, numFields: 20, want: 2},
+		{maxGeneratorRules: 2000, numFields: 20, want: 3},
+	}
+	for _, c := range cases {
+		generationDesc := testhelpers.GenerationDesc{
+			DMaxGeneratorRules: c.maxGeneratorRules,
+		}
+		got := countMaxNumFields(generationDesc, c.numFields)
+		if got != c.want {
+			t.Errorf("countMaxNumFields(%d, %d) got: %d, want: %d",
+				c.maxGeneratorRules, c.numFields, got, c.want)
+		}
+	}
+}
+
+func TestRuleBudgetAdd(t *testing.T) {
+	generationDesc := testhelpers.GenerationDesc{
+		DMinSupport:        10,
+		DMaxGeneratorRules: 2,
+	}
+	budget := newRuleBudget(budgetDescription, generationDesc)
+	rules := []Rule{}
+	for _, r := range []Rule{
+		NewEQFV("team", dlit.MustNew("b")),
+		NewEQFV("team", dlit.MustNew("a")),
+		NewGEFV("income", dlit.MustNew(500)),
+		NewGEFV("level", dlit.MustNew(1)),
+	} {
+		rules = budget.add(rules, r)
+	}
+	want := []Rule{
+		NewEQFV("team", dlit.MustNew("a")),
+		NewGEFV("income", dlit.MustNew(500)),
+	}
+	if err := matchRulesUnordered(rules, want); err != nil {
+		t.Errorf("add: %s\ngot: %s\nwant: %s", err, rules, want)
+	}
+	if !budget.isSpent() {
+		t.Errorf("isSpent got: false, want: true")
+	}
+}
+
+func TestRuleBudgetLimitPoints(t *testing.T) {
+	points := []*dlit.Literal{}
+	for i := 0; i < 10; i++ {
+		points = append(points, dlit.MustNew(i))
+	}
+	cases := []struct {
+		generationDesc GenerationDescriber
+		numFields      int
+		numRules       func(int) int
+		want           []string
+	}{
+		{generationDesc: testhelpers.GenerationDesc{},
+			numFields: 2,
+			numRules:  numPairs,
+			want:      []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		},
+		{generationDesc: testhelpers.GenerationDesc{DMaxFieldRules: 10},
+			numFields: 2,
+			numRules:  numPairs,
+			want:      []string{"0", "2", "4", "6", "8"},
+		},
+		{generationDesc: testhelpers.GenerationDesc{DMaxFieldRules: 4},
+			numFields: 2,
+			numRules:  onePerPoint,
+			want:      []string{"0", "2", "5", "7"},
+		},
+		{generationDesc: testhelpers.GenerationDesc{
+			DMaxFieldRules:     4,
+			DMaxGeneratorRules: 5,
+		},
+			numFields: 2,
+			numRules:  onePerPoint,
+			want:      []string{"0", "3", "6"},
+		},
+	}
+	for i, c := range cases {
+		budget := newRuleBudget(budgetDescription, c.generationDesc)
+		got := budget.limitPoints(points, c.numFields, c.numRules)
+		gotStrings := make([]string, len(got))
+		for j, p := range got {
+			gotStrings[j] = p.String()
+		}
+		if strings.Join(gotStrings, ",") != strings.Join(c.want, ",") {
+			t.Errorf("(%d) limitPoints got: %s, want: %s", i, gotStrings, c.want)
+		}
+	}
+}
+
+func TestGenerateBetweenFV_budget(t *testing.T) {
+	generationDesc := testhelpers.GenerationDesc{
+		DFields:        []string{"level", "income"},
+		DMaxFieldRules: 6,
+		DMinSupport:    20,
+	}
+	got := generateBetweenFV(budgetDescription, generationDesc)
+	numFieldRules := map[string]int{}
+	for _, r := range got {
+		numFieldRules[r.Fields()[0]]++
+		if n, ok := maxSupport(budgetDescription, r); ok && n < 20 {
+			t.Errorf("generateBetweenFV: rule: %s, support: %d < 20", r, n)
+		}
+	}
+	for _, field := range generationDesc.DFields {
+		if n := numFieldRules[field]; n == 0 || n > 6 {
+			t.Errorf("generateBetweenFV: field: %s, number of rules: %d", field, n)
+		}
+	}
+}
+
+func TestGenerateInFV_budget(t *testing.T) {
+	cases := []struct {
+		generationDesc testhelpers.GenerationDesc
+		wantMaxRules   int
+	}{
+		{generationDesc: testhelpers.GenerationDesc{
+			DFields:        []string{"level", "team"},
+			DMaxFieldRules: 4,
+			DMinSupport:    20,
+		},
+			wantMaxRules: 4,
+		},
+		{generationDesc: testhelpers.GenerationDesc{
+			DFields:            []string{"level", "team"},
+			DMaxGeneratorRules: 3,
+		},
+			wantMaxRules: 3,
+		},
+	}
+	for i, c := range cases {
+		got := generateInFV(budgetDescription, c.generationDesc)
+		if len(got) == 0 || len(got) > c.wantMaxRules {
+			t.Errorf("(%d) generateInFV: number of rules: %d, want: 1..%d",
+				i, len(got), c.wantMaxRules)
+		}
+		minSupport := c.generationDesc.DMinSupport
+		for _, r := range got {
+			if n, ok := maxSupport(budgetDescription, r); !ok || n < minSupport {
+				t.Errorf("(%d) generateInFV: rule: %s, support: %d < %d",
+					i, r, n, minSupport)
+			}
+		}
+	}
+}
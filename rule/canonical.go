@@ -0,0 +1,139 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package rule
+
+import (
+	"sort"
+
+	"github.com/lawrencewoodman/dlit"
+)
+
+// Canonical returns the canonical normal form of a rule so that rules
+// which are equivalent, but written differently, have the same String().
+// The rule is simplified, the clauses of And and Or rules are sorted,
+// the fields of commutative rules such as EQFF and Count* are sorted,
+// field comparisons such as GEFF are written with their fields in order,
+// so that b >= a becomes a <= b, and the values of InFV rules are sorted.
+func Canonical(r Rule) Rule {
+	if sr, ok := Simplify(r); ok {
+		r = sr
+	}
+	switch x := r.(type) {
+	case *And:
+		return canonicalClauses(x, true)
+	case *Or:
+		return canonicalClauses(x, false)
+	case *InFV:
+		return canonicalInFV(x)
+	case *EQFF:
+		if x.fieldB < x.fieldA {
+			return NewEQFF(x.fieldB, x.fieldA)
+		}
+	case *NEFF:
+		if x.fieldB < x.fieldA {
+			return NewNEFF(x.fieldB, x.fieldA)
+		}
+	case *GEFF:
+		if x.fieldB < x.fieldA {
+			return NewLEFF(x.fieldB, x.fieldA)
+		}
+	case *LEFF:
+		if x.fieldB < x.fieldA {
+			return NewGEFF(x.fieldB, x.fieldA)
+		}
+	case *GTFF:
+		if x.fieldB < x.fieldA {
+			return NewLTFF(x.fieldB, x.fieldA)
+		}
+	case *LTFF:
+		if x.fieldB < x.fieldA {
+			return NewGTFF(x.fieldB, x.fieldA)
+		}
+	case *AddGEF:
+		if x.fieldB < x.fieldA {
+			return NewAddGEF(x.fieldB, x.fieldA, x.value)
+		}
+	case *AddLEF:
+		if x.fieldB < x.fieldA {
+			return NewAddLEF(x.fieldB, x.fieldA, x.value)
+		}
+	case *MulGEF:
+		if x.fieldB < x.fieldA {
+			return NewMulGEF(x.fieldB, x.fieldA, x.value)
+		}
+	case *MulLEF:
+		if x.fieldB < x.fieldA {
+			return NewMulLEF(x.fieldB, x.fieldA, x.value)
+		}
+	case *CountEQVF:
+		if !sort.StringsAreSorted(x.fields) {
+			return NewCountEQVF(x.value, sortedStrings(x.fields), x.num)
+		}
+	case *CountNEVF:
+		if !sort.StringsAreSorted(x.fields) {
+			return NewCountNEVF(x.value, sortedStrings(x.fields), x.num)
+		}
+	case *CountGTVF:
+		if !sort.StringsAreSorted(x.fields) {
+			return NewCountGTVF(x.value, sortedStrings(x.fields), x.num)
+		}
+	case *CountLTVF:
+		if !sort.StringsAreSorted(x.fields) {
+			return NewCountLTVF(x.value, sortedStrings(x.fields), x.num)
+		}
+	}
+	return r
+}
+
+// canonicalClauses puts each clause of an And or Or rule into canonical
+// form, sorts them and joins them back together
+func canonicalClauses(r Rule, isAnd bool) Rule {
+	clauses := flattenClauses(r, isAnd)
+	for i, c := range clauses {
+		clauses[i] = Canonical(c)
+	}
+	// The canonical clauses may themselves need flattening
+	flatClauses := []Rule{}
+	for _, c := range clauses {
+		flatClauses = append(flatClauses, flattenClauses(c, isAnd)...)
+	}
+	flatClauses = Uniq(flatClauses)
+	Sort(flatClauses)
+	newRule := flatClauses[0]
+	for _, c := range flatClauses[1:] {
+		if isAnd {
+			newRule = &And{ruleA: newRule, ruleB: c}
+		} else {
+			newRule = &Or{ruleA: newRule, ruleB: c}
+		}
+	}
+	return newRule
+}
+
+func canonicalInFV(r *InFV) Rule {
+	valuesMap := make(map[string]*dlit.Literal, len(r.values))
+	keys := make([]string, 0, len(r.values))
+	for _, v := range r.values {
+		if _, ok := valuesMap[v.String()]; !ok {
+			valuesMap[v.String()] = v
+			keys = append(keys, v.String())
+		}
+	}
+	if len(keys) == len(r.values) && sort.StringsAreSorted(keys) {
+		return r
+	}
+	sort.Strings(keys)
+	values := make([]*dlit.Literal, len(keys))
+	for i, k := range keys {
+		values[i] = valuesMap[k]
+	}
+	return NewInFV(r.field, values)
+}
+
+func sortedStrings(s []string) []string {
+	r := make([]string, len(s))
+	copy(r, s)
+	sort.Strings(r)
+	return r
+}
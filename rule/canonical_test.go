@@ -0,0 +1,85 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+func TestCanonical(t *testing.T) {
+	ageGE30 := NewGEFV("age", dlit.MustNew(30))
+	ageLE40 := NewLEFV("age", dlit.MustNew(40))
+	jobA := NewEQFV("job", dlit.MustNew("a"))
+	townX := NewEQFV("town", dlit.MustNew("x"))
+	cases := []struct {
+		in   Rule
+		want string
+	}{
+		{in: jobA, want: "job == \"a\""},
+		{in: &And{ageLE40, ageGE30}, want: "age >= 30 && age <= 40"},
+		{in: MustNewBetweenFV("age", dlit.MustNew(30), dlit.MustNew(40)),
+			want: "age >= 30 && age <= 40",
+		},
+		{in: &And{townX, jobA}, want: "job == \"a\" && town == \"x\""},
+		{in: &Or{townX, jobA}, want: "job == \"a\" || town == \"x\""},
+		{in: &And{townX, &And{jobA, ageGE30}},
+			want: "(age >= 30 && job == \"a\") && town == \"x\"",
+		},
+		{in: &And{&Or{townX, jobA}, ageGE30},
+			want: "age >= 30 && (job == \"a\" || town == \"x\")",
+		},
+		{in: &Or{&Or{townX, jobA}, townX},
+			want: "job == \"a\" || town == \"x\"",
+		},
+		{in: NewInFV("job", testhelpers.MakeStringsDlitSlice("c", "a", "b")),
+			want: "in(job,\"a\",\"b\",\"c\")",
+		},
+		{in: NewInFV("job", testhelpers.MakeStringsDlitSlice("c", "a", "c")),
+			want: "in(job,\"a\",\"c\")",
+		},
+		{in: NewEQFF("town", "job"), want: "job == town"},
+		{in: NewNEFF("town", "job"), want: "job != town"},
+		{in: NewGEFF("town", "job"), want: "job <= town"},
+		{in: NewGEFF("job", "town"), want: "job >= town"},
+		{in: NewLEFF("town", "job"), want: "job >= town"},
+		{in: NewGTFF("town", "job"), want: "job < town"},
+		{in: NewLTFF("town", "job"), want: "job > town"},
+		{in: NewLTFF("job", "town"), want: "job < town"},
+		{in: NewAddGEF("out", "in", dlit.MustNew(5)), want: "in + out >= 5"},
+		{in: NewAddLEF("out", "in", dlit.MustNew(5)), want: "in + out <= 5"},
+		{in: NewMulGEF("out", "in", dlit.MustNew(5)), want: "in * out >= 5"},
+		{in: NewMulLEF("out", "in", dlit.MustNew(5)), want: "in * out <= 5"},
+		{in: NewCountEQVF(dlit.MustNew("yes"), []string{"loan", "housing"}, 1),
+			want: "count(\"yes\", housing, loan) == 1",
+		},
+		{in: NewCountNEVF(dlit.MustNew("yes"), []string{"loan", "housing"}, 1),
+			want: "count(\"yes\", housing, loan) != 1",
+		},
+		{in: NewCountGTVF(dlit.MustNew("yes"), []string{"loan", "housing"}, 1),
+			want: "count(\"yes\", housing, loan) > 1",
+		},
+		{in: NewCountLTVF(dlit.MustNew("yes"), []string{"loan", "housing"}, 1),
+			want: "count(\"yes\", housing, loan) < 1",
+		},
+	}
+	for i, c := range cases {
+		got := Canonical(c.in)
+		if got.String() != c.want {
+			t.Errorf("(%d) Canonical(%s) got: %s, want: %s", i, c.in, got, c.want)
+		}
+	}
+}
+
+func TestCanonical_doesntModify(t *testing.T) {
+	fields := []string{"loan", "housing"}
+	values := testhelpers.MakeStringsDlitSlice("c", "a", "b")
+	Canonical(NewCountEQVF(dlit.MustNew("yes"), fields, 1))
+	Canonical(NewInFV("job", values))
+	if fields[0] != "loan" || fields[1] != "housing" {
+		t.Errorf("Canonical modified fields: %v", fields)
+	}
+	if values[0].String() != "c" || values[1].String() != "a" {
+		t.Errorf("Canonical modified values: %v", values)
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package rule
+
+import (
+	"sort"
+
+	"github.com/lawrencewoodman/ddataset"
+	"github.com/lawrencewoodman/dlit"
+)
+
+// CompiledRule is a rule that has been compiled by Compile so that it
+// can be tested quickly against a DecodedRecord
+type CompiledRule func(record *DecodedRecord) (bool, error)
+
+// Compiler is implemented by rules that can compile themselves to be
+// tested against records decoded by a RecordDecoder.  If the rule can't
+// be compiled then false is returned.
+type Compiler interface {
+	Compile(decoder *RecordDecoder) (CompiledRule, bool)
+}
+
+// RecordDecoder decodes the fields of a record once per record so that
+// compiled rules don't each have to convert the same field values
+type RecordDecoder struct {
+	fields []string
+	index  map[string]int
+}
+
+// DecodedRecord is a record that has been decoded by a RecordDecoder
+type DecodedRecord struct {
+	record ddataset.Record
+	values []decodedValue
+}
+
+type decodedValue struct {
+	exists  bool
+	isInt   bool
+	isFloat bool
+	isErr   bool
+	int     int64
+	float   float64
+	str     string
+}
+
+// NewRecordDecoder returns a RecordDecoder for the supplied fields
+func NewRecordDecoder(fields []string) *RecordDecoder {
+	d := &RecordDecoder{
+		fields: []string{},
+		index:  make(map[string]int, len(fields)),
+	}
+	for _, f := range fields {
+		if _, ok := d.index[f]; !ok {
+			d.index[f] = len(d.fields)
+			d.fields = append(d.fields, f)
+		}
+	}
+	return d
+}
+
+// RulesFields returns the sorted fields used by the supplied rules,
+// which can be used to create a RecordDecoder for them
+func RulesFields(rules []Rule) []string {
+	fieldsMap := map[string]bool{}
+	for _, r := range rules {
+		for _, f := range r.Fields() {
+			fieldsMap[f] = true
+		}
+	}
+	fields := make([]string, 0, len(fieldsMap))
+	for f := range fieldsMap {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Decode converts each field of the record that the RecordDecoder
+// knows about
+func (d *RecordDecoder) Decode(record ddataset.Record) *DecodedRecord {
+	dr := &DecodedRecord{
+		record: record,
+		values: make([]decodedValue, len(d.fields)),
+	}
+	for i, f := range d.fields {
+		l, ok := record[f]
+		if !ok {
+			continue
+		}
+		v := &dr.values[i]
+		v.exists = true
+		v.int, v.isInt = l.Int()
+		v.float, v.isFloat = l.Float()
+		if l.Err() != nil {
+			v.isErr = true
+		} else {
+			v.str = l.String()
+		}
+	}
+	return dr
+}
+
+// Record returns the record that was decoded
+func (dr *DecodedRecord) Record() ddataset.Record {
+	return dr.record
+}
+
+// Compile compiles a rule so that it can be tested against records
+// decoded by the supplied RecordDecoder.  If the rule doesn't implement
+// Compiler or can't be compiled then the returned CompiledRule uses the
+// rule's IsTrue method.
+func Compile(r Rule, decoder *RecordDecoder) CompiledRule {
+	if c, ok := r.(Compiler); ok {
+		if cr, ok := c.Compile(decoder); ok {
+			return cr
+		}
+	}
+	return func(record *DecodedRecord) (bool, error) {
+		return r.IsTrue(record.record)
+	}
+}
+
+// compileCompareFV compiles rules that compare a field to a number
+func compileCompareFV(
+	r Rule,
+	decoder *RecordDecoder,
+	field string,
+	value *dlit.Literal,
+	cmpInt func(int64, int64) bool,
+	cmpFloat func(float64, float64) bool,
+) (CompiledRule, bool) {
+	i, ok := decoder.index[field]
+	if !ok {
+		return nil, false
+	}
+	vInt, vIsInt := value.Int()
+	vFloat, vIsFloat := value.Float()
+	return func(record *DecodedRecord) (bool, error) {
+		lh := &record.values[i]
+		if !lh.exists {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if lh.isInt && vIsInt {
+			return cmpInt(lh.int, vInt), nil
+		}
+		if lh.isFloat && vIsFloat {
+			return cmpFloat(lh.float, vFloat), nil
+		}
+		return false, IncompatibleTypesRuleError{Rule: r}
+	}, true
+}
+
+// compileEqualFV compiles rules that test whether a field is equal
+// to a value, if isEqual is false then it tests whether it is not equal
+func compileEqualFV(
+	r Rule,
+	decoder *RecordDecoder,
+	field string,
+	value *dlit.Literal,
+	isEqual bool,
+) (CompiledRule, bool) {
+	i, ok := decoder.index[field]
+	if !ok {
+		return nil, false
+	}
+	vInt, vIsInt := value.Int()
+	vFloat, vIsFloat := value.Float()
+	vIsErr := value.Err() != nil
+	vStr := value.String()
+	return func(record *DecodedRecord) (bool, error) {
+		lh := &record.values[i]
+		if !lh.exists {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if lh.isInt && vIsInt {
+			return (lh.int == vInt) == isEqual, nil
+		}
+		if lh.isFloat && vIsFloat {
+			return (lh.float == vFloat) == isEqual, nil
+		}
+		if !lh.isErr && !vIsErr {
+			return (lh.str == vStr) == isEqual, nil
+		}
+		return false, IncompatibleTypesRuleError{Rule: r}
+	}, true
+}
+
+// compileCompareFF compiles rules that compare two fields.  If
+// compareStrings is true then fields that aren't numbers are compared
+// as strings using cmpString.
+func compileCompareFF(
+	r Rule,
+	decoder *RecordDecoder,
+	fieldA string,
+	fieldB string,
+	cmpInt func(int64, int64) bool,
+	cmpFloat func(float64, float64) bool,
+	cmpString func(string, string) bool,
+) (CompiledRule, bool) {
+	iA, okA := decoder.index[fieldA]
+	iB, okB := decoder.index[fieldB]
+	if !okA || !okB {
+		return nil, false
+	}
+	return func(record *DecodedRecord) (bool, error) {
+		lh := &record.values[iA]
+		rh := &record.values[iB]
+		if !lh.exists || !rh.exists {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if lh.isInt && rh.isInt {
+			return cmpInt(lh.int, rh.int), nil
+		}
+		if lh.isFloat && rh.isFloat {
+			return cmpFloat(lh.float, rh.float), nil
+		}
+		if cmpString == nil || lh.isErr || rh.isErr {
+			return false, IncompatibleTypesRuleError{Rule: r}
+		}
+		return cmpString(lh.str, rh.str), nil
+	}, true
+}
+
+// compileArithmeticFF compiles rules that compare the result of an
+// arithmetic operation on two fields to a value
+func compileArithmeticFF(
+	r Rule,
+	decoder *RecordDecoder,
+	fieldA string,
+	fieldB string,
+	value *dlit.Literal,
+	opInt func(int64, int64) int64,
+	opFloat func(float64, float64) float64,
+	cmpInt func(int64, int64) bool,
+	cmpFloat func(float64, float64) bool,
+) (CompiledRule, bool) {
+	iA, okA := decoder.index[fieldA]
+	iB, okB := decoder.index[fieldB]
+	if !okA || !okB {
+		return nil, false
+	}
+	vInt, vIsInt := value.Int()
+	vFloat, vIsFloat := value.Float()
+	return func(record *DecodedRecord) (bool, error) {
+		a := &record.values[iA]
+		b := &record.values[iB]
+		if !a.exists || !b.exists {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if a.isInt && b.isInt && vIsInt {
+			return cmpInt(opInt(a.int, b.int), vInt), nil
+		}
+		if a.isFloat && b.isFloat && vIsFloat {
+			return cmpFloat(opFloat(a.float, b.float), vFloat), nil
+		}
+		return false, IncompatibleTypesRuleError{Rule: r}
+	}, true
+}
+
+// compileCountVF compiles rules that compare a count of the number of
+// fields equal to a value with a number
+func compileCountVF(
+	r Rule,
+	decoder *RecordDecoder,
+	value *dlit.Literal,
+	fields []string,
+	num int64,
+	cmp func(int64, int64) bool,
+) (CompiledRule, bool) {
+	indices := make([]int, len(fields))
+	for j, f := range fields {
+		i, ok := decoder.index[f]
+		if !ok {
+			return nil, false
+		}
+		indices[j] = i
+	}
+	vStr := value.String()
+	return func(record *DecodedRecord) (bool, error) {
+		n := int64(0)
+		for _, i := range indices {
+			v := &record.values[i]
+			if !v.exists {
+				return false, InvalidRuleError{Rule: r}
+			}
+			if v.isErr {
+				return false, IncompatibleTypesRuleError{Rule: r}
+			}
+			if v.str == vStr {
+				n++
+			}
+		}
+		return cmp(n, num), nil
+	}, true
+}
+
+// compileLogical compiles And and Or rules
+func compileLogical(
+	r Rule,
+	decoder *RecordDecoder,
+	ruleA Rule,
+	ruleB Rule,
+	isAnd bool,
+) (CompiledRule, bool) {
+	crA := Compile(ruleA, decoder)
+	crB := Compile(ruleB, decoder)
+	return func(record *DecodedRecord) (bool, error) {
+		lh, err := crA(record)
+		if err != nil {
+			return false, InvalidRuleError{Rule: r}
+		}
+		rh, err := crB(record)
+		if err != nil {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if isAnd {
+			return lh && rh, nil
+		}
+		return lh || rh, nil
+	}, true
+}
+
+func eqInt(a, b int64) bool { return a == b }
+func neInt(a, b int64) bool { return a != b }
+func geInt(a, b int64) bool { return a >= b }
+func gtInt(a, b int64) bool { return a > b }
+func leInt(a, b int64) bool { return a <= b }
+func ltInt(a, b int64) bool { return a < b }
+
+func eqFloat(a, b float64) bool { return a == b }
+func neFloat(a, b float64) bool { return a != b }
+func geFloat(a, b float64) bool { return a >= b }
+func gtFloat(a, b float64) bool { return a > b }
+func leFloat(a, b float64) bool { return a <= b }
+func ltFloat(a, b float64) bool { return a < b }
+
+func eqString(a, b string) bool { return a == b }
+func neString(a, b string) bool { return a != b }
+
+func addInt(a, b int64) int64       { return a + b }
+func mulInt(a, b int64) int64       { return a * b }
+func addFloat(a, b float64) float64 { return a + b }
+func mulFloat(a, b float64) float64 { return a * b }
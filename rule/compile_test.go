@@ -0,0 +1,120 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lawrencewoodman/ddataset"
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+func TestCompile(t *testing.T) {
+	rules := []Rule{
+		NewTrue(),
+		NewEQFV("band", dlit.MustNew(4)),
+		NewEQFV("team", dlit.MustNew("a")),
+		NewNEFV("band", dlit.MustNew(4.5)),
+		NewNEFV("team", dlit.MustNew("a")),
+		NewGEFV("band", dlit.MustNew(4)),
+		NewGEFV("flow", dlit.MustNew(2.5)),
+		NewLEFV("band", dlit.MustNew(4)),
+		NewLEFV("flow", dlit.MustNew(2.5)),
+		MustNewBetweenFV("band", dlit.MustNew(3), dlit.MustNew(5)),
+		MustNewBetweenFV("flow", dlit.MustNew(1.5), dlit.MustNew(2.7)),
+		MustNewOutsideFV("band", dlit.MustNew(3), dlit.MustNew(5)),
+		MustNewOutsideFV("flow", dlit.MustNew(1.5), dlit.MustNew(2.7)),
+		NewInFV("team", testhelpers.MakeStringsDlitSlice("a", "c")),
+		NewEQFF("band", "flow"),
+		NewEQFF("team", "group"),
+		NewNEFF("band", "flow"),
+		NewNEFF("team", "group"),
+		NewGEFF("band", "flow"),
+		NewGTFF("band", "flow"),
+		NewLEFF("band", "flow"),
+		NewLTFF("band", "flow"),
+		NewLTFF("team", "group"),
+		NewAddGEF("band", "flow", dlit.MustNew(6)),
+		NewAddLEF("band", "flow", dlit.MustNew(6)),
+		NewMulGEF("band", "flow", dlit.MustNew(8)),
+		NewMulLEF("band", "flow", dlit.MustNew(8)),
+		NewCountEQVF(dlit.MustNew("a"), []string{"team", "group"}, 1),
+		NewCountNEVF(dlit.MustNew("a"), []string{"team", "group"}, 1),
+		NewCountGTVF(dlit.MustNew("a"), []string{"team", "group"}, 0),
+		NewCountLTVF(dlit.MustNew("a"), []string{"team", "group"}, 2),
+		MustNewAnd(NewEQFV("team", dlit.MustNew("a")), NewGEFV("band", dlit.MustNew(4))),
+		MustNewOr(NewEQFV("team", dlit.MustNew("a")), NewGEFV("band", dlit.MustNew(4))),
+		MustNewDynamic("band > 3 && team == \"a\""),
+	}
+	records := []ddataset.Record{
+		{
+			"band":  dlit.MustNew(4),
+			"flow":  dlit.MustNew(2.5),
+			"team":  dlit.MustNew("a"),
+			"group": dlit.MustNew("a"),
+		},
+		{
+			"band":  dlit.MustNew(2),
+			"flow":  dlit.MustNew(2),
+			"team":  dlit.MustNew("b"),
+			"group": dlit.MustNew("a"),
+		},
+		{
+			"band":  dlit.MustNew(7),
+			"flow":  dlit.MustNew(1.2),
+			"team":  dlit.MustNew("c"),
+			"group": dlit.MustNew("c"),
+		},
+		{
+			"band":  dlit.MustNew("high"),
+			"flow":  dlit.MustNew(errors.New("bad value")),
+			"team":  dlit.MustNew(errors.New("bad value")),
+			"group": dlit.MustNew(4),
+		},
+		{
+			"band": dlit.MustNew(5),
+			"team": dlit.MustNew("a"),
+		},
+	}
+	decoders := []*RecordDecoder{
+		NewRecordDecoder(RulesFields(rules)),
+		// Missing fields so that rules can't be compiled
+		NewRecordDecoder([]string{"band"}),
+	}
+	for i, decoder := range decoders {
+		for _, r := range rules {
+			cr := Compile(r, decoder)
+			for _, record := range records {
+				want, wantErr := r.IsTrue(record)
+				got, err := cr(decoder.Decode(record))
+				if got != want || fmt.Sprint(err) != fmt.Sprint(wantErr) {
+					t.Errorf("(%d) Compile(%s) record: %v, got: %t, err: %v, want: %t, err: %v",
+						i, r, record, got, err, want, wantErr)
+				}
+			}
+		}
+	}
+}
+
+func TestRulesFields(t *testing.T) {
+	rules := []Rule{
+		NewEQFV("team", dlit.MustNew("a")),
+		NewGEFF("flow", "band"),
+		NewGEFV("band", dlit.MustNew(4)),
+	}
+	want := []string{"band", "flow", "team"}
+	got := RulesFields(rules)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("RulesFields got: %v, want: %v", got, want)
+	}
+}
+
+func TestDecodedRecordRecord(t *testing.T) {
+	record := ddataset.Record{"band": dlit.MustNew(4)}
+	decoder := NewRecordDecoder([]string{"band", "band", "flow"})
+	got := decoder.Decode(record).Record()
+	if len(got) != 1 || got["band"] != record["band"] {
+		t.Errorf("Record got: %v, want: %v", got, record)
+	}
+}
@@ -22,7 +22,7 @@ type CountEQVF struct {
 }
 
 func init() {
-	registerGenerator("CountEQVF", generateCountEQVF)
+	RegisterGenerator("CountEQVF", generateCountEQVF)
 }
 
 func NewCountEQVF(value *dlit.Literal, fields []string, num int64) *CountEQVF {
@@ -41,6 +41,14 @@ func (r *CountEQVF) Fields() []string {
 	return r.fields
 }
 
+func (r *CountEQVF) Complexity() int {
+	return len(r.fields) + 1
+}
+
+func (r *CountEQVF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCountVF(r, decoder, r.value, r.fields, r.num, eqInt)
+}
+
 func (r *CountEQVF) IsTrue(record ddataset.Record) (bool, error) {
 	n := int64(0)
 	for _, f := range r.fields {
@@ -65,9 +73,10 @@ func generateCountEQVF(
 	validFields := []string{}
 	for _, f := range generationDesc.Fields() {
 		fd := inputDescription.Fields[f]
-		if !generationDesc.Deny("CountEQFV", f) &&
+		if useField(generationDesc, "CountEQVF", f) &&
 			fd.NumValues >= 2 && fd.NumValues <= 4 &&
-			(fd.Kind == description.String || fd.Kind == description.Number) {
+			(fd.Kind == description.String || fd.Kind == description.Number ||
+				fd.Kind == description.Boolean) {
 			validFields = append(validFields, f)
 		}
 	}
@@ -132,7 +141,11 @@ func generateCountEQVF(
 	}
 
 	rules := make([]Rule, 0)
-	maxNumFields := 40.0 / len(possibleFields)
+	maxNumFields :=
+		countMaxNumFields(generationDesc, len(possibleFields))
+	if maxNumFields < 2 {
+		return rules
+	}
 	for _, v := range possibleValues {
 		for _, fields := range stringCombinations(possibleFields, 2, maxNumFields) {
 			if isValueInAllFields(v, fields) {
@@ -396,7 +396,7 @@ var wantGenerateCountEQVFRules = []Rule{
 
 func TestGenerateCountEQVF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -497,7 +497,7 @@ func TestGenerateCountEQVF(t *testing.T) {
 // Test that will generate correct number of fields
 func TestGenerateCountEQVF_num_fields(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -741,6 +741,15 @@ func TestGenerateCountEQVF_num_fields(t *testing.T) {
 			wantMaxNumRules:  9,
 			wantMaxNumFields: 2,
 		},
+		{generationDesc: testhelpers.GenerationDesc{
+			DFields:     []string{"groupA", "groupC", "groupG"},
+			DArithmetic: false,
+			DDeny:       map[string][]string{"CountEQVF": []string{"groupG"}},
+		},
+			wantMinNumRules:  9,
+			wantMaxNumRules:  9,
+			wantMaxNumFields: 2,
+		},
 		{generationDesc: testhelpers.GenerationDesc{
 			DFields: []string{"groupA", "groupC", "groupG", "groupH",
 				"groupI", "groupJ"},
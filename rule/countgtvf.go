@@ -22,7 +22,7 @@ type CountGTVF struct {
 }
 
 func init() {
-	registerGenerator("CountGTVF", generateCountGTVF)
+	RegisterGenerator("CountGTVF", generateCountGTVF)
 }
 
 func NewCountGTVF(value *dlit.Literal, fields []string, num int64) *CountGTVF {
@@ -41,6 +41,14 @@ func (r *CountGTVF) Fields() []string {
 	return r.fields
 }
 
+func (r *CountGTVF) Complexity() int {
+	return len(r.fields) + 1
+}
+
+func (r *CountGTVF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCountVF(r, decoder, r.value, r.fields, r.num, gtInt)
+}
+
 func (r *CountGTVF) IsTrue(record ddataset.Record) (bool, error) {
 	n := int64(0)
 	for _, f := range r.fields {
@@ -65,7 +73,7 @@ func generateCountGTVF(
 	validFields := []string{}
 	for _, f := range generationDesc.Fields() {
 		fd := inputDescription.Fields[f]
-		if !generationDesc.Deny("CountGTFV", f) &&
+		if useField(generationDesc, "CountGTVF", f) &&
 			fd.NumValues >= 2 && fd.NumValues <= 4 &&
 			(fd.Kind == description.String || fd.Kind == description.Number) {
 			validFields = append(validFields, f)
@@ -132,7 +140,11 @@ func generateCountGTVF(
 	}
 
 	rules := make([]Rule, 0)
-	maxNumFields := 40.0 / len(possibleFields)
+	maxNumFields :=
+		countMaxNumFields(generationDesc, len(possibleFields))
+	if maxNumFields < 2 {
+		return rules
+	}
 	for _, v := range possibleValues {
 		for _, fields := range stringCombinations(possibleFields, 2, maxNumFields) {
 			if isValueInAllFields(v, fields) {
@@ -273,7 +273,7 @@ var wantGenerateCountGTVFRules = []Rule{
 
 func TestGenerateCountGTVF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -374,7 +374,7 @@ func TestGenerateCountGTVF(t *testing.T) {
 // Test that will generate correct number of fields
 func TestGenerateCountGTVF_num_fields(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -618,6 +618,15 @@ func TestGenerateCountGTVF_num_fields(t *testing.T) {
 			wantMaxNumRules:  3,
 			wantMaxNumFields: 2,
 		},
+		{generationDesc: testhelpers.GenerationDesc{
+			DFields:     []string{"groupA", "groupC", "groupG"},
+			DArithmetic: false,
+			DDeny:       map[string][]string{"CountGTVF": []string{"groupG"}},
+		},
+			wantMinNumRules:  3,
+			wantMaxNumRules:  3,
+			wantMaxNumFields: 2,
+		},
 		{generationDesc: testhelpers.GenerationDesc{
 			DFields: []string{"groupA", "groupC", "groupG", "groupH",
 				"groupI", "groupJ"},
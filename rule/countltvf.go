@@ -22,7 +22,7 @@ type CountLTVF struct {
 }
 
 func init() {
-	registerGenerator("CountLTVF", generateCountLTVF)
+	RegisterGenerator("CountLTVF", generateCountLTVF)
 }
 
 func NewCountLTVF(value *dlit.Literal, fields []string, num int64) *CountLTVF {
@@ -41,6 +41,14 @@ func (r *CountLTVF) Fields() []string {
 	return r.fields
 }
 
+func (r *CountLTVF) Complexity() int {
+	return len(r.fields) + 1
+}
+
+func (r *CountLTVF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCountVF(r, decoder, r.value, r.fields, r.num, ltInt)
+}
+
 func (r *CountLTVF) IsTrue(record ddataset.Record) (bool, error) {
 	n := int64(0)
 	for _, f := range r.fields {
@@ -65,7 +73,7 @@ func generateCountLTVF(
 	validFields := []string{}
 	for _, f := range generationDesc.Fields() {
 		fd := inputDescription.Fields[f]
-		if !generationDesc.Deny("CountLTVF", f) &&
+		if useField(generationDesc, "CountLTVF", f) &&
 			fd.NumValues >= 2 && fd.NumValues <= 4 &&
 			(fd.Kind == description.String || fd.Kind == description.Number) {
 			validFields = append(validFields, f)
@@ -132,7 +140,11 @@ func generateCountLTVF(
 	}
 
 	rules := make([]Rule, 0)
-	maxNumFields := 40.0 / len(possibleFields)
+	maxNumFields :=
+		countMaxNumFields(generationDesc, len(possibleFields))
+	if maxNumFields < 2 {
+		return rules
+	}
 	for _, v := range possibleValues {
 		for _, fields := range stringCombinations(possibleFields, 2, maxNumFields) {
 			if isValueInAllFields(v, fields) {
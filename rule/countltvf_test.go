@@ -280,7 +280,7 @@ var wantGenerateCountLTVFRules = []Rule{
 
 func TestGenerateCountLTVF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -381,7 +381,7 @@ func TestGenerateCountLTVF(t *testing.T) {
 // Test that will generate correct number of fields
 func TestGenerateCountLTVF_num_fields(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -22,7 +22,7 @@ type CountNEVF struct {
 }
 
 func init() {
-	registerGenerator("CountNEVF", generateCountNEVF)
+	RegisterGenerator("CountNEVF", generateCountNEVF)
 }
 
 func NewCountNEVF(value *dlit.Literal, fields []string, num int64) *CountNEVF {
@@ -41,6 +41,14 @@ func (r *CountNEVF) Fields() []string {
 	return r.fields
 }
 
+func (r *CountNEVF) Complexity() int {
+	return len(r.fields) + 1
+}
+
+func (r *CountNEVF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCountVF(r, decoder, r.value, r.fields, r.num, neInt)
+}
+
 func (r *CountNEVF) IsTrue(record ddataset.Record) (bool, error) {
 	n := int64(0)
 	for _, f := range r.fields {
@@ -65,9 +73,10 @@ func generateCountNEVF(
 	validFields := []string{}
 	for _, f := range generationDesc.Fields() {
 		fd := inputDescription.Fields[f]
-		if !generationDesc.Deny("CountNEVF", f) &&
+		if useField(generationDesc, "CountNEVF", f) &&
 			fd.NumValues >= 2 && fd.NumValues <= 4 &&
-			(fd.Kind == description.String || fd.Kind == description.Number) {
+			(fd.Kind == description.String || fd.Kind == description.Number ||
+				fd.Kind == description.Boolean) {
 			validFields = append(validFields, f)
 		}
 	}
@@ -132,7 +141,11 @@ func generateCountNEVF(
 	}
 
 	rules := make([]Rule, 0)
-	maxNumFields := 40.0 / len(possibleFields)
+	maxNumFields :=
+		countMaxNumFields(generationDesc, len(possibleFields))
+	if maxNumFields < 2 {
+		return rules
+	}
 	for _, v := range possibleValues {
 		for _, fields := range stringCombinations(possibleFields, 2, maxNumFields) {
 			if isValueInAllFields(v, fields) {
@@ -396,7 +396,7 @@ var wantGenerateCountNEVFRules = []Rule{
 
 func TestGenerateCountNEVF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -497,7 +497,7 @@ func TestGenerateCountNEVF(t *testing.T) {
 // Test that will generate correct number of fields
 func TestGenerateCountNEVF_num_fields(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
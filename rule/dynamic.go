@@ -4,6 +4,10 @@
 package rule
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+
 	"github.com/lawrencewoodman/ddataset"
 	"github.com/lawrencewoodman/dexpr"
 	"github.com/vlifesystems/rhkit/internal/dexprfuncs"
@@ -12,7 +16,8 @@ import (
 // Dynamic represents a rule determining if supplied dynamic expression is
 // true for a record
 type Dynamic struct {
-	dexpr *dexpr.Expr
+	dexpr      *dexpr.Expr
+	complexity int
 }
 
 func NewDynamic(expr string) (Rule, error) {
@@ -20,7 +25,7 @@ func NewDynamic(expr string) (Rule, error) {
 	if err != nil {
 		return nil, InvalidExprError{Expr: expr}
 	}
-	return &Dynamic{dexpr: dexpr}, nil
+	return &Dynamic{dexpr: dexpr, complexity: exprComplexity(expr)}, nil
 }
 
 func MakeDynamicRules(exprs []string) ([]Rule, error) {
@@ -55,3 +60,71 @@ func (r *Dynamic) IsTrue(record ddataset.Record) (bool, error) {
 func (r *Dynamic) Fields() []string {
 	return []string{}
 }
+
+func (r *Dynamic) Complexity() int {
+	return r.complexity
+}
+
+// exprComplexity returns the complexity of an expression scored in the
+// same way as the other rules.  Each comparison scores one for each field
+// and arithmetic operation that it uses, in() and ni() score the number of
+// values in their set and && and || score one more than their operands.
+func exprComplexity(expr string) int {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return 1
+	}
+	return nodeComplexity(node)
+}
+
+func nodeComplexity(node ast.Expr) int {
+	switch n := node.(type) {
+	case *ast.ParenExpr:
+		return nodeComplexity(n.X)
+	case *ast.UnaryExpr:
+		return nodeComplexity(n.X)
+	case *ast.BinaryExpr:
+		if n.Op == token.LAND || n.Op == token.LOR {
+			return nodeComplexity(n.X) + nodeComplexity(n.Y) + 1
+		}
+	case *ast.CallExpr:
+		if id, ok := n.Fun.(*ast.Ident); ok &&
+			(id.Name == "in" || id.Name == "ni") && len(n.Args) >= 2 {
+			return len(n.Args) - 1
+		}
+	}
+	if c := numFieldsAndOps(node); c > 1 {
+		return c
+	}
+	return 1
+}
+
+// numFieldsAndOps returns the number of variables and arithmetic
+// operations in an expression
+func numFieldsAndOps(node ast.Expr) int {
+	switch n := node.(type) {
+	case *ast.Ident:
+		if n.Name == "true" || n.Name == "false" {
+			return 0
+		}
+		return 1
+	case *ast.ParenExpr:
+		return numFieldsAndOps(n.X)
+	case *ast.UnaryExpr:
+		return numFieldsAndOps(n.X)
+	case *ast.BinaryExpr:
+		c := numFieldsAndOps(n.X) + numFieldsAndOps(n.Y)
+		switch n.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+			c++
+		}
+		return c
+	case *ast.CallExpr:
+		c := 0
+		for _, arg := range n.Args {
+			c += numFieldsAndOps(arg)
+		}
+		return c
+	}
+	return 0
+}
@@ -15,7 +15,7 @@ type EQFF struct {
 }
 
 func init() {
-	registerGenerator("EQFF", generateEQFF)
+	RegisterGenerator("EQFF", generateEQFF)
 }
 
 func NewEQFF(fieldA, fieldB string) Rule {
@@ -64,25 +64,43 @@ func (r *EQFF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *EQFF) Complexity() int {
+	return 2
+}
+
+func (r *EQFF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFF(r, decoder, r.fieldA, r.fieldB,
+		eqInt, eqFloat, eqString)
+}
+
 func generateEQFF(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("EQFF", field) || (fd.Kind != description.String && fd.Kind != description.Number) {
+		if !useField(generationDesc, "EQFF", field) || (fd.Kind != description.String && fd.Kind != description.Number &&
+			fd.Kind != description.Boolean) {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 		for _, oField := range generationDesc.Fields() {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
 			oFd := inputDescription.Fields[oField]
-			if !generationDesc.Deny("EQFF", oField) && oFd.Kind == fd.Kind {
+			if useField(generationDesc, "EQFF", oField) &&
+				haveSameKind(inputDescription, field, oField) {
 				oFieldNum := description.CalcFieldNum(inputDescription.Fields, oField)
 				numSharedValues := calcNumSharedValues(fd, oFd)
 				if fieldNum < oFieldNum && numSharedValues >= 2 {
 					r := NewEQFF(field, oField)
-					rules = append(rules, r)
+					rules = budget.add(rules, r)
 				}
 			}
 		}
@@ -111,7 +111,7 @@ func TestEQFFFields(t *testing.T) {
 
 func TestGenerateEQFF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"bandA": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -225,7 +225,7 @@ func TestGenerateEQFF(t *testing.T) {
 
 func TestGenerateEQFF_deny(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"bandA": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -333,3 +333,61 @@ func TestGenerateEQFF_deny(t *testing.T) {
 			err, got, want)
 	}
 }
+
+func TestGenerateEQFF_correlated(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"groupA": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"Nelson": {dlit.NewString("Nelson"), 3},
+					"Drake":  {dlit.NewString("Drake"), 2},
+				},
+				NumValues: 2,
+			},
+			"groupB": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"Nelson": {dlit.NewString("Nelson"), 3},
+					"Drake":  {dlit.NewString("Drake"), 2},
+				},
+				NumValues: 2,
+			},
+			"groupC": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"Nelson": {dlit.NewString("Nelson"), 2},
+					"Drake":  {dlit.NewString("Drake"), 3},
+				},
+				NumValues: 2,
+			},
+		},
+		Correlations: []*description.Correlation{
+			{
+				FieldA: "groupA",
+				FieldB: "groupB",
+				Kind:   description.CramersV,
+				Value:  0.98,
+			},
+			{
+				FieldA: "groupA",
+				FieldB: "groupC",
+				Kind:   description.CramersV,
+				Value:  0.2,
+			},
+		},
+	}
+	want := []Rule{
+		NewEQFF("groupA", "groupC"),
+		NewEQFF("groupB", "groupC"),
+	}
+	generationDesc := testhelpers.GenerationDesc{
+		DFields:     []string{"groupA", "groupB", "groupC"},
+		DArithmetic: false,
+	}
+	got := generateEQFF(inputDescription, generationDesc)
+	if err := matchRulesUnordered(got, want); err != nil {
+		t.Errorf("matchRulesUnordered() rules don't match: %s\ngot: %s\nwant: %s\n",
+			err, got, want)
+	}
+}
@@ -17,7 +17,7 @@ type EQFV struct {
 }
 
 func init() {
-	registerGenerator("EQFV", generateEQFV)
+	RegisterGenerator("EQFV", generateEQFV)
 }
 
 func NewEQFV(field string, value *dlit.Literal) Rule {
@@ -58,6 +58,14 @@ func (r *EQFV) Fields() []string {
 	return []string{r.field}
 }
 
+func (r *EQFV) Complexity() int {
+	return 1
+}
+
+func (r *EQFV) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileEqualFV(r, decoder, r.field, r.value, true)
+}
+
 func generateEQFV(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
@@ -66,8 +74,9 @@ func generateEQFV(
 	for _, field := range generationDesc.Fields() {
 		fd := inputDescription.Fields[field]
 		values := fd.Values
-		if !generationDesc.Deny("EQFV", field) &&
-			len(values) >= 2 && fd.Kind != description.Ignore {
+		if useField(generationDesc, "EQFV", field) &&
+			len(values) >= 2 && fd.Kind != description.Ignore &&
+			fd.Kind != description.Boolean {
 			for _, vd := range values {
 				if vd.Num >= 2 {
 					r := NewEQFV(field, vd.Value)
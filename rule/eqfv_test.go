@@ -122,7 +122,7 @@ func TestEQFVFields(t *testing.T) {
 
 func TestGenerateEQFV(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"bandA": {
 				Kind: description.Number,
 				Min:  dlit.MustNew(1),
@@ -174,6 +174,13 @@ func TestGenerateEQFV(t *testing.T) {
 					"May": {dlit.NewString("May"), 3},
 				},
 			},
+			"success": {
+				Kind: description.Boolean,
+				Values: map[string]description.Value{
+					"yes": {dlit.NewString("yes"), 3},
+					"no":  {dlit.NewString("no"), 2},
+				},
+			},
 		},
 	}
 	want := []Rule{
@@ -188,7 +195,9 @@ func TestGenerateEQFV(t *testing.T) {
 		NewEQFV("group", dlit.MustNew("Drake")),
 	}
 	generationDesc := testhelpers.GenerationDesc{
-		DFields:     []string{"bandA", "bandB", "flow", "group", "month"},
+		DFields: []string{
+			"bandA", "bandB", "flow", "group", "month", "success",
+		},
 		DArithmetic: false,
 		DDeny:       map[string][]string{"EQFV": []string{"bandB"}},
 	}
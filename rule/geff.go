@@ -15,7 +15,7 @@ type GEFF struct {
 }
 
 func init() {
-	registerGenerator("GEFF", generateGEFF)
+	RegisterGenerator("GEFF", generateGEFF)
 }
 
 func NewGEFF(fieldA, fieldB string) Rule {
@@ -55,19 +55,35 @@ func (r *GEFF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *GEFF) Complexity() int {
+	return 2
+}
+
+func (r *GEFF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFF(r, decoder, r.fieldA, r.fieldB,
+		geInt, geFloat, nil)
+}
+
 func generateGEFF(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("GEFF", field) || fd.Kind != description.Number {
+		if !useField(generationDesc, "GEFF", field) || fd.Kind != description.Number {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 		for _, oField := range generationDesc.Fields() {
-			if generationDesc.Deny("GEFF", oField) {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
+			if !useField(generationDesc, "GEFF", oField) {
 				continue
 			}
 			oFd := inputDescription.Fields[oField]
@@ -75,7 +91,7 @@ func generateGEFF(
 			isComparable := hasComparableNumberRange(fd, oFd)
 			if fieldNum < oFieldNum && isComparable {
 				r := NewGEFF(field, oField)
-				rules = append(rules, r)
+				rules = budget.add(rules, r)
 			}
 		}
 	}
@@ -18,7 +18,7 @@ type GEFV struct {
 }
 
 func init() {
-	registerGenerator("GEFV", generateGEFV)
+	RegisterGenerator("GEFV", generateGEFV)
 }
 
 func NewGEFV(field string, value *dlit.Literal) *GEFV {
@@ -76,6 +76,14 @@ func (r *GEFV) Fields() []string {
 	return []string{r.field}
 }
 
+func (r *GEFV) Complexity() int {
+	return 1
+}
+
+func (r *GEFV) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFV(r, decoder, r.field, r.value, geInt, geFloat)
+}
+
 func (r *GEFV) Overlaps(o Rule) bool {
 	switch x := o.(type) {
 	case *GEFV:
@@ -97,7 +105,7 @@ func generateGEFV(
 ) []Rule {
 	rules := make([]Rule, 0)
 	for _, field := range generationDesc.Fields() {
-		if generationDesc.Deny("GEFV", field) {
+		if !useField(generationDesc, "GEFV", field) {
 			continue
 		}
 		fd := inputDescription.Fields[field]
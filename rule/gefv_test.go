@@ -117,7 +117,7 @@ func TestGEFVTweak(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -135,7 +135,7 @@ func TestGEFVTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790),
@@ -153,7 +153,7 @@ func TestGEFVTweak(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -171,7 +171,7 @@ func TestGEFVTweak(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -189,7 +189,7 @@ func TestGEFVTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -276,7 +276,7 @@ func TestGenerateGEFV(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -294,7 +294,7 @@ func TestGenerateGEFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -313,7 +313,7 @@ func TestGenerateGEFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790.73),
@@ -331,7 +331,7 @@ func TestGenerateGEFV(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -349,7 +349,7 @@ func TestGenerateGEFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
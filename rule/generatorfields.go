@@ -0,0 +1,134 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package rule
+
+import (
+	"path"
+	"sort"
+)
+
+// generatorGroups maps the built-in generators to the group they are in
+var generatorGroups = map[string]string{
+	"Boolean":   "comparison",
+	"EQFF":      "comparison",
+	"EQFV":      "comparison",
+	"GEFF":      "comparison",
+	"GTFF":      "comparison",
+	"LEFF":      "comparison",
+	"LTFF":      "comparison",
+	"NEFF":      "comparison",
+	"NEFV":      "comparison",
+	"BetweenFV": "range",
+	"GEFV":      "range",
+	"LEFV":      "range",
+	"OutsideFV": "range",
+	"InFV":      "set",
+	"AddGEF":    "arithmetic",
+	"AddLEF":    "arithmetic",
+	"MulGEF":    "arithmetic",
+	"MulLEF":    "arithmetic",
+	"CountEQVF": "count",
+	"CountGTVF": "count",
+	"CountLTVF": "count",
+	"CountNEVF": "count",
+}
+
+// generatorAliases maps old generator names, which may still be used in
+// patterns, to the names that the generators are registered with
+var generatorAliases = map[string]string{
+	"CountEQFV": "CountEQVF",
+	"CountGTFV": "CountGTVF",
+}
+
+// GeneratorGroups returns the sorted names of the generator groups
+func GeneratorGroups() []string {
+	seen := map[string]bool{}
+	groups := []string{}
+	for _, g := range generatorGroups {
+		if !seen[g] {
+			seen[g] = true
+			groups = append(groups, g)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// InGeneratorGroup returns whether a generator is in a generator group
+func InGeneratorGroup(group string, generatorName string) bool {
+	g, ok := generatorGroups[generatorName]
+	return ok && g == group
+}
+
+// MatchGenerator returns whether a generator is matched by a pattern,
+// where the pattern may be a generator name, a generator group or a
+// wildcard pattern of generator names.  The old names CountEQFV and
+// CountGTFV are accepted as aliases of CountEQVF and CountGTVF.
+func MatchGenerator(pattern string, generatorName string) bool {
+	if name, ok := generatorAliases[pattern]; ok {
+		pattern = name
+	}
+	return matchPattern(pattern, generatorName) ||
+		InGeneratorGroup(pattern, generatorName)
+}
+
+// GeneratorFields maps generators to fields.  The keys may be generator
+// names, generator groups or wildcard patterns of generator names and the
+// values may be field names or wildcard patterns of field names.  The
+// patterns use the syntax of path.Match, e.g. "Count*" or "income?".
+type GeneratorFields map[string][]string
+
+// Match returns whether a generator and field are matched
+func (gf GeneratorFields) Match(generatorName string, field string) bool {
+	for key, fieldPatterns := range gf {
+		if !MatchGenerator(key, generatorName) {
+			continue
+		}
+		for _, fieldPattern := range fieldPatterns {
+			if matchPattern(fieldPattern, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchField returns whether a field is matched for any generator
+func (gf GeneratorFields) MatchField(field string) bool {
+	for _, fieldPatterns := range gf {
+		for _, fieldPattern := range fieldPatterns {
+			if matchPattern(fieldPattern, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchPattern(pattern string, s string) bool {
+	matched, err := path.Match(pattern, s)
+	return err == nil && matched
+}
+
+// useField returns whether a field may be used by a generator
+func useField(
+	generationDesc GenerationDescriber,
+	generatorName string,
+	field string,
+) bool {
+	if a, ok := generationDesc.(FieldAllower); ok &&
+		!a.Allow(generatorName, field) {
+		return false
+	}
+	if generationDesc.Deny(generatorName, field) {
+		return false
+	}
+	// Deny used to be called with the old names of some generators
+	for oldName, name := range generatorAliases {
+		if name == generatorName && generationDesc.Deny(oldName, field) {
+			return false
+		}
+	}
+	return true
+}
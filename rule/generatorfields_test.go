@@ -0,0 +1,109 @@
+package rule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeneratorGroups(t *testing.T) {
+	want := []string{"arithmetic", "comparison", "count", "range", "set"}
+	got := GeneratorGroups()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GeneratorGroups() got: %s, want: %s", got, want)
+	}
+}
+
+func TestGeneratorGroups_registered(t *testing.T) {
+	for name := range generatorGroups {
+		found := false
+		for _, g := range Generators() {
+			if g == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("generator in group isn't registered: %s", name)
+		}
+	}
+}
+
+func TestMatchGenerator(t *testing.T) {
+	cases := []struct {
+		pattern       string
+		generatorName string
+		want          bool
+	}{
+		{pattern: "EQFV", generatorName: "EQFV", want: true},
+		{pattern: "EQFV", generatorName: "NEFV", want: false},
+		{pattern: "comparison", generatorName: "NEFV", want: true},
+		{pattern: "comparison", generatorName: "InFV", want: false},
+		{pattern: "set", generatorName: "InFV", want: true},
+		{pattern: "Count*", generatorName: "CountLTVF", want: true},
+		{pattern: "CountEQFV", generatorName: "CountEQVF", want: true},
+		{pattern: "CountGTFV", generatorName: "CountGTVF", want: true},
+		{pattern: "CountGTFV", generatorName: "CountEQVF", want: false},
+		{pattern: "*FF", generatorName: "GEFF", want: true},
+		{pattern: "*FF", generatorName: "GEFV", want: false},
+		{pattern: "*", generatorName: "MulLEF", want: true},
+		{pattern: "[", generatorName: "MulLEF", want: false},
+	}
+	for _, c := range cases {
+		got := MatchGenerator(c.pattern, c.generatorName)
+		if got != c.want {
+			t.Errorf("MatchGenerator(%s, %s) got: %t, want: %t",
+				c.pattern, c.generatorName, got, c.want)
+		}
+	}
+}
+
+func TestGeneratorFieldsMatch(t *testing.T) {
+	gf := GeneratorFields{
+		"EQFV":       []string{"job"},
+		"InFV":       []string{"job", "town"},
+		"arithmetic": []string{"income?"},
+		"Count*":     []string{"*"},
+	}
+	cases := []struct {
+		generatorName string
+		field         string
+		want          bool
+	}{
+		{generatorName: "EQFV", field: "job", want: true},
+		{generatorName: "EQFV", field: "town", want: false},
+		{generatorName: "InFV", field: "town", want: true},
+		{generatorName: "NEFV", field: "job", want: false},
+		{generatorName: "AddGEF", field: "incomeA", want: true},
+		{generatorName: "MulLEF", field: "incomeB", want: true},
+		{generatorName: "MulLEF", field: "incomeAB", want: false},
+		{generatorName: "CountEQVF", field: "loan", want: true},
+	}
+	for _, c := range cases {
+		got := gf.Match(c.generatorName, c.field)
+		if got != c.want {
+			t.Errorf("Match(%s, %s) got: %t, want: %t",
+				c.generatorName, c.field, got, c.want)
+		}
+	}
+}
+
+func TestGeneratorFieldsMatchField(t *testing.T) {
+	gf := GeneratorFields{
+		"EQFV":       []string{"job"},
+		"arithmetic": []string{"income?"},
+	}
+	cases := []struct {
+		field string
+		want  bool
+	}{
+		{field: "job", want: true},
+		{field: "incomeA", want: true},
+		{field: "town", want: false},
+	}
+	for _, c := range cases {
+		got := gf.MatchField(c.field)
+		if got != c.want {
+			t.Errorf("MatchField(%s) got: %t, want: %t", c.field, got, c.want)
+		}
+	}
+}
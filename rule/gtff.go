@@ -15,7 +15,7 @@ type GTFF struct {
 }
 
 func init() {
-	registerGenerator("GTFF", generateGTFF)
+	RegisterGenerator("GTFF", generateGTFF)
 }
 
 func NewGTFF(fieldA, fieldB string) Rule {
@@ -55,24 +55,42 @@ func (r *GTFF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *GTFF) Complexity() int {
+	return 2
+}
+
+func (r *GTFF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFF(r, decoder, r.fieldA, r.fieldB,
+		gtInt, gtFloat, nil)
+}
+
 func generateGTFF(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if fd.Kind != description.Number {
+		if !useField(generationDesc, "GTFF", field) ||
+			fd.Kind != description.Number {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 		for _, oField := range generationDesc.Fields() {
+			if !useField(generationDesc, "GTFF", oField) ||
+				isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
 			oFd := inputDescription.Fields[oField]
 			oFieldNum := description.CalcFieldNum(inputDescription.Fields, oField)
 			isComparable := hasComparableNumberRange(fd, oFd)
 			if fieldNum < oFieldNum && isComparable {
 				r := NewGTFF(field, oField)
-				rules = append(rules, r)
+				rules = budget.add(rules, r)
 			}
 		}
 	}
@@ -108,7 +108,7 @@ func TestGTFFFields(t *testing.T) {
 
 func TestGenerateGTFF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -129,6 +129,13 @@ func TestGenerateGTFF(t *testing.T) {
 				MaxDP:  2,
 				Values: map[string]description.Value{},
 			},
+			"flowMid": {
+				Kind:   description.Number,
+				Min:    dlit.MustNew(1),
+				Max:    dlit.MustNew(4),
+				MaxDP:  2,
+				Values: map[string]description.Value{},
+			},
 			"rateIn": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(4.2),
@@ -160,9 +167,10 @@ func TestGenerateGTFF(t *testing.T) {
 		NewGTFF("flowIn", "flowOut"),
 	}
 	generationDesc := testhelpers.GenerationDesc{
-		DFields: []string{"band", "flowIn", "flowOut", "rateIn",
+		DFields: []string{"band", "flowIn", "flowMid", "flowOut", "rateIn",
 			"rateOut", "group"},
 		DArithmetic: false,
+		DDeny:       map[string][]string{"GTFF": []string{"flowMid"}},
 	}
 	got := generateGTFF(inputDescription, generationDesc)
 	if err := matchRulesUnordered(got, want); err != nil {
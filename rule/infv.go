@@ -18,7 +18,7 @@ type InFV struct {
 }
 
 func init() {
-	registerGenerator("InFV", generateInFV)
+	RegisterGenerator("InFV", generateInFV)
 }
 
 func NewInFV(field string, values []*dlit.Literal) *InFV {
@@ -40,6 +40,31 @@ func (r *InFV) Fields() []string {
 	return []string{r.field}
 }
 
+func (r *InFV) Complexity() int {
+	return len(r.values)
+}
+
+func (r *InFV) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	i, ok := decoder.index[r.field]
+	if !ok {
+		return nil, false
+	}
+	values := make(map[string]bool, len(r.values))
+	for _, v := range r.values {
+		values[v.String()] = true
+	}
+	return func(record *DecodedRecord) (bool, error) {
+		needle := &record.values[i]
+		if !needle.exists {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if needle.isErr {
+			return false, IncompatibleTypesRuleError{Rule: r}
+		}
+		return values[needle.str], nil
+	}, true
+}
+
 func (r *InFV) Values() []*dlit.Literal {
 	return r.values
 }
@@ -87,15 +112,27 @@ func generateInFV(
 	if len(generationDesc.Fields()) == 2 {
 		extra += 3
 	}
-	rules := make([]Rule, 0)
+	fields := []string{}
 	for _, field := range generationDesc.Fields() {
 		fd := inputDescription.Fields[field]
 		numValues := len(fd.Values)
-		if generationDesc.Deny("InFV", field) ||
+		if !useField(generationDesc, "InFV", field) ||
 			(fd.Kind != description.String && fd.Kind != description.Number) ||
 			numValues <= 3 || numValues > (12+extra) {
 			continue
 		}
+		fields = append(fields, field)
+	}
+
+	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
+	fieldLimit := budget.fieldLimit(len(fields))
+	for _, field := range fields {
+		if budget.isSpent() {
+			break
+		}
+		fd := inputDescription.Fields[field]
+		numValues := len(fd.Values)
 		possibleLits := possibleValuesToLiterals(fd.Values)
 		maxNumLits := 5 + extra
 		if maxNumLits > numValues-2 {
@@ -104,14 +141,36 @@ func generateInFV(
 			maxNumLits = numValues - 2
 		}
 		litCombinations := literalCombinations(possibleLits, 2, maxNumLits)
+		numFieldRules := 0
 		for _, compareValues := range litCombinations {
+			if budget.isSpent() ||
+				(fieldLimit > 0 && numFieldRules >= fieldLimit) {
+				break
+			}
+			if valuesSupport(fd, compareValues) < budget.minSupport {
+				continue
+			}
+			numRules := len(rules)
 			r := NewInFV(field, compareValues)
-			rules = append(rules, r)
+			rules = budget.add(rules, r)
+			if len(rules) > numRules {
+				numFieldRules++
+			}
 		}
 	}
 	return rules
 }
 
+// valuesSupport returns the number of records that have one of values
+// for the field according to its Description
+func valuesSupport(fd *description.Field, values []*dlit.Literal) int64 {
+	support := int64(0)
+	for _, v := range values {
+		support += int64(fd.Values[v.String()].Num)
+	}
+	return support
+}
+
 func getMaskLiterals(mask string, values []*dlit.Literal) []*dlit.Literal {
 	r := []*dlit.Literal{}
 	for j, b := range mask {
@@ -258,7 +258,7 @@ func TestInFVOverlaps(t *testing.T) {
 
 func TestGenerateInFV(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -473,7 +473,7 @@ func TestGenerateInFV(t *testing.T) {
 // of fields
 func TestGenerateInFV_num_fields(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"group": {
 				Kind: description.String,
 			},
@@ -15,7 +15,7 @@ type LEFF struct {
 }
 
 func init() {
-	registerGenerator("LEFF", generateLEFF)
+	RegisterGenerator("LEFF", generateLEFF)
 }
 
 func NewLEFF(fieldA, fieldB string) Rule {
@@ -55,25 +55,41 @@ func (r *LEFF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *LEFF) Complexity() int {
+	return 2
+}
+
+func (r *LEFF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFF(r, decoder, r.fieldA, r.fieldB,
+		leInt, leFloat, nil)
+}
+
 func generateLEFF(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("LEFF", field) || fd.Kind != description.Number {
+		if !useField(generationDesc, "LEFF", field) || fd.Kind != description.Number {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 		for _, oField := range generationDesc.Fields() {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
 			oFd := inputDescription.Fields[oField]
 			oFieldNum := description.CalcFieldNum(inputDescription.Fields, oField)
 			isComparable := hasComparableNumberRange(fd, oFd)
-			if !generationDesc.Deny("LEFF", oField) &&
+			if useField(generationDesc, "LEFF", oField) &&
 				fieldNum < oFieldNum && isComparable {
 				r := NewLEFF(field, oField)
-				rules = append(rules, r)
+				rules = budget.add(rules, r)
 			}
 		}
 	}
@@ -18,7 +18,7 @@ type LEFV struct {
 }
 
 func init() {
-	registerGenerator("LEFV", generateLEFV)
+	RegisterGenerator("LEFV", generateLEFV)
 }
 
 func NewLEFV(field string, value *dlit.Literal) *LEFV {
@@ -76,6 +76,14 @@ func (r *LEFV) Fields() []string {
 	return []string{r.field}
 }
 
+func (r *LEFV) Complexity() int {
+	return 1
+}
+
+func (r *LEFV) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFV(r, decoder, r.field, r.value, leInt, leFloat)
+}
+
 func (r *LEFV) Overlaps(o Rule) bool {
 	switch x := o.(type) {
 	case *LEFV:
@@ -98,7 +106,7 @@ func generateLEFV(
 	rules := make([]Rule, 0)
 	for _, field := range generationDesc.Fields() {
 		fd := inputDescription.Fields[field]
-		if !generationDesc.Deny("LEFV", field) && fd.Kind == description.Number {
+		if useField(generationDesc, "LEFV", field) && fd.Kind == description.Number {
 			points := internal.GeneratePoints(fd.Min, fd.Max, fd.MaxDP)
 			for _, p := range points {
 				rules = append(rules, NewLEFV(field, p))
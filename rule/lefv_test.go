@@ -117,7 +117,7 @@ func TestLEFVTweak(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -135,7 +135,7 @@ func TestLEFVTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790),
@@ -153,7 +153,7 @@ func TestLEFVTweak(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -171,7 +171,7 @@ func TestLEFVTweak(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -190,7 +190,7 @@ func TestLEFVTweak(t *testing.T) {
 		},
 
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -249,7 +249,7 @@ func TestGenerateLEFV(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -268,7 +268,7 @@ func TestGenerateLEFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790.73),
@@ -287,7 +287,7 @@ func TestGenerateLEFV(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -306,7 +306,7 @@ func TestGenerateLEFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -328,7 +328,7 @@ func TestGenerateLEFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -15,7 +15,7 @@ type LTFF struct {
 }
 
 func init() {
-	registerGenerator("LTFF", generateLTFF)
+	RegisterGenerator("LTFF", generateLTFF)
 }
 
 func NewLTFF(fieldA, fieldB string) Rule {
@@ -55,25 +55,41 @@ func (r *LTFF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *LTFF) Complexity() int {
+	return 2
+}
+
+func (r *LTFF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFF(r, decoder, r.fieldA, r.fieldB,
+		ltInt, ltFloat, nil)
+}
+
 func generateLTFF(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("LTFF", field) || fd.Kind != description.Number {
+		if !useField(generationDesc, "LTFF", field) || fd.Kind != description.Number {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 		for _, oField := range generationDesc.Fields() {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
 			oFd := inputDescription.Fields[oField]
 			oFieldNum := description.CalcFieldNum(inputDescription.Fields, oField)
 			isComparable := hasComparableNumberRange(fd, oFd)
-			if !generationDesc.Deny("LTFF", oField) &&
+			if useField(generationDesc, "LTFF", oField) &&
 				fieldNum < oFieldNum && isComparable {
 				r := NewLTFF(field, oField)
-				rules = append(rules, r)
+				rules = budget.add(rules, r)
 			}
 		}
 	}
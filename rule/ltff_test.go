@@ -108,7 +108,7 @@ func TestLTFFFields(t *testing.T) {
 
 func TestGenerateLTFF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"bandA": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -20,7 +20,7 @@ type MulGEF struct {
 }
 
 func init() {
-	registerGenerator("MulGEF", generateMulGEF)
+	RegisterGenerator("MulGEF", generateMulGEF)
 }
 
 func NewMulGEF(fieldA string, fieldB string, value *dlit.Literal) *MulGEF {
@@ -35,6 +35,15 @@ func (r *MulGEF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *MulGEF) Complexity() int {
+	return 3
+}
+
+func (r *MulGEF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileArithmeticFF(r, decoder, r.fieldA, r.fieldB, r.value,
+		mulInt, mulFloat, geInt, geFloat)
+}
+
 func (r *MulGEF) Value() *dlit.Literal {
 	return r.value
 }
@@ -122,16 +131,25 @@ func generateMulGEF(
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
+	numFieldPairs :=
+		numPairs(numNumberFields(inputDescription, generationDesc, "MulGEF"))
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("MulGEF", field) || !generationDesc.Arithmetic() ||
+		if !useField(generationDesc, "MulGEF", field) || !generationDesc.Arithmetic() ||
 			fd.Kind != description.Number {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 
 		for _, oField := range generationDesc.Fields() {
-			if generationDesc.Deny("MulGEF", oField) {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
+			if !useField(generationDesc, "MulGEF", oField) {
 				continue
 			}
 			oFd := inputDescription.Fields[oField]
@@ -150,10 +168,14 @@ func generateMulGEF(
 				if oFd.MaxDP > maxDP {
 					maxDP = oFd.MaxDP
 				}
-				points := internal.GeneratePoints(min, max, maxDP)
+				points := budget.limitPoints(
+					internal.GeneratePoints(min, max, maxDP),
+					numFieldPairs,
+					onePerPoint,
+				)
 				for _, p := range points {
 					r := NewMulGEF(field, oField, p)
-					rules = append(rules, r)
+					rules = budget.add(rules, r)
 				}
 			}
 		}
@@ -20,7 +20,7 @@ type MulLEF struct {
 }
 
 func init() {
-	registerGenerator("MulLEF", generateMulLEF)
+	RegisterGenerator("MulLEF", generateMulLEF)
 }
 
 func NewMulLEF(fieldA string, fieldB string, value *dlit.Literal) *MulLEF {
@@ -35,6 +35,15 @@ func (r *MulLEF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *MulLEF) Complexity() int {
+	return 3
+}
+
+func (r *MulLEF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileArithmeticFF(r, decoder, r.fieldA, r.fieldB, r.value,
+		mulInt, mulFloat, leInt, leFloat)
+}
+
 func (r *MulLEF) Value() *dlit.Literal {
 	return r.value
 }
@@ -122,16 +131,25 @@ func generateMulLEF(
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
+	numFieldPairs :=
+		numPairs(numNumberFields(inputDescription, generationDesc, "MulLEF"))
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("MulLEF", field) ||
+		if !useField(generationDesc, "MulLEF", field) ||
 			!generationDesc.Arithmetic() || fd.Kind != description.Number {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 
 		for _, oField := range generationDesc.Fields() {
-			if generationDesc.Deny("MulLEF", oField) {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
+			if !useField(generationDesc, "MulLEF", oField) {
 				continue
 			}
 			oFd := inputDescription.Fields[oField]
@@ -149,10 +167,14 @@ func generateMulLEF(
 				if oFd.MaxDP > maxDP {
 					maxDP = oFd.MaxDP
 				}
-				points := internal.GeneratePoints(min, max, maxDP)
+				points := budget.limitPoints(
+					internal.GeneratePoints(min, max, maxDP),
+					numFieldPairs,
+					onePerPoint,
+				)
 				for _, p := range points {
 					r := NewMulLEF(field, oField, p)
-					rules = append(rules, r)
+					rules = budget.add(rules, r)
 				}
 			}
 		}
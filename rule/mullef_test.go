@@ -172,7 +172,7 @@ func TestMulLEFTweak(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -194,7 +194,7 @@ func TestMulLEFTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -216,7 +216,7 @@ func TestMulLEFTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -238,7 +238,7 @@ func TestMulLEFTweak(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(200),
@@ -262,7 +262,7 @@ func TestMulLEFTweak(t *testing.T) {
 			maxDP:       3,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -325,7 +325,7 @@ func TestGenerateMulLEF(t *testing.T) {
 		maxDP          int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -350,7 +350,7 @@ func TestGenerateMulLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(250),
@@ -375,7 +375,7 @@ func TestGenerateMulLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -400,7 +400,7 @@ func TestGenerateMulLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -425,7 +425,7 @@ func TestGenerateMulLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(200.172),
@@ -452,7 +452,7 @@ func TestGenerateMulLEF(t *testing.T) {
 			maxDP:       3,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(1),
@@ -477,7 +477,7 @@ func TestGenerateMulLEF(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"balance": {
 					Kind: description.Number,
 					Min:  dlit.MustNew(200),
@@ -532,7 +532,7 @@ func TestGenerateMulLEF(t *testing.T) {
 
 func TestGenerateMulLEF_multiple_fields(t *testing.T) {
 	description := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"balance": {
 				Kind: description.Number,
 				Min:  dlit.MustNew(250),
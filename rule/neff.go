@@ -15,7 +15,7 @@ type NEFF struct {
 }
 
 func init() {
-	registerGenerator("NEFF", generateNEFF)
+	RegisterGenerator("NEFF", generateNEFF)
 }
 
 func NewNEFF(fieldA, fieldB string) Rule {
@@ -64,28 +64,45 @@ func (r *NEFF) Fields() []string {
 	return []string{r.fieldA, r.fieldB}
 }
 
+func (r *NEFF) Complexity() int {
+	return 2
+}
+
+func (r *NEFF) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileCompareFF(r, decoder, r.fieldA, r.fieldB,
+		neInt, neFloat, neString)
+}
+
 func generateNEFF(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
 	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
 	for _, field := range generationDesc.Fields() {
+		if budget.isSpent() {
+			break
+		}
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("NEFF", field) || (fd.Kind != description.String && fd.Kind != description.Number) {
+		if !useField(generationDesc, "NEFF", field) || (fd.Kind != description.String && fd.Kind != description.Number &&
+			fd.Kind != description.Boolean) {
 			continue
 		}
 		fieldNum := description.CalcFieldNum(inputDescription.Fields, field)
 		for _, oField := range generationDesc.Fields() {
-			if generationDesc.Deny("NEFF", oField) {
+			if isRedundantPair(inputDescription, field, oField) {
+				continue
+			}
+			if !useField(generationDesc, "NEFF", oField) {
 				continue
 			}
 			oFd := inputDescription.Fields[oField]
-			if oFd.Kind == fd.Kind {
+			if haveSameKind(inputDescription, field, oField) {
 				oFieldNum := description.CalcFieldNum(inputDescription.Fields, oField)
 				numSharedValues := calcNumSharedValues(fd, oFd)
 				if fieldNum < oFieldNum && numSharedValues >= 2 {
 					r := NewNEFF(field, oField)
-					rules = append(rules, r)
+					rules = budget.add(rules, r)
 				}
 			}
 		}
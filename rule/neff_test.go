@@ -111,7 +111,7 @@ func TestNEFFFields(t *testing.T) {
 
 func TestGenerateNEFF(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"bandA": {
 				Kind:   description.Number,
 				Min:    dlit.MustNew(1),
@@ -17,7 +17,7 @@ type NEFV struct {
 }
 
 func init() {
-	registerGenerator("NEFV", generateNEFV)
+	RegisterGenerator("NEFV", generateNEFV)
 }
 
 func NewNEFV(field string, value *dlit.Literal) Rule {
@@ -58,6 +58,14 @@ func (r *NEFV) Fields() []string {
 	return []string{r.field}
 }
 
+func (r *NEFV) Complexity() int {
+	return 1
+}
+
+func (r *NEFV) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileEqualFV(r, decoder, r.field, r.value, false)
+}
+
 func generateNEFV(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
@@ -66,7 +74,7 @@ func generateNEFV(
 	for _, field := range generationDesc.Fields() {
 		fd := inputDescription.Fields[field]
 		values := fd.Values
-		if !generationDesc.Deny("NEFV", field) &&
+		if useField(generationDesc, "NEFV", field) &&
 			len(values) > 2 && fd.Kind != description.Ignore {
 			for _, vd := range values {
 				if vd.Num >= 2 {
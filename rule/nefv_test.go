@@ -123,7 +123,7 @@ func TestNEFVFields(t *testing.T) {
 
 func TestGenerateNEFV(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"bandA": {
 				Kind: description.Number,
 				Min:  dlit.MustNew(1),
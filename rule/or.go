@@ -6,7 +6,6 @@ package rule
 import (
 	"fmt"
 	"github.com/lawrencewoodman/ddataset"
-	"github.com/lawrencewoodman/dlit"
 )
 
 // Or represents a rule determining if ruleA OR ruleB
@@ -15,25 +14,27 @@ type Or struct {
 	ruleB Rule
 }
 
+// NewOr returns a rule that is true if either ruleA or ruleB is true.
+// If the rules use the same single field and can be joined into a single
+// rule, then that rule is returned.  An error is returned if either rule
+// is True, if the rules are always true together or if one of the rules
+// makes the other redundant.
 func NewOr(ruleA Rule, ruleB Rule) (Rule, error) {
 	_, ruleAIsTrue := ruleA.(True)
 	_, ruleBIsTrue := ruleB.(True)
 	if ruleAIsTrue || ruleBIsTrue {
 		return nil, fmt.Errorf("can't Or rule: %s, with: %s", ruleA, ruleB)
 	}
-	inRuleA, ruleAIsIn := ruleA.(*InFV)
-	inRuleB, ruleBIsIn := ruleB.(*InFV)
-	if ruleAIsIn && ruleBIsIn {
-		return handleInRules(inRuleA, inRuleB), nil
-	}
-
-	if skip, r := tryJoinRulesWithOutside(ruleA, ruleB); !skip {
-		if r != nil {
+	if isSameSingleField(ruleA, ruleB) {
+		r, result := mergeOrClauses(ruleA, ruleB)
+		if result != notMerged {
+			if result != merged ||
+				r.String() == ruleA.String() || r.String() == ruleB.String() {
+				return nil, fmt.Errorf("can't Or rule: %s, with: %s", ruleA, ruleB)
+			}
 			return r, nil
 		}
-		return nil, fmt.Errorf("can't Or rule: %s, with: %s", ruleA, ruleB)
 	}
-
 	return &Or{ruleA: ruleA, ruleB: ruleB}, nil
 }
 
@@ -45,6 +46,16 @@ func MustNewOr(ruleA Rule, ruleB Rule) Rule {
 	return r
 }
 
+// RuleA returns the first rule of the Or
+func (r *Or) RuleA() Rule {
+	return r.ruleA
+}
+
+// RuleB returns the second rule of the Or
+func (r *Or) RuleB() Rule {
+	return r.ruleB
+}
+
 func (r *Or) String() string {
 	// TODO: Consider making this OR rather than ||
 	aStr := r.ruleA.String()
@@ -102,73 +113,10 @@ func (r *Or) Fields() []string {
 	return results
 }
 
-func tryJoinRulesWithOutside(
-	ruleA Rule,
-	ruleB Rule,
-) (skip bool, newRule Rule) {
-	var r Rule
-	var err error
-
-	if len(ruleA.Fields()) != 1 || len(ruleB.Fields()) != 1 {
-		return true, nil
-	}
-	fieldA := ruleA.Fields()[0]
-	fieldB := ruleB.Fields()[0]
-
-	if fieldA == fieldB {
-		_, ruleAIsBetweenFV := ruleA.(*BetweenFV)
-		_, ruleBIsBetweenFV := ruleB.(*BetweenFV)
-		_, ruleAIsOutsideFV := ruleA.(*OutsideFV)
-		_, ruleBIsOutsideFV := ruleB.(*OutsideFV)
-		if ruleAIsBetweenFV || ruleBIsBetweenFV {
-			return true, nil
-		}
-
-		if ruleAIsOutsideFV || ruleBIsOutsideFV {
-			return false, nil
-		}
-
-		GEFVRuleA, ruleAIsGEFV := ruleA.(*GEFV)
-		LEFVRuleA, ruleAIsLEFV := ruleA.(*LEFV)
-		GEFVRuleB, ruleBIsGEFV := ruleB.(*GEFV)
-		LEFVRuleB, ruleBIsLEFV := ruleB.(*LEFV)
-
-		if (ruleAIsGEFV && ruleBIsGEFV) || (ruleAIsLEFV && ruleBIsLEFV) {
-			return false, nil
-		}
-
-		if ruleAIsGEFV && ruleBIsLEFV {
-			r, err = NewOutsideFV(fieldA, LEFVRuleB.Value(), GEFVRuleA.Value())
-		} else if ruleAIsLEFV && ruleBIsGEFV {
-			r, err = NewOutsideFV(fieldA, LEFVRuleA.Value(), GEFVRuleB.Value())
-		} else {
-			return true, nil
-		}
-		if err != nil {
-			return false, nil
-		}
-		return false, r
-	}
-	return true, nil
+func (r *Or) Complexity() int {
+	return Complexity(r.ruleA) + Complexity(r.ruleB) + 1
 }
 
-func handleInRules(ruleA, ruleB *InFV) Rule {
-	if ruleA.Fields()[0] != ruleB.Fields()[0] {
-		return &Or{ruleA: ruleA, ruleB: ruleB}
-	}
-	newValues := []*dlit.Literal{}
-	mNewValues := map[string]interface{}{}
-	for _, v := range ruleA.Values() {
-		if _, ok := mNewValues[v.String()]; !ok {
-			mNewValues[v.String()] = nil
-			newValues = append(newValues, v)
-		}
-	}
-	for _, v := range ruleB.Values() {
-		if _, ok := mNewValues[v.String()]; !ok {
-			mNewValues[v.String()] = nil
-			newValues = append(newValues, v)
-		}
-	}
-	return NewInFV(ruleA.Fields()[0], newValues)
+func (r *Or) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return compileLogical(r, decoder, r.ruleA, r.ruleB, false)
 }
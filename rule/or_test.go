@@ -74,12 +74,6 @@ func TestNewOr(t *testing.T) {
 		{ruleA: MustNewOutsideFV("flowA", dlit.MustNew(1.7), dlit.MustNew(5.2)),
 			ruleB: MustNewOutsideFV("flowB", dlit.MustNew(7.3), dlit.MustNew(8.9)),
 		},
-		{ruleA: MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(22.1)),
-			ruleB: MustNewOutsideFV("flow", dlit.MustNew(1.05), dlit.MustNew(17.5)),
-		},
-		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(1.05), dlit.MustNew(17.5)),
-			ruleB: MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(22.1)),
-		},
 	}
 	for _, c := range cases {
 		r, err := NewOr(c.ruleA, c.ruleB)
@@ -114,13 +108,13 @@ func TestNewOr_errors(t *testing.T) {
 			ruleB:      NewGEFV("flow", dlit.MustNew(2.07)),
 			wantErrStr: "can't Or rule: flow >= 1.05, with: flow >= 2.07",
 		},
-		{ruleA: NewGEFV("flow", dlit.MustNew(1.05)),
-			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			wantErrStr: "can't Or rule: flow >= 1.05, with: flow <= 0.7 || flow >= 2.1",
+		{ruleA: MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(22.1)),
+			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(1.05), dlit.MustNew(17.5)),
+			wantErrStr: "can't Or rule: flow >= 0.7 && flow <= 22.1, with: flow <= 1.05 || flow >= 17.5",
 		},
-		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
-			ruleB:      NewGEFV("flow", dlit.MustNew(1.05)),
-			wantErrStr: "can't Or rule: flow <= 0.7 || flow >= 2.1, with: flow >= 1.05",
+		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(1.05), dlit.MustNew(17.5)),
+			ruleB:      MustNewBetweenFV("flow", dlit.MustNew(0.7), dlit.MustNew(22.1)),
+			wantErrStr: "can't Or rule: flow <= 1.05 || flow >= 17.5, with: flow >= 0.7 && flow <= 22.1",
 		},
 		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(1.7), dlit.MustNew(5.2)),
 			ruleB:      MustNewOutsideFV("flow", dlit.MustNew(7.3), dlit.MustNew(8.9)),
@@ -142,6 +136,42 @@ func TestNewOr_errors(t *testing.T) {
 	}
 }
 
+func TestNewOr_joined(t *testing.T) {
+	cases := []struct {
+		ruleA Rule
+		ruleB Rule
+		want  string
+	}{
+		{ruleA: NewGEFV("flow", dlit.MustNew(1.05)),
+			ruleB: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
+			want:  "flow <= 0.7 || flow >= 1.05",
+		},
+		{ruleA: MustNewOutsideFV("flow", dlit.MustNew(0.7), dlit.MustNew(2.1)),
+			ruleB: NewGEFV("flow", dlit.MustNew(1.05)),
+			want:  "flow <= 0.7 || flow >= 1.05",
+		},
+		{ruleA: NewEQFV("team", dlit.MustNew("a")),
+			ruleB: NewEQFV("team", dlit.MustNew("b")),
+			want:  "in(team,\"a\",\"b\")",
+		},
+		{ruleA: NewEQFV("team", dlit.MustNew(1)),
+			ruleB: NewEQFV("team", dlit.MustNew(2)),
+			want:  "team == 1 || team == 2",
+		},
+	}
+	for _, c := range cases {
+		r, err := NewOr(c.ruleA, c.ruleB)
+		if err != nil {
+			t.Errorf("NewOr(%s, %s) got err: %s", c.ruleA, c.ruleB, err)
+			continue
+		}
+		if r.String() != c.want {
+			t.Errorf("NewOr(%s, %s) got: %s, want: %s",
+				c.ruleA, c.ruleB, r, c.want)
+		}
+	}
+}
+
 func TestMustNewOr(t *testing.T) {
 	ruleA := NewEQFF("flow", "rate")
 	ruleB := NewEQFF("income", "cost")
@@ -22,7 +22,7 @@ type OutsideFV struct {
 }
 
 func init() {
-	registerGenerator("OutsideFV", generateOutsideFV)
+	RegisterGenerator("OutsideFV", generateOutsideFV)
 }
 
 func NewOutsideFV(
@@ -98,6 +98,34 @@ func (r *OutsideFV) Fields() []string {
 	return []string{r.field}
 }
 
+func (r *OutsideFV) Complexity() int {
+	return 2
+}
+
+func (r *OutsideFV) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	i, ok := decoder.index[r.field]
+	if !ok {
+		return nil, false
+	}
+	lowInt, lowIsInt := r.low.Int()
+	highInt, highIsInt := r.high.Int()
+	lowFloat, lowIsFloat := r.low.Float()
+	highFloat, highIsFloat := r.high.Float()
+	return func(record *DecodedRecord) (bool, error) {
+		v := &record.values[i]
+		if !v.exists {
+			return false, InvalidRuleError{Rule: r}
+		}
+		if v.isInt && lowIsInt && highIsInt {
+			return v.int <= lowInt || v.int >= highInt, nil
+		}
+		if v.isFloat && lowIsFloat && highIsFloat {
+			return v.float <= lowFloat || v.float >= highFloat, nil
+		}
+		return false, IncompatibleTypesRuleError{Rule: r}
+	}, true
+}
+
 func (r *OutsideFV) Tweak(
 	inputDescription *description.Description,
 	stage int,
@@ -117,14 +145,13 @@ func (r *OutsideFV) Tweak(
 		inputDescription.Fields[r.field].MaxDP,
 		stage,
 	)
-	isValidExpr := dexpr.MustNew("pH > pL", dexprfuncs.CallFuncs)
 	for _, pL := range pointsL {
 		for _, pH := range pointsH {
 			vars := map[string]*dlit.Literal{
 				"pL": pL,
 				"pH": pH,
 			}
-			if ok, err := isValidExpr.EvalBool(vars); ok && err == nil {
+			if ok, err := isValidRangeExpr.EvalBool(vars); ok && err == nil {
 				r := MustNewOutsideFV(r.field, pL, pH)
 				rules = append(rules, r)
 			}
@@ -146,16 +173,24 @@ func generateOutsideFV(
 	inputDescription *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule {
-	rules := make([]Rule, 0)
+	fields := []string{}
 	for _, field := range generationDesc.Fields() {
 		fd := inputDescription.Fields[field]
-		if generationDesc.Deny("OutsideFV", field) ||
-			fd.Kind != description.Number {
-			continue
+		if useField(generationDesc, "OutsideFV", field) &&
+			fd.Kind == description.Number {
+			fields = append(fields, field)
 		}
+	}
+	rules := make([]Rule, 0)
+	budget := newRuleBudget(inputDescription, generationDesc)
+	for _, field := range fields {
+		fd := inputDescription.Fields[field]
 		rulesMap := make(map[string]Rule)
-		points := internal.GeneratePoints(fd.Min, fd.Max, fd.MaxDP)
-		isValidExpr := dexpr.MustNew("pH > pL", dexprfuncs.CallFuncs)
+		points := budget.limitPoints(
+			internal.GeneratePoints(fd.Min, fd.Max, fd.MaxDP),
+			len(fields),
+			numPairs,
+		)
 
 		for _, pL := range points {
 			for _, pH := range points {
@@ -163,11 +198,11 @@ func generateOutsideFV(
 					"pL": pL,
 					"pH": pH,
 				}
-				if ok, err := isValidExpr.EvalBool(vars); ok && err == nil {
+				if ok, err := isValidRangeExpr.EvalBool(vars); ok && err == nil {
 					if r, err := NewOutsideFV(field, pL, pH); err == nil {
 						if _, dup := rulesMap[r.String()]; !dup {
 							rulesMap[r.String()] = r
-							rules = append(rules, r)
+							rules = budget.add(rules, r)
 						}
 					}
 				}
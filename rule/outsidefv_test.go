@@ -195,7 +195,7 @@ func TestOutsideFVTweak(t *testing.T) {
 	)
 	for _, c := range cases {
 		description := &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   c.fdMin,
@@ -277,7 +277,7 @@ func TestGenerateOutsideFV(t *testing.T) {
 		maxDP       int
 	}{
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(500),
@@ -295,7 +295,7 @@ func TestGenerateOutsideFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790.73),
@@ -313,7 +313,7 @@ func TestGenerateOutsideFV(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(790.73),
@@ -332,7 +332,7 @@ func TestGenerateOutsideFV(t *testing.T) {
 			maxDP:       2,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(799),
@@ -350,7 +350,7 @@ func TestGenerateOutsideFV(t *testing.T) {
 			maxDP:       0,
 		},
 		{description: &description.Description{
-			map[string]*description.Field{
+			Fields: map[string]*description.Field{
 				"income": {
 					Kind:  description.Number,
 					Min:   dlit.MustNew(700),
@@ -19,20 +19,53 @@ import (
 
 var (
 	generatorsMu sync.RWMutex
-	generators   = make(map[string]generatorFunc)
+	generators   = make(map[string]GeneratorFunc)
 )
 
-// GenerationDescriber describes what sort of rules should be generated
+// GenerationDescriber describes what sort of rules should be generated.
+// It may also implement GeneratorDisabler, FieldAllower and RuleBudgeter
+// to further control which rules are generated.
 type GenerationDescriber interface {
 	// Fields indicates which fields should be used to generate rules
 	Fields() []string
 	// Arithmetic indicates whether to generate arithmetic rules
 	Arithmetic() bool
 	// Deny indicates whether a field should not be used for a generator.
+	// This takes precedence over FieldAllower.Allow.
 	Deny(generatorName string, field string) bool
 }
 
-type generatorFunc func(
+// GeneratorDisabler is implemented by a GenerationDescriber that can
+// disable generators
+type GeneratorDisabler interface {
+	// Disabled indicates whether a generator should not be used
+	Disabled(generatorName string) bool
+}
+
+// FieldAllower is implemented by a GenerationDescriber that can restrict
+// which fields a generator may use
+type FieldAllower interface {
+	// Allow indicates whether a field may be used for a generator.
+	Allow(generatorName string, field string) bool
+}
+
+// RuleBudgeter is implemented by a GenerationDescriber that can limit
+// the number of rules generated
+type RuleBudgeter interface {
+	// MaxGeneratorRules is the maximum number of rules that each generator
+	// may generate, 0 means no limit
+	MaxGeneratorRules() int
+	// MaxFieldRules is the maximum number of rules that each generator may
+	// generate for a field or combination of fields, 0 means no limit
+	MaxFieldRules() int
+	// MinSupport is the minimum number of records that a generated rule
+	// must be able to match, 0 means no limit
+	MinSupport() int64
+}
+
+// GeneratorFunc generates rules for the fields of a Description
+// as described by a GenerationDescriber
+type GeneratorFunc func(
 	desc *description.Description,
 	generationDesc GenerationDescriber,
 ) []Rule
@@ -59,6 +92,13 @@ type Valuer interface {
 	Value() *dlit.Literal
 }
 
+// Complexer is implemented by rules that can report how complex they
+// are.  The score takes into account the number of clauses, fields,
+// values in a set and any arithmetic, so that simpler rules score lower.
+type Complexer interface {
+	Complexity() int
+}
+
 // Generate generates rules for rules that have registered a generator.
 func Generate(
 	inputDescription *description.Description,
@@ -71,8 +111,15 @@ func Generate(
 	rules := make([]Rule, 1)
 	rules[0] = NewTrue()
 
-	for _, generator := range generators {
-		newRules := generator(inputDescription, generationDesc)
+	for name, generator := range registeredGenerators() {
+		if isGeneratorDisabled(generationDesc, name) {
+			continue
+		}
+		newRules := applyBudget(
+			inputDescription,
+			generationDesc,
+			generator(inputDescription, generationDesc),
+		)
 		rules = append(rules, newRules...)
 	}
 
@@ -80,21 +127,42 @@ func Generate(
 	return Uniq(rules), nil
 }
 
-// Combine combines rules together using And and Or
+// Complexity returns the complexity score of a rule.  If the rule
+// doesn't implement Complexer then the score is based on the number of
+// fields it uses.
+func Complexity(r Rule) int {
+	if c, ok := r.(Complexer); ok {
+		return c.Complexity()
+	}
+	return len(r.Fields()) + 1
+}
+
+// Combine combines rules together using And and Or.  The combined rules
+// are simplified, put into canonical form and any that are always true,
+// can never be true or are equivalent to one of the supplied rules are
+// dropped.
 func Combine(rules []Rule, maxNumRules int) []Rule {
 	Sort(rules)
 	combinedRules := make([]Rule, 0)
 	numRules := len(rules)
+	inRules := make(map[string]bool, numRules)
+	for _, r := range rules {
+		inRules[Canonical(r).String()] = true
+	}
 	for i := 0; i < numRules-1; i++ {
 		for j := i + 1; j < numRules; j++ {
 			if andRule, err := NewAnd(rules[i], rules[j]); err == nil {
-				combinedRules = append(combinedRules, andRule)
+				if r, ok := simplifyCombined(andRule, inRules); ok {
+					combinedRules = append(combinedRules, r)
+				}
 			}
 			if len(combinedRules) >= maxNumRules {
 				break
 			}
 			if orRule, err := NewOr(rules[i], rules[j]); err == nil {
-				combinedRules = append(combinedRules, orRule)
+				if r, ok := simplifyCombined(orRule, inRules); ok {
+					combinedRules = append(combinedRules, r)
+				}
 			}
 			if len(combinedRules) >= maxNumRules {
 				break
@@ -104,7 +172,22 @@ func Combine(rules []Rule, maxNumRules int) []Rule {
 			break
 		}
 	}
-	return Uniq(combinedRules)
+	// The combined rules are already in canonical form
+	return uniqCanonical(combinedRules)
+}
+
+// simplifyCombined simplifies a combined rule, puts it into canonical
+// form and returns whether it is worth keeping
+func simplifyCombined(r Rule, inRules map[string]bool) (Rule, bool) {
+	sr, ok := Simplify(r)
+	if !ok {
+		return nil, false
+	}
+	cr := Canonical(sr)
+	if _, isTrue := cr.(True); isTrue || inRules[cr.String()] {
+		return nil, false
+	}
+	return cr, true
 }
 
 // Sort sorts the rules in place using their .String() method
@@ -112,13 +195,38 @@ func Sort(rules []Rule) {
 	sort.Sort(byString(rules))
 }
 
-// Uniq returns the slices of Rules with duplicates removed
+// Uniq returns the slices of Rules with duplicates removed.  Rules are
+// considered duplicates if they have the same canonical form.
 func Uniq(rules []Rule) []Rule {
 	results := []Rule{}
-	mResults := map[string]interface{}{}
+	seen := map[string]bool{}
+	mResults := map[string]bool{}
+	for _, r := range rules {
+		// Finding the canonical form of an And or Or rule is expensive so
+		// rules that have already been seen are skipped before it is found
+		s := r.String()
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		cs := Canonical(r).String()
+		if !mResults[cs] {
+			mResults[cs] = true
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// uniqCanonical returns the slice of Rules with duplicates removed, where
+// the rules are already in canonical form
+func uniqCanonical(rules []Rule) []Rule {
+	results := []Rule{}
+	mResults := map[string]bool{}
 	for _, r := range rules {
-		if _, ok := mResults[r.String()]; !ok {
-			mResults[r.String()] = nil
+		s := r.String()
+		if !mResults[s] {
+			mResults[s] = true
 			results = append(results, r)
 		}
 	}
@@ -159,15 +267,65 @@ func (rs byString) Less(i, j int) bool {
 	return strings.Compare(rs[i].String(), rs[j].String()) == -1
 }
 
-// registerGenerator makes a rule generator available.
-// If called twice with the same ruleName it panics.
-func registerGenerator(ruleType string, generator generatorFunc) {
+// RegisterGenerator makes a rule generator available to Generate by
+// the provided name.  If RegisterGenerator is called twice with the same
+// name or if generator is nil, it panics.
+func RegisterGenerator(name string, generator GeneratorFunc) {
 	generatorsMu.Lock()
 	defer generatorsMu.Unlock()
-	if _, dup := generators[ruleType]; dup {
-		panic("registerGenerator called twice for ruleType: " + ruleType)
+	if generator == nil {
+		panic("rule.RegisterGenerator generator is nil")
 	}
-	generators[ruleType] = generator
+	if _, dup := generators[name]; dup {
+		panic("rule.RegisterGenerator called twice for generator: " + name)
+	}
+	generators[name] = generator
+}
+
+// UnregisterGenerator removes a rule generator so that it is no longer
+// used by Generate.  It returns whether the generator was registered.
+func UnregisterGenerator(name string) bool {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	if _, ok := generators[name]; !ok {
+		return false
+	}
+	delete(generators, name)
+	return true
+}
+
+// registeredGenerators returns a copy of the registered rule generators
+// so that they can be run without holding generatorsMu
+func registeredGenerators() map[string]GeneratorFunc {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	r := make(map[string]GeneratorFunc, len(generators))
+	for name, generator := range generators {
+		r[name] = generator
+	}
+	return r
+}
+
+// isGeneratorDisabled returns whether a generator has been disabled by
+// a GenerationDescriber that implements GeneratorDisabler
+func isGeneratorDisabled(
+	generationDesc GenerationDescriber,
+	generatorName string,
+) bool {
+	d, ok := generationDesc.(GeneratorDisabler)
+	return ok && d.Disabled(generatorName)
+}
+
+// Generators returns the sorted names of the registered rule generators
+func Generators() []string {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func generateTweakPoints(
@@ -188,10 +346,6 @@ func generateTweakPoints(
 			dexprfuncs.CallFuncs,
 			vars,
 		)
-	validValueExpr := dexpr.MustNew(
-		"rp > min && rp != value && rp < max",
-		dexprfuncs.CallFuncs,
-	)
 	low := dexpr.Eval("max(min, value - step)", dexprfuncs.CallFuncs, vars)
 	high := dexpr.Eval("min(max, value + step)", dexprfuncs.CallFuncs, vars)
 	points := internal.GeneratePoints(low, high, maxDP)
@@ -200,7 +354,7 @@ func generateTweakPoints(
 	for _, p := range points {
 		vars["p"] = p
 		vars["rp"] = internal.RoundLit(p, maxDP)
-		if ok, err := validValueExpr.EvalBool(vars); ok && err == nil {
+		if ok, err := validTweakPointExpr.EvalBool(vars); ok && err == nil {
 			tweakPoints[vars["rp"].String()] = vars["rp"]
 		}
 	}
@@ -208,6 +362,15 @@ func generateTweakPoints(
 	return internal.MapLitNumsToSlice(tweakPoints)
 }
 
+var validTweakPointExpr = dexpr.MustNew(
+	"rp > min && rp != value && rp < max",
+	dexprfuncs.CallFuncs,
+)
+
+// isValidRangeExpr is used to check that a high point, pH, is greater
+// than a low point, pL
+var isValidRangeExpr = dexpr.MustNew("pH > pL", dexprfuncs.CallFuncs)
+
 type makeRoundRule func(*dlit.Literal) Rule
 
 func roundRules(v *dlit.Literal, makeRule makeRoundRule) []Rule {
@@ -234,6 +397,22 @@ func calcNumSharedValues(fd1 *description.Field, fd2 *description.Field) int {
 	return numShared
 }
 
+// redundantCorrelation is the strength of correlation at which a pair of
+// fields are considered to be near duplicates of each other
+const redundantCorrelation = 0.95
+
+// isRedundantPair returns whether a pair of fields are so strongly
+// correlated that rules comparing them are unlikely to be of use.
+// This relies on the Description having been correlated.
+func isRedundantPair(
+	inputDescription *description.Description,
+	fieldA string,
+	fieldB string,
+) bool {
+	c, ok := inputDescription.Correlation(fieldA, fieldB)
+	return ok && c.IsStrong(redundantCorrelation)
+}
+
 var compareExpr *dexpr.Expr = dexpr.MustNew(
 	"min1 < max2 && max1 > min2",
 	dexprfuncs.CallFuncs,
@@ -1,6 +1,7 @@
 package rule
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/lawrencewoodman/dexpr"
@@ -42,12 +43,18 @@ func TestUniq(t *testing.T) {
 		NewGEFV("flow", dlit.MustNew(3)),
 		NewEQFV("band", dlit.MustNew("a")),
 		NewGEFV("flow", dlit.MustNew(2)),
+		MustNewBetweenFV("flow", dlit.MustNew(2), dlit.MustNew(5)),
+		&And{NewLEFV("flow", dlit.MustNew(5)), NewGEFV("flow", dlit.MustNew(2))},
+		&And{NewEQFV("team", dlit.MustNew("a")), NewGEFV("flow", dlit.MustNew(2))},
+		&And{NewGEFV("flow", dlit.MustNew(2)), NewEQFV("team", dlit.MustNew("a"))},
 	}
 	want := []Rule{
 		NewEQFV("band", dlit.MustNew("b")),
 		NewEQFV("band", dlit.MustNew("a")),
 		NewGEFV("flow", dlit.MustNew(3)),
 		NewGEFV("flow", dlit.MustNew(2)),
+		MustNewBetweenFV("flow", dlit.MustNew(2), dlit.MustNew(5)),
+		&And{NewEQFV("team", dlit.MustNew("a")), NewGEFV("flow", dlit.MustNew(2))},
 	}
 	got := Uniq(in)
 	if len(got) != len(want) {
@@ -259,7 +266,7 @@ func TestReduceDP(t *testing.T) {
 
 func TestGenerate(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"team": {
 				Kind: description.String,
 				Values: map[string]description.Value{
@@ -374,7 +381,7 @@ func TestGenerate(t *testing.T) {
 
 func TestGenerate_no_rule_fields(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"team": {
 				Kind: description.String,
 				Values: map[string]description.Value{
@@ -406,7 +413,7 @@ func TestGenerate_no_rule_fields(t *testing.T) {
 
 func TestGenerate_errors(t *testing.T) {
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"directionIn": {
 				Kind: description.String,
 				Values: map[string]description.Value{
@@ -442,6 +449,352 @@ func TestGenerate_errors(t *testing.T) {
 	}
 }
 
+func TestGenerate_disabled(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"team": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"a": {dlit.NewString("a"), 3},
+					"b": {dlit.NewString("b"), 3},
+					"c": {dlit.NewString("c"), 3},
+				},
+				NumValues: 3,
+			},
+		}}
+
+	wantRules := []Rule{
+		NewTrue(),
+		NewEQFV("team", dlit.MustNew("a")),
+		NewEQFV("team", dlit.MustNew("b")),
+		NewEQFV("team", dlit.MustNew("c")),
+	}
+	generationDesc := testhelpers.GenerationDesc{
+		DFields: []string{"team"},
+		DDisabled: []string{
+			"NEFV", "CountEQVF", "CountNEVF", "CountGTVF", "CountLTVF",
+		},
+	}
+	got, err := Generate(inputDescription, generationDesc)
+	if err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+	if err := matchRulesUnordered(got, wantRules); err != nil {
+		t.Errorf("Generate: %s\ngot: %s\nwant: %s", err, got, wantRules)
+	}
+}
+
+func TestGenerate_allow(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"job": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"a": {dlit.NewString("a"), 3},
+					"b": {dlit.NewString("b"), 3},
+					"c": {dlit.NewString("c"), 3},
+				},
+				NumValues: 3,
+			},
+			"town": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"a": {dlit.NewString("a"), 3},
+					"b": {dlit.NewString("b"), 3},
+					"c": {dlit.NewString("c"), 3},
+				},
+				NumValues: 3,
+			},
+		}}
+
+	wantRules := []Rule{
+		NewTrue(),
+		NewEQFV("job", dlit.MustNew("a")),
+		NewEQFV("job", dlit.MustNew("b")),
+		NewEQFV("job", dlit.MustNew("c")),
+		NewNEFV("town", dlit.MustNew("a")),
+		NewNEFV("town", dlit.MustNew("b")),
+		NewNEFV("town", dlit.MustNew("c")),
+	}
+	generationDesc := testhelpers.GenerationDesc{
+		DFields: []string{"job", "town"},
+		DAllow: map[string][]string{
+			"EQFV": []string{"job"},
+			"NEFV": []string{"job", "town"},
+		},
+		DDeny: map[string][]string{"NEFV": []string{"job"}},
+	}
+	got, err := Generate(inputDescription, generationDesc)
+	if err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+	if err := matchRulesUnordered(got, wantRules); err != nil {
+		t.Errorf("Generate: %s\ngot: %s\nwant: %s", err, got, wantRules)
+	}
+}
+
+func TestGenerate_budget(t *testing.T) {
+	generationDesc := testhelpers.GenerationDesc{
+		DFields:            []string{"team", "level"},
+		DMinSupport:        20,
+		DMaxGeneratorRules: 2,
+	}
+	got, err := Generate(budgetDescription, generationDesc)
+	if err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+	numGEFV := 0
+	for _, r := range got {
+		if n, ok := maxSupport(budgetDescription, r); ok && n < 20 {
+			t.Errorf("Generate: rule: %s, support: %d < 20", r, n)
+		}
+		if _, isGEFV := r.(*GEFV); isGEFV {
+			numGEFV++
+		}
+	}
+	if numGEFV == 0 || numGEFV > 2 {
+		t.Errorf("Generate: number of GEFV rules: %d", numGEFV)
+	}
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"team": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"a": {dlit.NewString("a"), 3},
+					"b": {dlit.NewString("b"), 3},
+				},
+				NumValues: 2,
+			},
+		}}
+	generateTeamA := func(
+		desc *description.Description,
+		generationDesc GenerationDescriber,
+	) []Rule {
+		return []Rule{NewEQFV("team", dlit.MustNew("A"))}
+	}
+	wantRule := NewEQFV("team", dlit.MustNew("A"))
+	generationDesc := testhelpers.GenerationDesc{
+		DFields: []string{"team"},
+	}
+
+	RegisterGenerator("TeamA", generateTeamA)
+	defer UnregisterGenerator("TeamA")
+	if !internal.IsStringInSlice("TeamA", Generators()) {
+		t.Errorf("Generators() doesn't contain TeamA: %s", Generators())
+	}
+	got, err := Generate(inputDescription, generationDesc)
+	if err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+	if err := rulesContain(got, []Rule{wantRule}); err != nil {
+		t.Errorf("Generate: %s", err)
+	}
+
+	if !UnregisterGenerator("TeamA") {
+		t.Errorf("UnregisterGenerator(\"TeamA\") got: false, want: true")
+	}
+	if UnregisterGenerator("TeamA") {
+		t.Errorf("UnregisterGenerator(\"TeamA\") got: true, want: false")
+	}
+	if internal.IsStringInSlice("TeamA", Generators()) {
+		t.Errorf("Generators() contains TeamA: %s", Generators())
+	}
+	got, err = Generate(inputDescription, generationDesc)
+	if err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+	if err := rulesContain(got, []Rule{wantRule}); err == nil {
+		t.Errorf("Generate: rules contain: %s", wantRule)
+	}
+}
+
+func TestGenerate_unregisterInGenerator(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"team": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"a": {dlit.NewString("a"), 3},
+					"b": {dlit.NewString("b"), 3},
+				},
+				NumValues: 2,
+			},
+		}}
+	generateOnce := func(
+		desc *description.Description,
+		generationDesc GenerationDescriber,
+	) []Rule {
+		UnregisterGenerator("Once")
+		return []Rule{NewEQFV("team", dlit.MustNew("A"))}
+	}
+	generationDesc := testhelpers.GenerationDesc{
+		DFields: []string{"team"},
+	}
+	RegisterGenerator("Once", generateOnce)
+	defer UnregisterGenerator("Once")
+	if _, err := Generate(inputDescription, generationDesc); err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+	if internal.IsStringInSlice("Once", Generators()) {
+		t.Errorf("Generators() contains Once: %s", Generators())
+	}
+}
+
+// minGenerationDesc only implements the methods required by
+// GenerationDescriber
+type minGenerationDesc struct {
+	fields []string
+}
+
+func (gd minGenerationDesc) Fields() []string { return gd.fields }
+func (gd minGenerationDesc) Arithmetic() bool { return false }
+func (gd minGenerationDesc) Deny(generatorName string, field string) bool {
+	return false
+}
+
+func TestGenerate_minGenerationDesc(t *testing.T) {
+	inputDescription := &description.Description{
+		Fields: map[string]*description.Field{
+			"team": {
+				Kind: description.String,
+				Values: map[string]description.Value{
+					"a": {dlit.NewString("a"), 3},
+					"b": {dlit.NewString("b"), 3},
+				},
+				NumValues: 2,
+			},
+		}}
+	generationDesc := minGenerationDesc{fields: []string{"team"}}
+	got, err := Generate(inputDescription, generationDesc)
+	if err != nil {
+		t.Fatalf("Generate: %s", err)
+	}
+	want := []Rule{
+		NewEQFV("team", dlit.NewString("a")),
+		NewEQFV("team", dlit.NewString("b")),
+	}
+	if err := rulesContain(got, want); err != nil {
+		t.Errorf("Generate: %s", err)
+	}
+}
+
+func TestRegisterGenerator_panic(t *testing.T) {
+	cases := []struct {
+		name      string
+		generator GeneratorFunc
+		wantPanic string
+	}{
+		{name: "EQFV",
+			generator: generateEQFV,
+			wantPanic: "rule.RegisterGenerator called twice for generator: EQFV",
+		},
+		{name: "Nil",
+			generator: nil,
+			wantPanic: "rule.RegisterGenerator generator is nil",
+		},
+	}
+	for _, c := range cases {
+		paniced := false
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					if r.(string) == c.wantPanic {
+						paniced = true
+					} else {
+						t.Errorf("RegisterGenerator: got panic: %s, want: %s",
+							r, c.wantPanic)
+					}
+				}
+			}()
+			RegisterGenerator(c.name, c.generator)
+		}()
+		if !paniced {
+			t.Errorf("RegisterGenerator: failed to panic with: %s", c.wantPanic)
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	want := []string{
+		"AddGEF", "AddLEF", "BetweenFV", "Boolean", "CountEQVF", "CountGTVF",
+	}
+	got := Generators()
+	if len(got) < len(want) {
+		t.Fatalf("Generators() got: %s", got)
+	}
+	for i, g := range want {
+		if got[i] != g {
+			t.Errorf("Generators() got: %s, want prefix: %s", got, want)
+			break
+		}
+	}
+}
+
+func TestComplexity(t *testing.T) {
+	ruleA := NewEQFV("team", dlit.MustNew("a"))
+	ruleB := NewGEFV("level", dlit.MustNew(2))
+	and, err := NewAnd(ruleA, ruleB)
+	if err != nil {
+		t.Fatalf("NewAnd: %s", err)
+	}
+	or, err := NewOr(and, NewLEFF("level", "flow"))
+	if err != nil {
+		t.Fatalf("NewOr: %s", err)
+	}
+	dynamic := MustNewDynamic("team == \"a\" && level > 2")
+	cases := []struct {
+		rule Rule
+		want int
+	}{
+		{rule: NewTrue(), want: 0},
+		{rule: ruleA, want: 1},
+		{rule: NewNEFV("team", dlit.MustNew("a")), want: 1},
+		{rule: ruleB, want: 1},
+		{rule: NewLEFV("level", dlit.MustNew(2)), want: 1},
+		{rule: NewEQFF("team", "teamOut"), want: 2},
+		{rule: NewNEFF("team", "teamOut"), want: 2},
+		{rule: NewGEFF("level", "flow"), want: 2},
+		{rule: NewGTFF("level", "flow"), want: 2},
+		{rule: NewLEFF("level", "flow"), want: 2},
+		{rule: NewLTFF("level", "flow"), want: 2},
+		{rule: MustNewBetweenFV("level", dlit.MustNew(1), dlit.MustNew(3)),
+			want: 2},
+		{rule: MustNewOutsideFV("level", dlit.MustNew(1), dlit.MustNew(3)),
+			want: 2},
+		{rule: NewInFV("team", testhelpers.MakeStringsDlitSlice("a", "b", "c")),
+			want: 3},
+		{rule: NewAddGEF("level", "flow", dlit.MustNew(5)), want: 3},
+		{rule: NewAddLEF("level", "flow", dlit.MustNew(5)), want: 3},
+		{rule: NewMulGEF("level", "flow", dlit.MustNew(5)), want: 3},
+		{rule: NewMulLEF("level", "flow", dlit.MustNew(5)), want: 3},
+		{rule: NewCountEQVF(dlit.MustNew("yes"), []string{"a", "b"}, 1),
+			want: 3},
+		{rule: NewCountNEVF(dlit.MustNew("yes"), []string{"a", "b", "c"}, 1),
+			want: 4},
+		{rule: NewCountGTVF(dlit.MustNew("yes"), []string{"a", "b"}, 1),
+			want: 3},
+		{rule: NewCountLTVF(dlit.MustNew("yes"), []string{"a", "b"}, 1),
+			want: 3},
+		{rule: and, want: 3},
+		{rule: or, want: 6},
+		{rule: dynamic, want: 3},
+		{rule: MustNewDynamic("team == \"a && b || c\""), want: 1},
+		{rule: MustNewDynamic("level == flow"), want: 2},
+		{rule: MustNewDynamic("level + flow >= 5"), want: 3},
+		{rule: MustNewDynamic("in(team, \"a\", \"b\", \"c\")"), want: 3},
+		{rule: MustNewDynamic("!(level > 2 || (flow < 3))"), want: 3},
+	}
+	for _, c := range cases {
+		got := Complexity(c.rule)
+		if got != c.want {
+			t.Errorf("Complexity(%s) got: %d, want: %d", c.rule, got, c.want)
+		}
+	}
+}
+
 func TestCombine(t *testing.T) {
 	cases := []struct {
 		inRules       []Rule
@@ -462,7 +815,7 @@ func TestCombine(t *testing.T) {
 					NewGEFV("band", dlit.MustNew(4)),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 				),
 				MustNewAnd(
@@ -473,7 +826,7 @@ func TestCombine(t *testing.T) {
 					NewGEFV("band", dlit.MustNew(4)),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 				),
 				MustNewOr(
@@ -484,14 +837,14 @@ func TestCombine(t *testing.T) {
 					NewEQFV("group", dlit.MustNew("a")),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 				),
 				MustNewOr(
 					NewEQFV("group", dlit.MustNew("a")),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 				),
 			},
@@ -556,11 +909,12 @@ func TestCombine(t *testing.T) {
 		},
 			inMaxNumRules: 100,
 			want: []Rule{
+				NewEQFV("team", dlit.MustNew("blue")),
 				NewInFV(
 					"team",
 					testhelpers.MakeStringsDlitSlice(
-						"pink", "yellow", "blue",
-						"red", "green",
+						"blue", "green", "pink",
+						"red", "yellow",
 					),
 				),
 			},
@@ -580,21 +934,21 @@ func TestCombine(t *testing.T) {
 				MustNewAnd(
 					NewInFV(
 						"group",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("pink", "yellow", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "pink", "yellow"),
 					),
 				),
 				MustNewOr(
 					NewInFV(
 						"group",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("pink", "yellow", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "pink", "yellow"),
 					),
 				),
 			},
@@ -614,11 +968,11 @@ func TestCombine(t *testing.T) {
 				MustNewAnd(
 					NewInFV(
 						"group",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("pink", "yellow", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "pink", "yellow"),
 					),
 				),
 			},
@@ -638,11 +992,11 @@ func TestCombine(t *testing.T) {
 				MustNewAnd(
 					NewInFV(
 						"group",
-						testhelpers.MakeStringsDlitSlice("red", "green", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "green", "red"),
 					),
 					NewInFV(
 						"team",
-						testhelpers.MakeStringsDlitSlice("pink", "yellow", "blue"),
+						testhelpers.MakeStringsDlitSlice("blue", "pink", "yellow"),
 					),
 				),
 			},
@@ -733,3 +1087,22 @@ func TestStringCombinations(t *testing.T) {
 		}
 	}
 }
+
+func BenchmarkUniq(b *testing.B) {
+	b.StopTimer()
+	rules := []Rule{}
+	for i := 0; i < 20; i++ {
+		rules = append(rules,
+			NewGEFV("flow", dlit.MustNew(i)),
+			NewLEFV("level", dlit.MustNew(i)),
+			NewEQFV("team", dlit.MustNew(fmt.Sprintf("t%d", i))),
+		)
+	}
+	combined := Combine(rules, 1000)
+	rules = append(rules, combined...)
+	rules = append(rules, combined...)
+	b.StartTimer()
+	for n := 0; n < b.N; n++ {
+		Uniq(rules)
+	}
+}
@@ -0,0 +1,543 @@
+// Copyright (C) 2018 vLife Systems Ltd <http://vlifesystems.com>
+// Licensed under an MIT licence.  Please see LICENSE.md for details.
+
+package rule
+
+import (
+	"github.com/lawrencewoodman/dlit"
+)
+
+// Simplify returns a logically equivalent, but simpler, version of a rule.
+// The clauses of And and Or rules are compared for each field, using the
+// intervals and sets of values that they allow, so that redundant clauses
+// are removed and clauses that can be joined are joined.  If the rule is
+// always true then True is returned.  If the rule can never be true then
+// the second return value is false.
+func Simplify(r Rule) (Rule, bool) {
+	switch r.(type) {
+	case *And:
+		return simplifyClauses(r, true)
+	case *Or:
+		return simplifyClauses(r, false)
+	}
+	return r, true
+}
+
+// mergeResult describes the outcome of trying to merge two clauses
+type mergeResult int
+
+const (
+	notMerged mergeResult = iota
+	merged
+	// mergedNever indicates that the clauses can never be true together
+	mergedNever
+	// mergedAlways indicates that at least one of the clauses is always true
+	mergedAlways
+)
+
+func simplifyClauses(r Rule, isAnd bool) (Rule, bool) {
+	isChanged := false
+	clauses := []Rule{}
+	for _, c := range flattenClauses(r, isAnd) {
+		sc, ok := Simplify(c)
+		if sc != c {
+			isChanged = true
+		}
+		if !ok {
+			if isAnd {
+				return nil, false
+			}
+			isChanged = true
+			continue
+		}
+		if _, isTrue := sc.(True); isTrue {
+			if !isAnd {
+				return NewTrue(), true
+			}
+			isChanged = true
+			continue
+		}
+		clauses = append(clauses, flattenClauses(sc, isAnd)...)
+	}
+
+	mergeFunc := mergeOrClauses
+	if isAnd {
+		mergeFunc = mergeAndClauses
+	}
+	for i := 0; i < len(clauses)-1; i++ {
+		for j := i + 1; j < len(clauses); j++ {
+			if !isSameSingleField(clauses[i], clauses[j]) {
+				continue
+			}
+			mr, result := mergeFunc(clauses[i], clauses[j])
+			switch result {
+			case notMerged:
+				continue
+			case mergedNever:
+				return nil, false
+			case mergedAlways:
+				return NewTrue(), true
+			}
+			isChanged = true
+			clauses[i] = mr
+			clauses = append(clauses[:j], clauses[j+1:]...)
+			// Start again as the merged clause may now merge with others
+			i = -1
+			break
+		}
+	}
+
+	if !isChanged {
+		return r, true
+	}
+	if len(clauses) == 0 {
+		if isAnd {
+			return NewTrue(), true
+		}
+		return nil, false
+	}
+	newRule := clauses[0]
+	for _, c := range clauses[1:] {
+		var err error
+		if isAnd {
+			newRule, err = NewAnd(newRule, c)
+		} else {
+			newRule, err = NewOr(newRule, c)
+		}
+		if err != nil {
+			return r, true
+		}
+	}
+	return newRule, true
+}
+
+// flattenClauses returns the clauses of nested And rules if isAnd is
+// true or the clauses of nested Or rules if isAnd is false
+func flattenClauses(r Rule, isAnd bool) []Rule {
+	switch x := r.(type) {
+	case *And:
+		if isAnd {
+			return append(flattenClauses(x.ruleA, isAnd),
+				flattenClauses(x.ruleB, isAnd)...)
+		}
+	case *Or:
+		if !isAnd {
+			return append(flattenClauses(x.ruleA, isAnd),
+				flattenClauses(x.ruleB, isAnd)...)
+		}
+	}
+	return []Rule{r}
+}
+
+func isSameSingleField(ruleA, ruleB Rule) bool {
+	fieldsA := ruleA.Fields()
+	fieldsB := ruleB.Fields()
+	return len(fieldsA) == 1 && len(fieldsB) == 1 && fieldsA[0] == fieldsB[0]
+}
+
+// mergeAndClauses tries to merge two clauses, for the same field,
+// of an And rule
+func mergeAndClauses(ruleA, ruleB Rule) (Rule, mergeResult) {
+	if ruleA.String() == ruleB.String() {
+		return ruleA, merged
+	}
+	field := ruleA.Fields()[0]
+	if eq, other, ok := eqfvAndOther(ruleA, ruleB); ok &&
+		isComparableWith(eq.value, other) {
+		isTrue, err := isTrueForValue(other, field, eq.value)
+		if err != nil {
+			return nil, notMerged
+		}
+		if isTrue {
+			return eq, merged
+		}
+		return nil, mergedNever
+	}
+	if in, other, ok := infvAndOther(ruleA, ruleB); ok {
+		values := []*dlit.Literal{}
+		for _, v := range in.values {
+			isTrue, err := isTrueForValue(other, field, v)
+			if err != nil {
+				return nil, notMerged
+			}
+			if isTrue {
+				values = append(values, v)
+			}
+		}
+		switch len(values) {
+		case 0:
+			return nil, mergedNever
+		case 1:
+			if !isNumberValue(values[0]) {
+				return NewEQFV(field, values[0]), merged
+			}
+		}
+		return NewInFV(field, values), merged
+	}
+
+	ivA, ruleAIsInterval := toInterval(ruleA)
+	ivB, ruleBIsInterval := toInterval(ruleB)
+	if ruleAIsInterval && ruleBIsInterval {
+		iv, ok := ivA.intersect(ivB)
+		if !ok {
+			return nil, notMerged
+		}
+		return iv.toRule(field)
+	}
+
+	out, iv, ok := outsideAndInterval(ruleA, ruleB)
+	if !ok {
+		return nil, notMerged
+	}
+	lowPart, lowOK := iv.intersect(interval{max: out.low})
+	highPart, highOK := iv.intersect(interval{min: out.high})
+	if !lowOK || !highOK {
+		return nil, notMerged
+	}
+	lowIsEmpty, lowOK := lowPart.isEmpty()
+	highIsEmpty, highOK := highPart.isEmpty()
+	if !lowOK || !highOK {
+		return nil, notMerged
+	}
+	switch {
+	case lowIsEmpty && highIsEmpty:
+		return nil, mergedNever
+	case lowIsEmpty:
+		return highPart.toRule(field)
+	case highIsEmpty:
+		return lowPart.toRule(field)
+	}
+	return nil, notMerged
+}
+
+// mergeOrClauses tries to merge two clauses, for the same field,
+// of an Or rule
+func mergeOrClauses(ruleA, ruleB Rule) (Rule, mergeResult) {
+	if ruleA.String() == ruleB.String() {
+		return ruleA, merged
+	}
+	field := ruleA.Fields()[0]
+	if ne, other, ok := nefvAndOther(ruleA, ruleB); ok &&
+		isComparableWith(ne.value, other) {
+		isTrue, err := isTrueForValue(other, field, ne.value)
+		if err != nil {
+			return nil, notMerged
+		}
+		if isTrue {
+			return nil, mergedAlways
+		}
+		return ne, merged
+	}
+	if eq, other, ok := eqfvAndOther(ruleA, ruleB); ok &&
+		isComparableWith(eq.value, other) {
+		isTrue, err := isTrueForValue(other, field, eq.value)
+		if err != nil {
+			return nil, notMerged
+		}
+		if isTrue {
+			return other, merged
+		}
+		switch x := other.(type) {
+		case *EQFV:
+			if isNumberValue(eq.value) || isNumberValue(x.value) {
+				return nil, notMerged
+			}
+			return NewInFV(field, []*dlit.Literal{eq.value, x.value}), merged
+		case *InFV:
+			values := append([]*dlit.Literal{}, x.values...)
+			return NewInFV(field, append(values, eq.value)), merged
+		}
+		return nil, notMerged
+	}
+	if in, other, ok := infvAndOther(ruleA, ruleB); ok {
+		if inOther, ok := other.(*InFV); ok {
+			return unionInFV(in, inOther), merged
+		}
+		for _, v := range in.values {
+			isTrue, err := isTrueForValue(other, field, v)
+			if err != nil || !isTrue {
+				return nil, notMerged
+			}
+		}
+		return other, merged
+	}
+
+	ivA, ruleAIsInterval := toInterval(ruleA)
+	ivB, ruleBIsInterval := toInterval(ruleB)
+	if ruleAIsInterval && ruleBIsInterval {
+		return unionIntervals(field, ivA, ivB)
+	}
+
+	outA, ruleAIsOutside := ruleA.(*OutsideFV)
+	outB, ruleBIsOutside := ruleB.(*OutsideFV)
+	if ruleAIsOutside && ruleBIsOutside {
+		return makeOutside(
+			field,
+			maxLiteral(outA.low, outB.low),
+			minLiteral(outA.high, outB.high),
+		)
+	}
+
+	out, iv, ok := outsideAndInterval(ruleA, ruleB)
+	if !ok {
+		return nil, notMerged
+	}
+	// The interval is joined to the side of the Outside rule that it
+	// touches, if it touches neither then it can't be joined
+	if c, ok := compareLiterals(iv.min, out.low); iv.min == nil || ok && c <= 0 {
+		if iv.max == nil {
+			return nil, mergedAlways
+		}
+		return makeOutside(field, maxLiteral(out.low, iv.max), out.high)
+	}
+	if c, ok := compareLiterals(iv.max, out.high); iv.max == nil || ok && c >= 0 {
+		return makeOutside(field, out.low, minLiteral(out.high, iv.min))
+	}
+	return nil, notMerged
+}
+
+// interval represents the values: min <= x <= max, where a nil min or max
+// is unbounded
+type interval struct {
+	min *dlit.Literal
+	max *dlit.Literal
+}
+
+func toInterval(r Rule) (interval, bool) {
+	switch x := r.(type) {
+	case *GEFV:
+		return interval{min: x.value}, true
+	case *LEFV:
+		return interval{max: x.value}, true
+	case *BetweenFV:
+		return interval{min: x.min, max: x.max}, true
+	}
+	return interval{}, false
+}
+
+func (iv interval) intersect(o interval) (interval, bool) {
+	r := interval{min: iv.min, max: iv.max}
+	if o.min != nil {
+		if r.min == nil {
+			r.min = o.min
+		} else if c, ok := compareLiterals(o.min, r.min); !ok {
+			return interval{}, false
+		} else if c > 0 {
+			r.min = o.min
+		}
+	}
+	if o.max != nil {
+		if r.max == nil {
+			r.max = o.max
+		} else if c, ok := compareLiterals(o.max, r.max); !ok {
+			return interval{}, false
+		} else if c < 0 {
+			r.max = o.max
+		}
+	}
+	return r, true
+}
+
+func (iv interval) isEmpty() (bool, bool) {
+	if iv.min == nil || iv.max == nil {
+		return false, true
+	}
+	c, ok := compareLiterals(iv.min, iv.max)
+	return c > 0, ok
+}
+
+func (iv interval) toRule(field string) (Rule, mergeResult) {
+	switch {
+	case iv.min == nil && iv.max == nil:
+		return nil, mergedAlways
+	case iv.min == nil:
+		return NewLEFV(field, iv.max), merged
+	case iv.max == nil:
+		return NewGEFV(field, iv.min), merged
+	}
+	c, ok := compareLiterals(iv.min, iv.max)
+	if !ok {
+		return nil, notMerged
+	}
+	if c > 0 {
+		return nil, mergedNever
+	}
+	r, err := NewBetweenFV(field, iv.min, iv.max)
+	if err != nil {
+		return nil, notMerged
+	}
+	return r, merged
+}
+
+// unionIntervals joins two intervals if they overlap, or makes an
+// OutsideFV rule if they are both unbounded on opposite sides
+func unionIntervals(field string, ivA, ivB interval) (Rule, mergeResult) {
+	iv, ok := ivA.intersect(ivB)
+	if !ok {
+		return nil, notMerged
+	}
+	isEmpty, ok := iv.isEmpty()
+	if !ok {
+		return nil, notMerged
+	}
+	if !isEmpty {
+		union := interval{}
+		if ivA.min != nil && ivB.min != nil {
+			union.min = minLiteral(ivA.min, ivB.min)
+		}
+		if ivA.max != nil && ivB.max != nil {
+			union.max = maxLiteral(ivA.max, ivB.max)
+		}
+		return union.toRule(field)
+	}
+	if ivA.min == nil && ivB.max == nil {
+		return makeOutside(field, ivA.max, ivB.min)
+	}
+	if ivA.max == nil && ivB.min == nil {
+		return makeOutside(field, ivB.max, ivA.min)
+	}
+	return nil, notMerged
+}
+
+func makeOutside(
+	field string,
+	low *dlit.Literal,
+	high *dlit.Literal,
+) (Rule, mergeResult) {
+	c, ok := compareLiterals(low, high)
+	if !ok {
+		return nil, notMerged
+	}
+	if c >= 0 {
+		return nil, mergedAlways
+	}
+	r, err := NewOutsideFV(field, low, high)
+	if err != nil {
+		return nil, notMerged
+	}
+	return r, merged
+}
+
+func eqfvAndOther(ruleA, ruleB Rule) (*EQFV, Rule, bool) {
+	if eq, ok := ruleA.(*EQFV); ok {
+		return eq, ruleB, true
+	}
+	if eq, ok := ruleB.(*EQFV); ok {
+		return eq, ruleA, true
+	}
+	return nil, nil, false
+}
+
+func nefvAndOther(ruleA, ruleB Rule) (*NEFV, Rule, bool) {
+	if ne, ok := ruleA.(*NEFV); ok {
+		return ne, ruleB, true
+	}
+	if ne, ok := ruleB.(*NEFV); ok {
+		return ne, ruleA, true
+	}
+	return nil, nil, false
+}
+
+func infvAndOther(ruleA, ruleB Rule) (*InFV, Rule, bool) {
+	if in, ok := ruleA.(*InFV); ok {
+		return in, ruleB, true
+	}
+	if in, ok := ruleB.(*InFV); ok {
+		return in, ruleA, true
+	}
+	return nil, nil, false
+}
+
+func outsideAndInterval(ruleA, ruleB Rule) (*OutsideFV, interval, bool) {
+	if out, ok := ruleA.(*OutsideFV); ok {
+		iv, ok := toInterval(ruleB)
+		return out, iv, ok
+	}
+	if out, ok := ruleB.(*OutsideFV); ok {
+		iv, ok := toInterval(ruleA)
+		return out, iv, ok
+	}
+	return nil, interval{}, false
+}
+
+// unionInFV returns an InFV rule with the values of both rules, which
+// must be for the same field
+func unionInFV(ruleA, ruleB *InFV) Rule {
+	newValues := []*dlit.Literal{}
+	mNewValues := map[string]interface{}{}
+	for _, v := range append(ruleA.Values(), ruleB.Values()...) {
+		if _, ok := mNewValues[v.String()]; !ok {
+			mNewValues[v.String()] = nil
+			newValues = append(newValues, v)
+		}
+	}
+	return NewInFV(ruleA.Fields()[0], newValues)
+}
+
+// isNumberValue returns whether v is a number, which EQFV and NEFV
+// compare numerically but InFV compares as a string
+func isNumberValue(v *dlit.Literal) bool {
+	_, isFloat := v.Float()
+	return isFloat
+}
+
+// isComparableWith returns whether the value of an EQFV or NEFV rule
+// can be tested against other to merge them.  This isn't the case for a
+// number and an InFV rule because "30.0" would equal 30 for the former
+// but not be in ("30") for the latter.
+func isComparableWith(v *dlit.Literal, other Rule) bool {
+	if _, isIn := other.(*InFV); isIn {
+		return !isNumberValue(v)
+	}
+	return true
+}
+
+func isTrueForValue(r Rule, field string, v *dlit.Literal) (bool, error) {
+	return r.IsTrue(map[string]*dlit.Literal{field: v})
+}
+
+// compareLiterals returns -1, 0 or 1 depending on whether l1 is less than,
+// equal to or greater than l2.  The second return value is false if the
+// literals can't be compared as numbers.
+func compareLiterals(l1, l2 *dlit.Literal) (int, bool) {
+	if l1 == nil || l2 == nil {
+		return 0, false
+	}
+	if i1, l1IsInt := l1.Int(); l1IsInt {
+		if i2, l2IsInt := l2.Int(); l2IsInt {
+			switch {
+			case i1 < i2:
+				return -1, true
+			case i1 > i2:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	if f1, l1IsFloat := l1.Float(); l1IsFloat {
+		if f2, l2IsFloat := l2.Float(); l2IsFloat {
+			switch {
+			case f1 < f2:
+				return -1, true
+			case f1 > f2:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+func minLiteral(l1, l2 *dlit.Literal) *dlit.Literal {
+	if c, ok := compareLiterals(l1, l2); ok && c > 0 {
+		return l2
+	}
+	return l1
+}
+
+func maxLiteral(l1, l2 *dlit.Literal) *dlit.Literal {
+	if c, ok := compareLiterals(l1, l2); ok && c < 0 {
+		return l2
+	}
+	return l1
+}
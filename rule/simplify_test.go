@@ -0,0 +1,150 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/lawrencewoodman/dlit"
+	"github.com/vlifesystems/rhkit/internal/testhelpers"
+)
+
+func TestSimplify(t *testing.T) {
+	ageGE30 := NewGEFV("age", dlit.MustNew(30))
+	ageGE40 := NewGEFV("age", dlit.MustNew(40))
+	ageLE20 := NewLEFV("age", dlit.MustNew(20))
+	ageLE35 := NewLEFV("age", dlit.MustNew(35))
+	ageLE50 := NewLEFV("age", dlit.MustNew(50))
+	age20To50 := MustNewBetweenFV("age", dlit.MustNew(20), dlit.MustNew(50))
+	ageOut20To50 := MustNewOutsideFV("age", dlit.MustNew(20), dlit.MustNew(50))
+	jobA := NewEQFV("job", dlit.MustNew("a"))
+	jobB := NewEQFV("job", dlit.MustNew("b"))
+	jobNotA := NewNEFV("job", dlit.MustNew("a"))
+	jobNotC := NewNEFV("job", dlit.MustNew("c"))
+	jobInAB := NewInFV("job", testhelpers.MakeStringsDlitSlice("a", "b"))
+	jobInBC := NewInFV("job", testhelpers.MakeStringsDlitSlice("b", "c"))
+	townX := NewEQFV("town", dlit.MustNew("x"))
+	cases := []struct {
+		in     Rule
+		want   string
+		wantOK bool
+	}{
+		{in: jobA, want: "job == \"a\"", wantOK: true},
+		{in: &And{ageGE30, townX},
+			want:   "age >= 30 && town == \"x\"",
+			wantOK: true,
+		},
+		{in: &And{ageGE30, ageGE40}, want: "age >= 40", wantOK: true},
+		{in: &And{ageLE35, ageLE50}, want: "age <= 35", wantOK: true},
+		{in: &And{ageGE30, ageLE35}, want: "age >= 30 && age <= 35", wantOK: true},
+		{in: &And{ageGE40, ageLE35}, wantOK: false},
+		{in: &And{ageGE30, NewLEFV("age", dlit.MustNew(30))},
+			want:   "age >= 30 && age <= 30",
+			wantOK: true,
+		},
+		{in: &And{age20To50, ageGE40}, want: "age >= 40 && age <= 50", wantOK: true},
+		{in: &And{ageOut20To50, ageGE30}, want: "age >= 50", wantOK: true},
+		{in: &And{ageOut20To50, ageLE35}, want: "age <= 20", wantOK: true},
+		{in: &And{ageOut20To50, MustNewBetweenFV("age", dlit.MustNew(25), dlit.MustNew(45))},
+			wantOK: false,
+		},
+		{in: &And{jobA, jobB}, wantOK: false},
+		{in: &And{jobA, jobInAB}, want: "job == \"a\"", wantOK: true},
+		{in: &And{jobInAB, jobInBC}, want: "job == \"b\"", wantOK: true},
+		{in: &And{jobInAB, jobNotC}, want: "in(job,\"a\",\"b\")", wantOK: true},
+		{in: &And{jobA, jobNotA}, wantOK: false},
+		{in: &And{&And{ageGE30, townX}, ageGE40},
+			want:   "age >= 40 && town == \"x\"",
+			wantOK: true,
+		},
+		{in: &And{&And{ageGE30, townX}, &And{jobA, jobB}}, wantOK: false},
+		{in: &Or{ageGE30, ageGE40}, want: "age >= 30", wantOK: true},
+		{in: &Or{ageGE30, ageLE50}, want: "true()", wantOK: true},
+		{in: &Or{ageGE40, ageLE20}, want: "age <= 20 || age >= 40", wantOK: true},
+		{in: &Or{age20To50, ageGE40}, want: "age >= 20", wantOK: true},
+		{in: &Or{ageOut20To50, ageLE35}, want: "age <= 35 || age >= 50",
+			wantOK: true,
+		},
+		{in: &Or{ageOut20To50, age20To50}, want: "true()", wantOK: true},
+		{in: &Or{jobInAB, jobA}, want: "in(job,\"a\",\"b\")", wantOK: true},
+		{in: &Or{jobA, jobB}, want: "in(job,\"a\",\"b\")", wantOK: true},
+		{in: &Or{NewEQFV("age", dlit.MustNew(30)), NewEQFV("age", dlit.MustNew(40))},
+			want:   "age == 30 || age == 40",
+			wantOK: true,
+		},
+		{in: &Or{
+			NewEQFV("age", dlit.MustNew(30)),
+			NewInFV("age", testhelpers.MakeStringsDlitSlice("30", "40")),
+		},
+			want:   "age == 30 || in(age,\"30\",\"40\")",
+			wantOK: true,
+		},
+		{in: &Or{jobA, jobNotA}, want: "true()", wantOK: true},
+		{in: &Or{jobNotA, jobNotC}, want: "true()", wantOK: true},
+		{in: &Or{jobNotA, jobInBC}, want: "job != \"a\"", wantOK: true},
+		{in: &Or{jobNotA, jobInAB}, want: "true()", wantOK: true},
+		{in: &Or{&And{ageGE40, ageLE35}, townX}, want: "town == \"x\"",
+			wantOK: true,
+		},
+		{in: &Or{&And{ageGE30, ageGE40}, townX},
+			want:   "age >= 40 || town == \"x\"",
+			wantOK: true,
+		},
+		{in: &Or{&Or{jobA, townX}, jobB},
+			want:   "in(job,\"a\",\"b\") || town == \"x\"",
+			wantOK: true,
+		},
+	}
+	for i, c := range cases {
+		got, ok := Simplify(c.in)
+		if ok != c.wantOK {
+			t.Errorf("(%d) Simplify(%s) got ok: %t, want: %t", i, c.in, ok, c.wantOK)
+			continue
+		}
+		if ok && got.String() != c.want {
+			t.Errorf("(%d) Simplify(%s) got: %s, want: %s", i, c.in, got, c.want)
+		}
+	}
+}
+
+func TestSimplify_equivalent(t *testing.T) {
+	rules := []Rule{
+		&And{NewGEFV("age", dlit.MustNew(30)), NewGEFV("age", dlit.MustNew(40))},
+		&Or{NewGEFV("age", dlit.MustNew(40)), NewLEFV("age", dlit.MustNew(20))},
+		&And{
+			MustNewOutsideFV("age", dlit.MustNew(20), dlit.MustNew(50)),
+			NewGEFV("age", dlit.MustNew(30)),
+		},
+		&Or{
+			NewInFV("job", testhelpers.MakeStringsDlitSlice("a", "b")),
+			NewNEFV("job", dlit.MustNew("a")),
+		},
+	}
+	ages := []int{0, 19, 20, 21, 29, 30, 31, 39, 40, 41, 49, 50, 51, 100}
+	jobs := []string{"a", "b", "c"}
+	for _, r := range rules {
+		sr, ok := Simplify(r)
+		if !ok {
+			t.Errorf("Simplify(%s) got ok: false", r)
+			continue
+		}
+		for _, age := range ages {
+			for _, job := range jobs {
+				record := map[string]*dlit.Literal{
+					"age": dlit.MustNew(age),
+					"job": dlit.MustNew(job),
+				}
+				want, err := r.IsTrue(record)
+				if err != nil {
+					t.Fatalf("IsTrue: %s", err)
+				}
+				got, err := sr.IsTrue(record)
+				if err != nil {
+					t.Fatalf("IsTrue: %s", err)
+				}
+				if got != want {
+					t.Errorf("Simplify(%s) got: %s, IsTrue(%v) got: %t, want: %t",
+						r, sr, record, got, want)
+				}
+			}
+		}
+	}
+}
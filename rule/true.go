@@ -26,3 +26,13 @@ func (r True) IsTrue(record ddataset.Record) (bool, error) {
 func (r True) Fields() []string {
 	return []string{}
 }
+
+func (r True) Complexity() int {
+	return 0
+}
+
+func (r True) Compile(decoder *RecordDecoder) (CompiledRule, bool) {
+	return func(record *DecodedRecord) (bool, error) {
+		return true, nil
+	}, true
+}
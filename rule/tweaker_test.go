@@ -11,7 +11,7 @@ import (
 func TestTweak_1(t *testing.T) {
 	testPurposes := []string{"Ensure that results are only from tweakable rules"}
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				description.Number, dlit.MustNew(3), dlit.MustNew(40), 0,
 				map[string]description.Value{}, 0},
@@ -103,7 +103,7 @@ func TestTweak_2(t *testing.T) {
 		"Ensure that generates a range of int numbers between current ones",
 	}
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"age": {
 				description.Number, dlit.MustNew(10), dlit.MustNew(80), 0,
 				map[string]description.Value{}, 0,
@@ -185,7 +185,7 @@ func TestTweak_3(t *testing.T) {
 		"Ensure that decimal places are no greater than maxDP for field",
 	}
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"flow": {
 				Kind:      description.Number,
 				Min:       dlit.MustNew(10),
@@ -294,7 +294,7 @@ func TestTweak_4(t *testing.T) {
 		"Ensure that generates a True rule",
 	}
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"flow": {
 				description.Number, dlit.MustNew(4), dlit.MustNew(30), 6,
 				map[string]description.Value{}, 0,
@@ -324,7 +324,7 @@ func TestTweak_4(t *testing.T) {
 func TestTweak_5(t *testing.T) {
 	testPurposes := []string{"Ensure that are rules are unique"}
 	inputDescription := &description.Description{
-		map[string]*description.Field{
+		Fields: map[string]*description.Field{
 			"band": {
 				description.Number, dlit.MustNew(3), dlit.MustNew(40), 0,
 				map[string]description.Value{}, 0},